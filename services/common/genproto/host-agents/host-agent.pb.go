@@ -21,6 +21,311 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// UpdateStrategy is how an update replaces the old container.
+type UpdateStrategy int32
+
+const (
+	UpdateStrategy_STOP_FIRST  UpdateStrategy = 0 // stop the old container, then start the new one
+	UpdateStrategy_START_FIRST UpdateStrategy = 1 // start the new container next to the old one, then stop the old one
+)
+
+// Enum value maps for UpdateStrategy.
+var (
+	UpdateStrategy_name = map[int32]string{
+		0: "STOP_FIRST",
+		1: "START_FIRST",
+	}
+	UpdateStrategy_value = map[string]int32{
+		"STOP_FIRST":  0,
+		"START_FIRST": 1,
+	}
+)
+
+func (x UpdateStrategy) Enum() *UpdateStrategy {
+	p := new(UpdateStrategy)
+	*p = x
+	return p
+}
+
+func (x UpdateStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UpdateStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_host_agent_proto_enumTypes[0].Descriptor()
+}
+
+func (UpdateStrategy) Type() protoreflect.EnumType {
+	return &file_host_agent_proto_enumTypes[0]
+}
+
+func (x UpdateStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UpdateStrategy.Descriptor instead.
+func (UpdateStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{0}
+}
+
+// Feature is an optional capability negotiated in the stream handshake.
+type Feature int32
+
+const (
+	Feature_FEATURE_UNSPECIFIED   Feature = 0
+	Feature_FEATURE_HEALTH_CHECKS Feature = 1 // gates COMPLETED on UpdateContainerCommand.health_check
+	Feature_FEATURE_HOOKS         Feature = 2 // runs UpdateContainerCommand.hooks
+	Feature_FEATURE_COMPOSE       Feature = 3 // reports compose projects and writes updated tags back
+	Feature_FEATURE_START_FIRST   Feature = 4 // starts the new container before stopping the old one
+	Feature_FEATURE_CANCEL        Feature = 5 // aborts updates on CancelUpdate
+)
+
+// Enum value maps for Feature.
+var (
+	Feature_name = map[int32]string{
+		0: "FEATURE_UNSPECIFIED",
+		1: "FEATURE_HEALTH_CHECKS",
+		2: "FEATURE_HOOKS",
+		3: "FEATURE_COMPOSE",
+		4: "FEATURE_START_FIRST",
+		5: "FEATURE_CANCEL",
+	}
+	Feature_value = map[string]int32{
+		"FEATURE_UNSPECIFIED":   0,
+		"FEATURE_HEALTH_CHECKS": 1,
+		"FEATURE_HOOKS":         2,
+		"FEATURE_COMPOSE":       3,
+		"FEATURE_START_FIRST":   4,
+		"FEATURE_CANCEL":        5,
+	}
+)
+
+func (x Feature) Enum() *Feature {
+	p := new(Feature)
+	*p = x
+	return p
+}
+
+func (x Feature) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Feature) Descriptor() protoreflect.EnumDescriptor {
+	return file_host_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (Feature) Type() protoreflect.EnumType {
+	return &file_host_agent_proto_enumTypes[1]
+}
+
+func (x Feature) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Feature.Descriptor instead.
+func (Feature) EnumDescriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{1}
+}
+
+type HealthCheck_Kind int32
+
+const (
+	HealthCheck_DOCKER HealthCheck_Kind = 0 // the image's HEALTHCHECK; containers without one only get the grace period
+	HealthCheck_HTTP   HealthCheck_Kind = 1 // GET target, a URL, expecting 2xx
+	HealthCheck_TCP    HealthCheck_Kind = 2 // connect to target, host:port
+	HealthCheck_EXEC   HealthCheck_Kind = 3 // run target with "sh -c" in the container, expecting exit code 0
+	HealthCheck_NONE   HealthCheck_Kind = 4 // no check at all
+)
+
+// Enum value maps for HealthCheck_Kind.
+var (
+	HealthCheck_Kind_name = map[int32]string{
+		0: "DOCKER",
+		1: "HTTP",
+		2: "TCP",
+		3: "EXEC",
+		4: "NONE",
+	}
+	HealthCheck_Kind_value = map[string]int32{
+		"DOCKER": 0,
+		"HTTP":   1,
+		"TCP":    2,
+		"EXEC":   3,
+		"NONE":   4,
+	}
+)
+
+func (x HealthCheck_Kind) Enum() *HealthCheck_Kind {
+	p := new(HealthCheck_Kind)
+	*p = x
+	return p
+}
+
+func (x HealthCheck_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthCheck_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_host_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (HealthCheck_Kind) Type() protoreflect.EnumType {
+	return &file_host_agent_proto_enumTypes[2]
+}
+
+func (x HealthCheck_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthCheck_Kind.Descriptor instead.
+func (HealthCheck_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{18, 0}
+}
+
+type Hook_Phase int32
+
+const (
+	Hook_PRE  Hook_Phase = 0 // after the pull, before the old container is stopped
+	Hook_POST Hook_Phase = 1 // after the new container started and passed its health check
+)
+
+// Enum value maps for Hook_Phase.
+var (
+	Hook_Phase_name = map[int32]string{
+		0: "PRE",
+		1: "POST",
+	}
+	Hook_Phase_value = map[string]int32{
+		"PRE":  0,
+		"POST": 1,
+	}
+)
+
+func (x Hook_Phase) Enum() *Hook_Phase {
+	p := new(Hook_Phase)
+	*p = x
+	return p
+}
+
+func (x Hook_Phase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Hook_Phase) Descriptor() protoreflect.EnumDescriptor {
+	return file_host_agent_proto_enumTypes[3].Descriptor()
+}
+
+func (Hook_Phase) Type() protoreflect.EnumType {
+	return &file_host_agent_proto_enumTypes[3]
+}
+
+func (x Hook_Phase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Hook_Phase.Descriptor instead.
+func (Hook_Phase) EnumDescriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{19, 0}
+}
+
+type Hook_Kind int32
+
+const (
+	Hook_EXEC Hook_Kind = 0 // command run in the container: the old one for PRE, the new one for POST
+	Hook_HOST Hook_Kind = 1 // command run on the host
+	Hook_HTTP Hook_Kind = 2 // request sent to url
+)
+
+// Enum value maps for Hook_Kind.
+var (
+	Hook_Kind_name = map[int32]string{
+		0: "EXEC",
+		1: "HOST",
+		2: "HTTP",
+	}
+	Hook_Kind_value = map[string]int32{
+		"EXEC": 0,
+		"HOST": 1,
+		"HTTP": 2,
+	}
+)
+
+func (x Hook_Kind) Enum() *Hook_Kind {
+	p := new(Hook_Kind)
+	*p = x
+	return p
+}
+
+func (x Hook_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Hook_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_host_agent_proto_enumTypes[4].Descriptor()
+}
+
+func (Hook_Kind) Type() protoreflect.EnumType {
+	return &file_host_agent_proto_enumTypes[4]
+}
+
+func (x Hook_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Hook_Kind.Descriptor instead.
+func (Hook_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{19, 1}
+}
+
+type Hook_OnFailure int32
+
+const (
+	Hook_ABORT    Hook_OnFailure = 0 // stop the update; a POST hook leaves the new container running
+	Hook_ROLLBACK Hook_OnFailure = 1 // restore the original container
+	Hook_IGNORE   Hook_OnFailure = 2 // carry on
+)
+
+// Enum value maps for Hook_OnFailure.
+var (
+	Hook_OnFailure_name = map[int32]string{
+		0: "ABORT",
+		1: "ROLLBACK",
+		2: "IGNORE",
+	}
+	Hook_OnFailure_value = map[string]int32{
+		"ABORT":    0,
+		"ROLLBACK": 1,
+		"IGNORE":   2,
+	}
+)
+
+func (x Hook_OnFailure) Enum() *Hook_OnFailure {
+	p := new(Hook_OnFailure)
+	*p = x
+	return p
+}
+
+func (x Hook_OnFailure) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Hook_OnFailure) Descriptor() protoreflect.EnumDescriptor {
+	return file_host_agent_proto_enumTypes[5].Descriptor()
+}
+
+func (Hook_OnFailure) Type() protoreflect.EnumType {
+	return &file_host_agent_proto_enumTypes[5]
+}
+
+func (x Hook_OnFailure) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Hook_OnFailure.Descriptor instead.
+func (Hook_OnFailure) EnumDescriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{19, 2}
+}
+
 type UpdateStatus_Stage int32
 
 const (
@@ -32,19 +337,29 @@ const (
 	UpdateStatus_ROLLBACK     UpdateStatus_Stage = 5
 	UpdateStatus_FAILED       UpdateStatus_Stage = 6
 	UpdateStatus_RUNNING      UpdateStatus_Stage = 7
+	UpdateStatus_HOOK         UpdateStatus_Stage = 8  // output of a pre- or post-update hook
+	UpdateStatus_QUEUED       UpdateStatus_Stage = 9  // accepted, waiting for a worker or an earlier update of the container
+	UpdateStatus_REJECTED     UpdateStatus_Stage = 10 // not run: the same update of the container is already queued or running
+	UpdateStatus_CANCELLED    UpdateStatus_Stage = 11 // aborted by a CancelUpdate; the old container is kept or restored
+	UpdateStatus_PREFLIGHT    UpdateStatus_Stage = 12 // pre-flight checks passed; the old container is replaced next
 )
 
 // Enum value maps for UpdateStatus_Stage.
 var (
 	UpdateStatus_Stage_name = map[int32]string{
-		0: "UNKNOWN",
-		1: "PULLING",
-		2: "STARTING",
-		3: "HEALTH_CHECK",
-		4: "COMPLETED",
-		5: "ROLLBACK",
-		6: "FAILED",
-		7: "RUNNING",
+		0:  "UNKNOWN",
+		1:  "PULLING",
+		2:  "STARTING",
+		3:  "HEALTH_CHECK",
+		4:  "COMPLETED",
+		5:  "ROLLBACK",
+		6:  "FAILED",
+		7:  "RUNNING",
+		8:  "HOOK",
+		9:  "QUEUED",
+		10: "REJECTED",
+		11: "CANCELLED",
+		12: "PREFLIGHT",
 	}
 	UpdateStatus_Stage_value = map[string]int32{
 		"UNKNOWN":      0,
@@ -55,6 +370,11 @@ var (
 		"ROLLBACK":     5,
 		"FAILED":       6,
 		"RUNNING":      7,
+		"HOOK":         8,
+		"QUEUED":       9,
+		"REJECTED":     10,
+		"CANCELLED":    11,
+		"PREFLIGHT":    12,
 	}
 )
 
@@ -69,11 +389,11 @@ func (x UpdateStatus_Stage) String() string {
 }
 
 func (UpdateStatus_Stage) Descriptor() protoreflect.EnumDescriptor {
-	return file_host_agent_proto_enumTypes[0].Descriptor()
+	return file_host_agent_proto_enumTypes[6].Descriptor()
 }
 
 func (UpdateStatus_Stage) Type() protoreflect.EnumType {
-	return &file_host_agent_proto_enumTypes[0]
+	return &file_host_agent_proto_enumTypes[6]
 }
 
 func (x UpdateStatus_Stage) Number() protoreflect.EnumNumber {
@@ -82,7 +402,7 @@ func (x UpdateStatus_Stage) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use UpdateStatus_Stage.Descriptor instead.
 func (UpdateStatus_Stage) EnumDescriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{8, 0}
+	return file_host_agent_proto_rawDescGZIP(), []int{21, 0}
 }
 
 // Port mapping definition
@@ -155,16 +475,24 @@ func (x *PortMapping) GetProtocol() string {
 }
 
 type ContainerInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ContainerID   string                 `protobuf:"bytes,1,opt,name=containerID,proto3" json:"containerID,omitempty"` // Unique identifier for the container
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`               // Name of the container
-	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`             // Docker image used for the container
-	Ports         []*PortMapping         `protobuf:"bytes,4,rep,name=ports,proto3" json:"ports,omitempty"`             // Structured ports
-	EnvVars       []string               `protobuf:"bytes,5,rep,name=envVars,proto3" json:"envVars,omitempty"`
-	Volumes       []string               `protobuf:"bytes,6,rep,name=volumes,proto3" json:"volumes,omitempty"`
-	Network       string                 `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ContainerID    string                 `protobuf:"bytes,1,opt,name=containerID,proto3" json:"containerID,omitempty"` // Unique identifier for the container
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`               // Name of the container
+	Image          string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`             // Docker image used for the container
+	Ports          []*PortMapping         `protobuf:"bytes,4,rep,name=ports,proto3" json:"ports,omitempty"`             // Structured ports
+	EnvVars        []string               `protobuf:"bytes,5,rep,name=envVars,proto3" json:"envVars,omitempty"`
+	Volumes        []string               `protobuf:"bytes,6,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	Network        string                 `protobuf:"bytes,7,opt,name=network,proto3" json:"network,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Docker labels declared on the container
+	ImageDigest    string                 `protobuf:"bytes,9,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`                                              // registry digest of the running image, e.g. "sha256:..."
+	ComposeProject string                 `protobuf:"bytes,10,opt,name=compose_project,json=composeProject,proto3" json:"compose_project,omitempty"`                                    // Docker Compose project the container belongs to, if any
+	ComposeService string                 `protobuf:"bytes,11,opt,name=compose_service,json=composeService,proto3" json:"compose_service,omitempty"`                                    // service name within the compose project
+	DependsOn      []string               `protobuf:"bytes,12,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`                                                   // compose services this one depends on
+	Spec           *ContainerSpec         `protobuf:"bytes,13,opt,name=spec,proto3" json:"spec,omitempty"`                                                                              // everything needed to recreate the container
+	State          string                 `protobuf:"bytes,14,opt,name=state,proto3" json:"state,omitempty"`                                                                            // Docker state: created, running, paused, restarting, exited or dead
+	Health         string                 `protobuf:"bytes,15,opt,name=health,proto3" json:"health,omitempty"`                                                                          // Docker health: starting, healthy or unhealthy; empty without a HEALTHCHECK
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ContainerInfo) Reset() {
@@ -246,96 +574,103 @@ func (x *ContainerInfo) GetNetwork() string {
 	return ""
 }
 
-type HostInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MacAddress    string                 `protobuf:"bytes,1,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
-	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	IpAddress     string                 `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	Containers    []*ContainerInfo       `protobuf:"bytes,5,rep,name=containers,proto3" json:"containers,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *HostInfo) Reset() {
-	*x = HostInfo{}
-	mi := &file_host_agent_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ContainerInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
-func (x *HostInfo) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ContainerInfo) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
 }
 
-func (*HostInfo) ProtoMessage() {}
-
-func (x *HostInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[2]
+func (x *ContainerInfo) GetComposeProject() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ComposeProject
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use HostInfo.ProtoReflect.Descriptor instead.
-func (*HostInfo) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{2}
+func (x *ContainerInfo) GetComposeService() string {
+	if x != nil {
+		return x.ComposeService
+	}
+	return ""
 }
 
-func (x *HostInfo) GetMacAddress() string {
+func (x *ContainerInfo) GetDependsOn() []string {
 	if x != nil {
-		return x.MacAddress
+		return x.DependsOn
 	}
-	return ""
+	return nil
 }
 
-func (x *HostInfo) GetHostname() string {
+func (x *ContainerInfo) GetSpec() *ContainerSpec {
 	if x != nil {
-		return x.Hostname
+		return x.Spec
 	}
-	return ""
+	return nil
 }
 
-func (x *HostInfo) GetIpAddress() string {
+func (x *ContainerInfo) GetState() string {
 	if x != nil {
-		return x.IpAddress
+		return x.State
 	}
 	return ""
 }
 
-func (x *HostInfo) GetContainers() []*ContainerInfo {
+func (x *ContainerInfo) GetHealth() string {
 	if x != nil {
-		return x.Containers
+		return x.Health
 	}
-	return nil
+	return ""
 }
 
-type RegisterHostRequest struct {
+// ContainerSpec is the configuration of a container that an update must carry over. Together
+// with the image it recreates the container identically.
+type ContainerSpec struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Host          *HostInfo              `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Env           []string               `protobuf:"bytes,1,rep,name=env,proto3" json:"env,omitempty"`
+	Ports         []*PortMapping         `protobuf:"bytes,2,rep,name=ports,proto3" json:"ports,omitempty"`
+	Mounts        []*MountSpec           `protobuf:"bytes,3,rep,name=mounts,proto3" json:"mounts,omitempty"`
+	Labels        map[string]string      `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RestartPolicy *RestartPolicy         `protobuf:"bytes,5,opt,name=restart_policy,json=restartPolicy,proto3" json:"restart_policy,omitempty"`
+	Resources     *Resources             `protobuf:"bytes,6,opt,name=resources,proto3" json:"resources,omitempty"`
+	CapAdd        []string               `protobuf:"bytes,7,rep,name=cap_add,json=capAdd,proto3" json:"cap_add,omitempty"`
+	CapDrop       []string               `protobuf:"bytes,8,rep,name=cap_drop,json=capDrop,proto3" json:"cap_drop,omitempty"`
+	Devices       []*DeviceMapping       `protobuf:"bytes,9,rep,name=devices,proto3" json:"devices,omitempty"`
+	Healthcheck   *Healthcheck           `protobuf:"bytes,10,opt,name=healthcheck,proto3" json:"healthcheck,omitempty"` // unset keeps the image's HEALTHCHECK
+	User          string                 `protobuf:"bytes,11,opt,name=user,proto3" json:"user,omitempty"`
+	Entrypoint    []string               `protobuf:"bytes,12,rep,name=entrypoint,proto3" json:"entrypoint,omitempty"`
+	Cmd           []string               `protobuf:"bytes,13,rep,name=cmd,proto3" json:"cmd,omitempty"`
+	NetworkMode   string                 `protobuf:"bytes,14,opt,name=network_mode,json=networkMode,proto3" json:"network_mode,omitempty"`
+	Networks      []*NetworkAttachment   `protobuf:"bytes,15,rep,name=networks,proto3" json:"networks,omitempty"`
+	WorkingDir    string                 `protobuf:"bytes,16,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	Hostname      string                 `protobuf:"bytes,17,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Privileged    bool                   `protobuf:"varint,18,opt,name=privileged,proto3" json:"privileged,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterHostRequest) Reset() {
-	*x = RegisterHostRequest{}
-	mi := &file_host_agent_proto_msgTypes[3]
+func (x *ContainerSpec) Reset() {
+	*x = ContainerSpec{}
+	mi := &file_host_agent_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterHostRequest) String() string {
+func (x *ContainerSpec) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterHostRequest) ProtoMessage() {}
+func (*ContainerSpec) ProtoMessage() {}
 
-func (x *RegisterHostRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[3]
+func (x *ContainerSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -346,41 +681,1525 @@ func (x *RegisterHostRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterHostRequest.ProtoReflect.Descriptor instead.
-func (*RegisterHostRequest) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ContainerSpec.ProtoReflect.Descriptor instead.
+func (*ContainerSpec) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *RegisterHostRequest) GetHost() *HostInfo {
+func (x *ContainerSpec) GetEnv() []string {
 	if x != nil {
-		return x.Host
+		return x.Env
 	}
 	return nil
 }
 
-type RegisterHostResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ContainerSpec) GetPorts() []*PortMapping {
+	if x != nil {
+		return x.Ports
+	}
+	return nil
 }
 
-func (x *RegisterHostResponse) Reset() {
-	*x = RegisterHostResponse{}
-	mi := &file_host_agent_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ContainerSpec) GetMounts() []*MountSpec {
+	if x != nil {
+		return x.Mounts
+	}
+	return nil
 }
 
-func (x *RegisterHostResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ContainerSpec) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
-func (*RegisterHostResponse) ProtoMessage() {}
-
-func (x *RegisterHostResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[4]
+func (x *ContainerSpec) GetRestartPolicy() *RestartPolicy {
+	if x != nil {
+		return x.RestartPolicy
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetResources() *Resources {
+	if x != nil {
+		return x.Resources
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetCapAdd() []string {
+	if x != nil {
+		return x.CapAdd
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetCapDrop() []string {
+	if x != nil {
+		return x.CapDrop
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetDevices() []*DeviceMapping {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetHealthcheck() *Healthcheck {
+	if x != nil {
+		return x.Healthcheck
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ContainerSpec) GetEntrypoint() []string {
+	if x != nil {
+		return x.Entrypoint
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetCmd() []string {
+	if x != nil {
+		return x.Cmd
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetNetworkMode() string {
+	if x != nil {
+		return x.NetworkMode
+	}
+	return ""
+}
+
+func (x *ContainerSpec) GetNetworks() []*NetworkAttachment {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+func (x *ContainerSpec) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *ContainerSpec) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *ContainerSpec) GetPrivileged() bool {
+	if x != nil {
+		return x.Privileged
+	}
+	return false
+}
+
+type MountSpec struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`     // bind, volume, tmpfs or npipe
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"` // host path or volume name
+	Target        string                 `protobuf:"bytes,3,opt,name=target,proto3" json:"target,omitempty"` // path in the container
+	ReadOnly      bool                   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
+	Mode          string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`               // e.g. "z" for SELinux relabeling
+	Propagation   string                 `protobuf:"bytes,6,opt,name=propagation,proto3" json:"propagation,omitempty"` // bind propagation, e.g. "rprivate"
+	Tmpfs         *TmpfsOptions          `protobuf:"bytes,7,opt,name=tmpfs,proto3" json:"tmpfs,omitempty"`             // tmpfs mounts only
+	Volume        *VolumeOptions         `protobuf:"bytes,8,opt,name=volume,proto3" json:"volume,omitempty"`           // volume mounts only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MountSpec) Reset() {
+	*x = MountSpec{}
+	mi := &file_host_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MountSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MountSpec) ProtoMessage() {}
+
+func (x *MountSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MountSpec.ProtoReflect.Descriptor instead.
+func (*MountSpec) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MountSpec) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MountSpec) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *MountSpec) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *MountSpec) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *MountSpec) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *MountSpec) GetPropagation() string {
+	if x != nil {
+		return x.Propagation
+	}
+	return ""
+}
+
+func (x *MountSpec) GetTmpfs() *TmpfsOptions {
+	if x != nil {
+		return x.Tmpfs
+	}
+	return nil
+}
+
+func (x *MountSpec) GetVolume() *VolumeOptions {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+type TmpfsOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SizeBytes     int64                  `protobuf:"varint,1,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Mode          uint32                 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`      // file mode of the tmpfs root, e.g. 01777
+	Options       []string               `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty"` // further mount options, "flag" or "key=value"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TmpfsOptions) Reset() {
+	*x = TmpfsOptions{}
+	mi := &file_host_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TmpfsOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TmpfsOptions) ProtoMessage() {}
+
+func (x *TmpfsOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TmpfsOptions.ProtoReflect.Descriptor instead.
+func (*TmpfsOptions) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TmpfsOptions) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *TmpfsOptions) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *TmpfsOptions) GetOptions() []string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type VolumeOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NoCopy        bool                   `protobuf:"varint,1,opt,name=no_copy,json=noCopy,proto3" json:"no_copy,omitempty"`                                                            // don't populate the volume from the image
+	Labels        map[string]string      `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // labels set when the volume is created
+	Subpath       string                 `protobuf:"bytes,3,opt,name=subpath,proto3" json:"subpath,omitempty"`                                                                         // path within the volume to mount
+	Driver        string                 `protobuf:"bytes,4,opt,name=driver,proto3" json:"driver,omitempty"`                                                                           // volume driver used to create the volume
+	DriverOptions map[string]string      `protobuf:"bytes,5,rep,name=driver_options,json=driverOptions,proto3" json:"driver_options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VolumeOptions) Reset() {
+	*x = VolumeOptions{}
+	mi := &file_host_agent_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VolumeOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeOptions) ProtoMessage() {}
+
+func (x *VolumeOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeOptions.ProtoReflect.Descriptor instead.
+func (*VolumeOptions) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VolumeOptions) GetNoCopy() bool {
+	if x != nil {
+		return x.NoCopy
+	}
+	return false
+}
+
+func (x *VolumeOptions) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *VolumeOptions) GetSubpath() string {
+	if x != nil {
+		return x.Subpath
+	}
+	return ""
+}
+
+func (x *VolumeOptions) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *VolumeOptions) GetDriverOptions() map[string]string {
+	if x != nil {
+		return x.DriverOptions
+	}
+	return nil
+}
+
+type RestartPolicy struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // no, always, unless-stopped or on-failure
+	MaximumRetryCount int32                  `protobuf:"varint,2,opt,name=maximum_retry_count,json=maximumRetryCount,proto3" json:"maximum_retry_count,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RestartPolicy) Reset() {
+	*x = RestartPolicy{}
+	mi := &file_host_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartPolicy) ProtoMessage() {}
+
+func (x *RestartPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartPolicy.ProtoReflect.Descriptor instead.
+func (*RestartPolicy) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RestartPolicy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RestartPolicy) GetMaximumRetryCount() int32 {
+	if x != nil {
+		return x.MaximumRetryCount
+	}
+	return 0
+}
+
+type Resources struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Memory        int64                  `protobuf:"varint,1,opt,name=memory,proto3" json:"memory,omitempty"`                           // bytes
+	MemorySwap    int64                  `protobuf:"varint,2,opt,name=memory_swap,json=memorySwap,proto3" json:"memory_swap,omitempty"` // bytes
+	NanoCpus      int64                  `protobuf:"varint,3,opt,name=nano_cpus,json=nanoCpus,proto3" json:"nano_cpus,omitempty"`
+	CpuShares     int64                  `protobuf:"varint,4,opt,name=cpu_shares,json=cpuShares,proto3" json:"cpu_shares,omitempty"`
+	PidsLimit     int64                  `protobuf:"varint,5,opt,name=pids_limit,json=pidsLimit,proto3" json:"pids_limit,omitempty"`
+	CpusetCpus    string                 `protobuf:"bytes,6,opt,name=cpuset_cpus,json=cpusetCpus,proto3" json:"cpuset_cpus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Resources) Reset() {
+	*x = Resources{}
+	mi := &file_host_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resources) ProtoMessage() {}
+
+func (x *Resources) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resources.ProtoReflect.Descriptor instead.
+func (*Resources) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Resources) GetMemory() int64 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *Resources) GetMemorySwap() int64 {
+	if x != nil {
+		return x.MemorySwap
+	}
+	return 0
+}
+
+func (x *Resources) GetNanoCpus() int64 {
+	if x != nil {
+		return x.NanoCpus
+	}
+	return 0
+}
+
+func (x *Resources) GetCpuShares() int64 {
+	if x != nil {
+		return x.CpuShares
+	}
+	return 0
+}
+
+func (x *Resources) GetPidsLimit() int64 {
+	if x != nil {
+		return x.PidsLimit
+	}
+	return 0
+}
+
+func (x *Resources) GetCpusetCpus() string {
+	if x != nil {
+		return x.CpusetCpus
+	}
+	return ""
+}
+
+type DeviceMapping struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PathOnHost        string                 `protobuf:"bytes,1,opt,name=path_on_host,json=pathOnHost,proto3" json:"path_on_host,omitempty"`
+	PathInContainer   string                 `protobuf:"bytes,2,opt,name=path_in_container,json=pathInContainer,proto3" json:"path_in_container,omitempty"`
+	CgroupPermissions string                 `protobuf:"bytes,3,opt,name=cgroup_permissions,json=cgroupPermissions,proto3" json:"cgroup_permissions,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DeviceMapping) Reset() {
+	*x = DeviceMapping{}
+	mi := &file_host_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceMapping) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceMapping) ProtoMessage() {}
+
+func (x *DeviceMapping) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceMapping.ProtoReflect.Descriptor instead.
+func (*DeviceMapping) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeviceMapping) GetPathOnHost() string {
+	if x != nil {
+		return x.PathOnHost
+	}
+	return ""
+}
+
+func (x *DeviceMapping) GetPathInContainer() string {
+	if x != nil {
+		return x.PathInContainer
+	}
+	return ""
+}
+
+func (x *DeviceMapping) GetCgroupPermissions() string {
+	if x != nil {
+		return x.CgroupPermissions
+	}
+	return ""
+}
+
+type Healthcheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Test          []string               `protobuf:"bytes,1,rep,name=test,proto3" json:"test,omitempty"`
+	IntervalNs    int64                  `protobuf:"varint,2,opt,name=interval_ns,json=intervalNs,proto3" json:"interval_ns,omitempty"`
+	TimeoutNs     int64                  `protobuf:"varint,3,opt,name=timeout_ns,json=timeoutNs,proto3" json:"timeout_ns,omitempty"`
+	StartPeriodNs int64                  `protobuf:"varint,4,opt,name=start_period_ns,json=startPeriodNs,proto3" json:"start_period_ns,omitempty"`
+	Retries       int32                  `protobuf:"varint,5,opt,name=retries,proto3" json:"retries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Healthcheck) Reset() {
+	*x = Healthcheck{}
+	mi := &file_host_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Healthcheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Healthcheck) ProtoMessage() {}
+
+func (x *Healthcheck) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Healthcheck.ProtoReflect.Descriptor instead.
+func (*Healthcheck) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Healthcheck) GetTest() []string {
+	if x != nil {
+		return x.Test
+	}
+	return nil
+}
+
+func (x *Healthcheck) GetIntervalNs() int64 {
+	if x != nil {
+		return x.IntervalNs
+	}
+	return 0
+}
+
+func (x *Healthcheck) GetTimeoutNs() int64 {
+	if x != nil {
+		return x.TimeoutNs
+	}
+	return 0
+}
+
+func (x *Healthcheck) GetStartPeriodNs() int64 {
+	if x != nil {
+		return x.StartPeriodNs
+	}
+	return 0
+}
+
+func (x *Healthcheck) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+type NetworkAttachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Aliases       []string               `protobuf:"bytes,2,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	Ipv4Address   string                 `protobuf:"bytes,3,opt,name=ipv4_address,json=ipv4Address,proto3" json:"ipv4_address,omitempty"` // static address, if any
+	Ipv6Address   string                 `protobuf:"bytes,4,opt,name=ipv6_address,json=ipv6Address,proto3" json:"ipv6_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAttachment) Reset() {
+	*x = NetworkAttachment{}
+	mi := &file_host_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAttachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAttachment) ProtoMessage() {}
+
+func (x *NetworkAttachment) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAttachment.ProtoReflect.Descriptor instead.
+func (*NetworkAttachment) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NetworkAttachment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkAttachment) GetAliases() []string {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+func (x *NetworkAttachment) GetIpv4Address() string {
+	if x != nil {
+		return x.Ipv4Address
+	}
+	return ""
+}
+
+func (x *NetworkAttachment) GetIpv6Address() string {
+	if x != nil {
+		return x.Ipv6Address
+	}
+	return ""
+}
+
+type HostInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MacAddress    string                 `protobuf:"bytes,1,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"` // attributes of the host; they may change over time
+	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	Containers    []*ContainerInfo       `protobuf:"bytes,5,rep,name=containers,proto3" json:"containers,omitempty"`
+	AgentId       string                 `protobuf:"bytes,6,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                   // UUID the agent generated on first start; identifies the host
+	InventoryHash string                 `protobuf:"bytes,7,opt,name=inventory_hash,json=inventoryHash,proto3" json:"inventory_hash,omitempty"` // hash of containers, as in HeartbeatRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostInfo) Reset() {
+	*x = HostInfo{}
+	mi := &file_host_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfo) ProtoMessage() {}
+
+func (x *HostInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfo.ProtoReflect.Descriptor instead.
+func (*HostInfo) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HostInfo) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *HostInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *HostInfo) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *HostInfo) GetContainers() []*ContainerInfo {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+func (x *HostInfo) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HostInfo) GetInventoryHash() string {
+	if x != nil {
+		return x.InventoryHash
+	}
+	return ""
+}
+
+type RegisterHostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          *HostInfo              `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterHostRequest) Reset() {
+	*x = RegisterHostRequest{}
+	mi := &file_host_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterHostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterHostRequest) ProtoMessage() {}
+
+func (x *RegisterHostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterHostRequest.ProtoReflect.Descriptor instead.
+func (*RegisterHostRequest) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RegisterHostRequest) GetHost() *HostInfo {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+type RegisterHostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterHostResponse) Reset() {
+	*x = RegisterHostResponse{}
+	mi := &file_host_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterHostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterHostResponse) ProtoMessage() {}
+
+func (x *RegisterHostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterHostResponse.ProtoReflect.Descriptor instead.
+func (*RegisterHostResponse) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RegisterHostResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterHostResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type HeartbeatRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	MacAddress          string                 `protobuf:"bytes,1,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Containers          []*ContainerInfo       `protobuf:"bytes,3,rep,name=containers,proto3" json:"containers,omitempty"`
+	AgentId             string                 `protobuf:"bytes,4,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Partial             bool                   `protobuf:"varint,5,opt,name=partial,proto3" json:"partial,omitempty"`                                                     // containers only lists changed containers
+	RemovedContainerIds []string               `protobuf:"bytes,6,rep,name=removed_container_ids,json=removedContainerIds,proto3" json:"removed_container_ids,omitempty"` // containers removed since the last heartbeat, when partial
+	InventoryHash       string                 `protobuf:"bytes,7,opt,name=inventory_hash,json=inventoryHash,proto3" json:"inventory_hash,omitempty"`                     // hash of the agent's whole inventory, changes included
+	BaseHash            string                 `protobuf:"bytes,8,opt,name=base_hash,json=baseHash,proto3" json:"base_hash,omitempty"`                                    // inventory hash the changes apply to, when partial
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_host_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HeartbeatRequest) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetContainers() []*ContainerInfo {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *HeartbeatRequest) GetRemovedContainerIds() []string {
+	if x != nil {
+		return x.RemovedContainerIds
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetInventoryHash() string {
+	if x != nil {
+		return x.InventoryHash
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetBaseHash() string {
+	if x != nil {
+		return x.BaseHash
+	}
+	return ""
+}
+
+type HeartbeatResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	InventoryHash  string                 `protobuf:"bytes,3,opt,name=inventory_hash,json=inventoryHash,proto3" json:"inventory_hash,omitempty"`     // inventory hash the orchestrator holds for the host
+	ResyncRequired bool                   `protobuf:"varint,4,opt,name=resync_required,json=resyncRequired,proto3" json:"resync_required,omitempty"` // base_hash didn't match: the changes were not applied, send everything
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_host_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HeartbeatResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HeartbeatResponse) GetInventoryHash() string {
+	if x != nil {
+		return x.InventoryHash
+	}
+	return ""
+}
+
+func (x *HeartbeatResponse) GetResyncRequired() bool {
+	if x != nil {
+		return x.ResyncRequired
+	}
+	return false
+}
+
+type UpdateContainerCommand struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ContainerUID    string                 `protobuf:"bytes,2,opt,name=containerUID,proto3" json:"containerUID,omitempty"`       // which container to update
+	Image           string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`                     // target image (repo:tag or digest)
+	OverrideEnvVars []string               `protobuf:"bytes,4,rep,name=overrideEnvVars,proto3" json:"overrideEnvVars,omitempty"` // optional overrides
+	OverridePorts   []*PortMapping         `protobuf:"bytes,5,rep,name=overridePorts,proto3" json:"overridePorts,omitempty"`     // structured ports override
+	OverrideVolumes []string               `protobuf:"bytes,6,rep,name=overrideVolumes,proto3" json:"overrideVolumes,omitempty"`
+	OverrideNetwork string                 `protobuf:"bytes,7,opt,name=overrideNetwork,proto3" json:"overrideNetwork,omitempty"`
+	AgentId         string                 `protobuf:"bytes,8,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                   // target agent
+	ContainerName   string                 `protobuf:"bytes,9,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"` // echoed back in UpdateStatus
+	Digest          string                 `protobuf:"bytes,10,opt,name=digest,proto3" json:"digest,omitempty"`                                   // optional; pull image by this digest and tag it as image (used for rollbacks)
+	Hooks           []*Hook                `protobuf:"bytes,11,rep,name=hooks,proto3" json:"hooks,omitempty"`                                     // run before stopping the old container and after starting the new one
+	HealthCheck     *HealthCheck           `protobuf:"bytes,12,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`      // gates COMPLETED; unset waits for the image's HEALTHCHECK
+	Spec            *ContainerSpec         `protobuf:"bytes,13,opt,name=spec,proto3" json:"spec,omitempty"`                                       // recreate the container from this spec; replaces the override fields
+	CommandId       string                 `protobuf:"bytes,14,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`            // unique per command; echoed back in UpdateStatus and used to cancel it
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateContainerCommand) Reset() {
+	*x = UpdateContainerCommand{}
+	mi := &file_host_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateContainerCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateContainerCommand) ProtoMessage() {}
+
+func (x *UpdateContainerCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateContainerCommand.ProtoReflect.Descriptor instead.
+func (*UpdateContainerCommand) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateContainerCommand) GetContainerUID() string {
+	if x != nil {
+		return x.ContainerUID
+	}
+	return ""
+}
+
+func (x *UpdateContainerCommand) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *UpdateContainerCommand) GetOverrideEnvVars() []string {
+	if x != nil {
+		return x.OverrideEnvVars
+	}
+	return nil
+}
+
+func (x *UpdateContainerCommand) GetOverridePorts() []*PortMapping {
+	if x != nil {
+		return x.OverridePorts
+	}
+	return nil
+}
+
+func (x *UpdateContainerCommand) GetOverrideVolumes() []string {
+	if x != nil {
+		return x.OverrideVolumes
+	}
+	return nil
+}
+
+func (x *UpdateContainerCommand) GetOverrideNetwork() string {
+	if x != nil {
+		return x.OverrideNetwork
+	}
+	return ""
+}
+
+func (x *UpdateContainerCommand) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *UpdateContainerCommand) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *UpdateContainerCommand) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *UpdateContainerCommand) GetHooks() []*Hook {
+	if x != nil {
+		return x.Hooks
+	}
+	return nil
+}
+
+func (x *UpdateContainerCommand) GetHealthCheck() *HealthCheck {
+	if x != nil {
+		return x.HealthCheck
+	}
+	return nil
+}
+
+func (x *UpdateContainerCommand) GetSpec() *ContainerSpec {
+	if x != nil {
+		return x.Spec
+	}
+	return nil
+}
+
+func (x *UpdateContainerCommand) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+// CancelUpdate aborts a queued or running update. A running update stops at its current step
+// and restores the old container if it was already stopped.
+type CancelUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommandId     string                 `protobuf:"bytes,1,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`
+	ContainerName string                 `protobuf:"bytes,2,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelUpdate) Reset() {
+	*x = CancelUpdate{}
+	mi := &file_host_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelUpdate) ProtoMessage() {}
+
+func (x *CancelUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelUpdate.ProtoReflect.Descriptor instead.
+func (*CancelUpdate) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelUpdate) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *CancelUpdate) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *CancelUpdate) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// HealthCheck decides when a new container is healthy. The agent probes it until it passes or
+// timeout_seconds elapse, then watches it for grace_seconds: a container that stops or restarts
+// in that time fails the check. A failed check rolls the update back.
+type HealthCheck struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Kind           HealthCheck_Kind       `protobuf:"varint,1,opt,name=kind,proto3,enum=orchestrator.HealthCheck_Kind" json:"kind,omitempty"`
+	Target         string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`                                        // "{ip}" is replaced with the container's IP address
+	TimeoutSeconds int32                  `protobuf:"varint,3,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // defaults to 60
+	GraceSeconds   int32                  `protobuf:"varint,4,opt,name=grace_seconds,json=graceSeconds,proto3" json:"grace_seconds,omitempty"`       // defaults to 10
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HealthCheck) Reset() {
+	*x = HealthCheck{}
+	mi := &file_host_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheck) ProtoMessage() {}
+
+func (x *HealthCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheck.ProtoReflect.Descriptor instead.
+func (*HealthCheck) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HealthCheck) GetKind() HealthCheck_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return HealthCheck_DOCKER
+}
+
+func (x *HealthCheck) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *HealthCheck) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *HealthCheck) GetGraceSeconds() int32 {
+	if x != nil {
+		return x.GraceSeconds
+	}
+	return 0
+}
+
+// Hook is a step the agent runs around an update. Its output is reported with the HOOK stage.
+type Hook struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Phase          Hook_Phase             `protobuf:"varint,2,opt,name=phase,proto3,enum=orchestrator.Hook_Phase" json:"phase,omitempty"`
+	Kind           Hook_Kind              `protobuf:"varint,3,opt,name=kind,proto3,enum=orchestrator.Hook_Kind" json:"kind,omitempty"`
+	Command        string                 `protobuf:"bytes,4,opt,name=command,proto3" json:"command,omitempty"`                                      // EXEC and HOST, run with "sh -c"
+	Url            string                 `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`                                              // HTTP
+	Method         string                 `protobuf:"bytes,6,opt,name=method,proto3" json:"method,omitempty"`                                        // HTTP, defaults to POST
+	TimeoutSeconds int32                  `protobuf:"varint,7,opt,name=timeout_seconds,json=timeoutSeconds,proto3" json:"timeout_seconds,omitempty"` // defaults to 60
+	OnFailure      Hook_OnFailure         `protobuf:"varint,8,opt,name=on_failure,json=onFailure,proto3,enum=orchestrator.Hook_OnFailure" json:"on_failure,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Hook) Reset() {
+	*x = Hook{}
+	mi := &file_host_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hook) ProtoMessage() {}
+
+func (x *Hook) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hook.ProtoReflect.Descriptor instead.
+func (*Hook) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Hook) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Hook) GetPhase() Hook_Phase {
+	if x != nil {
+		return x.Phase
+	}
+	return Hook_PRE
+}
+
+func (x *Hook) GetKind() Hook_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return Hook_EXEC
+}
+
+func (x *Hook) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Hook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Hook) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Hook) GetTimeoutSeconds() int32 {
+	if x != nil {
+		return x.TimeoutSeconds
+	}
+	return 0
+}
+
+func (x *Hook) GetOnFailure() Hook_OnFailure {
+	if x != nil {
+		return x.OnFailure
+	}
+	return Hook_ABORT
+}
+
+// PreflightCheck is the result of one check run after the pull, before the old container is touched.
+type PreflightCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // disk, mounts, networks, ports or platform
+	Passed        bool                   `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"` // what was checked, or why it failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreflightCheck) Reset() {
+	*x = PreflightCheck{}
+	mi := &file_host_agent_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreflightCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreflightCheck) ProtoMessage() {}
+
+func (x *PreflightCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreflightCheck.ProtoReflect.Descriptor instead.
+func (*PreflightCheck) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PreflightCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PreflightCheck) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *PreflightCheck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UpdateStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ContainerUID    string                 `protobuf:"bytes,2,opt,name=containerUID,proto3" json:"containerUID,omitempty"`
+	Image           string                 `protobuf:"bytes,7,opt,name=image,proto3" json:"image,omitempty"`                                               // target image (repo:tag or digest)
+	AgentId         string                 `protobuf:"bytes,6,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`                            // agent reporting the status
+	ContainerName   string                 `protobuf:"bytes,8,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`          // name of the container being updated
+	PreviousImage   string                 `protobuf:"bytes,9,opt,name=previous_image,json=previousImage,proto3" json:"previous_image,omitempty"`          // image the container ran before the update (set on COMPLETED)
+	PreviousDigest  string                 `protobuf:"bytes,10,opt,name=previous_digest,json=previousDigest,proto3" json:"previous_digest,omitempty"`      // repo digest of the previous image, e.g. "sha256:..."
+	ImageDigest     string                 `protobuf:"bytes,11,opt,name=image_digest,json=imageDigest,proto3" json:"image_digest,omitempty"`               // repo digest of the image now running
+	TargetDigest    string                 `protobuf:"bytes,12,opt,name=target_digest,json=targetDigest,proto3" json:"target_digest,omitempty"`            // digest requested by the command, echoed back
+	NewContainerUid string                 `protobuf:"bytes,13,opt,name=new_container_uid,json=newContainerUid,proto3" json:"new_container_uid,omitempty"` // ID of the replacement container (set on COMPLETED)
+	Strategy        UpdateStrategy         `protobuf:"varint,14,opt,name=strategy,proto3,enum=orchestrator.UpdateStrategy" json:"strategy,omitempty"`      // how the old container was replaced (set on COMPLETED)
+	QueueDepth      uint32                 `protobuf:"varint,15,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`                 // updates waiting on the agent when the status was sent
+	CommandId       string                 `protobuf:"bytes,16,opt,name=command_id,json=commandId,proto3" json:"command_id,omitempty"`                     // command the status belongs to
+	Preflight       []*PreflightCheck      `protobuf:"bytes,17,rep,name=preflight,proto3" json:"preflight,omitempty"`                                      // results of the pre-flight checks (set on PREFLIGHT, or FAILED when one failed)
+	Stage           UpdateStatus_Stage     `protobuf:"varint,3,opt,name=stage,proto3,enum=orchestrator.UpdateStatus_Stage" json:"stage,omitempty"`
+	Logs            string                 `protobuf:"bytes,4,opt,name=logs,proto3" json:"logs,omitempty"`           // status log/err messages
+	Timestamp       string                 `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // optional, useful for ordering
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateStatus) Reset() {
+	*x = UpdateStatus{}
+	mi := &file_host_agent_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatus) ProtoMessage() {}
+
+func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -388,51 +2207,155 @@ func (x *RegisterHostResponse) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatus.ProtoReflect.Descriptor instead.
+func (*UpdateStatus) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateStatus) GetContainerUID() string {
+	if x != nil {
+		return x.ContainerUID
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetPreviousImage() string {
+	if x != nil {
+		return x.PreviousImage
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetPreviousDigest() string {
+	if x != nil {
+		return x.PreviousDigest
+	}
+	return ""
 }
 
-// Deprecated: Use RegisterHostResponse.ProtoReflect.Descriptor instead.
-func (*RegisterHostResponse) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{4}
+func (x *UpdateStatus) GetImageDigest() string {
+	if x != nil {
+		return x.ImageDigest
+	}
+	return ""
 }
 
-func (x *RegisterHostResponse) GetSuccess() bool {
+func (x *UpdateStatus) GetTargetDigest() string {
 	if x != nil {
-		return x.Success
+		return x.TargetDigest
 	}
-	return false
+	return ""
 }
 
-func (x *RegisterHostResponse) GetMessage() string {
+func (x *UpdateStatus) GetNewContainerUid() string {
 	if x != nil {
-		return x.Message
+		return x.NewContainerUid
 	}
 	return ""
 }
 
-type HeartbeatRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MacAddress    string                 `protobuf:"bytes,1,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
-	Containers    []*ContainerInfo       `protobuf:"bytes,3,rep,name=containers,proto3" json:"containers,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *UpdateStatus) GetStrategy() UpdateStrategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return UpdateStrategy_STOP_FIRST
 }
 
-func (x *HeartbeatRequest) Reset() {
-	*x = HeartbeatRequest{}
-	mi := &file_host_agent_proto_msgTypes[5]
+func (x *UpdateStatus) GetQueueDepth() uint32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *UpdateStatus) GetCommandId() string {
+	if x != nil {
+		return x.CommandId
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetPreflight() []*PreflightCheck {
+	if x != nil {
+		return x.Preflight
+	}
+	return nil
+}
+
+func (x *UpdateStatus) GetStage() UpdateStatus_Stage {
+	if x != nil {
+		return x.Stage
+	}
+	return UpdateStatus_UNKNOWN
+}
+
+func (x *UpdateStatus) GetLogs() string {
+	if x != nil {
+		return x.Logs
+	}
+	return ""
+}
+
+func (x *UpdateStatus) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+// AgentHello is the first message an agent sends on the command stream.
+type AgentHello struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AgentId         string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentVersion    string                 `protobuf:"bytes,2,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
+	ProtocolVersion uint32                 `protobuf:"varint,3,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Os              string                 `protobuf:"bytes,4,opt,name=os,proto3" json:"os,omitempty"`                                               // e.g. "linux"
+	Arch            string                 `protobuf:"bytes,5,opt,name=arch,proto3" json:"arch,omitempty"`                                           // e.g. "amd64"
+	DockerVersion   string                 `protobuf:"bytes,6,opt,name=docker_version,json=dockerVersion,proto3" json:"docker_version,omitempty"`    // version of the Docker engine on the host
+	Features        []Feature              `protobuf:"varint,7,rep,packed,name=features,proto3,enum=orchestrator.Feature" json:"features,omitempty"` // features the agent supports
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AgentHello) Reset() {
+	*x = AgentHello{}
+	mi := &file_host_agent_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HeartbeatRequest) String() string {
+func (x *AgentHello) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HeartbeatRequest) ProtoMessage() {}
+func (*AgentHello) ProtoMessage() {}
 
-func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[5]
+func (x *AgentHello) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -443,48 +2366,87 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
-func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use AgentHello.ProtoReflect.Descriptor instead.
+func (*AgentHello) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *HeartbeatRequest) GetMacAddress() string {
+func (x *AgentHello) GetAgentId() string {
 	if x != nil {
-		return x.MacAddress
+		return x.AgentId
 	}
 	return ""
 }
 
-func (x *HeartbeatRequest) GetContainers() []*ContainerInfo {
+func (x *AgentHello) GetAgentVersion() string {
 	if x != nil {
-		return x.Containers
+		return x.AgentVersion
+	}
+	return ""
+}
+
+func (x *AgentHello) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *AgentHello) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *AgentHello) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *AgentHello) GetDockerVersion() string {
+	if x != nil {
+		return x.DockerVersion
+	}
+	return ""
+}
+
+func (x *AgentHello) GetFeatures() []Feature {
+	if x != nil {
+		return x.Features
 	}
 	return nil
 }
 
-type HeartbeatResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// OrchestratorHello answers an AgentHello. The stream is closed after it when not accepted.
+type OrchestratorHello struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Accepted            bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Message             string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"` // why the agent was refused or degraded
+	OrchestratorVersion string                 `protobuf:"bytes,3,opt,name=orchestrator_version,json=orchestratorVersion,proto3" json:"orchestrator_version,omitempty"`
+	ProtocolVersion     uint32                 `protobuf:"varint,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"` // protocol version used on the stream
+	Features            []Feature              `protobuf:"varint,5,rep,packed,name=features,proto3,enum=orchestrator.Feature" json:"features,omitempty"`     // features both sides support; others are not used
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
-func (x *HeartbeatResponse) Reset() {
-	*x = HeartbeatResponse{}
-	mi := &file_host_agent_proto_msgTypes[6]
+func (x *OrchestratorHello) Reset() {
+	*x = OrchestratorHello{}
+	mi := &file_host_agent_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HeartbeatResponse) String() string {
+func (x *OrchestratorHello) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HeartbeatResponse) ProtoMessage() {}
+func (*OrchestratorHello) ProtoMessage() {}
 
-func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[6]
+func (x *OrchestratorHello) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -495,53 +2457,76 @@ func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
-func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use OrchestratorHello.ProtoReflect.Descriptor instead.
+func (*OrchestratorHello) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *HeartbeatResponse) GetSuccess() bool {
+func (x *OrchestratorHello) GetAccepted() bool {
 	if x != nil {
-		return x.Success
+		return x.Accepted
 	}
 	return false
 }
 
-func (x *HeartbeatResponse) GetMessage() string {
+func (x *OrchestratorHello) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-type UpdateContainerCommand struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	ContainerUID    string                 `protobuf:"bytes,2,opt,name=containerUID,proto3" json:"containerUID,omitempty"`       // which container to update
-	Image           string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`                     // target image (repo:tag or digest)
-	OverrideEnvVars []string               `protobuf:"bytes,4,rep,name=overrideEnvVars,proto3" json:"overrideEnvVars,omitempty"` // optional overrides
-	OverridePorts   []*PortMapping         `protobuf:"bytes,5,rep,name=overridePorts,proto3" json:"overridePorts,omitempty"`     // structured ports override
-	OverrideVolumes []string               `protobuf:"bytes,6,rep,name=overrideVolumes,proto3" json:"overrideVolumes,omitempty"`
-	OverrideNetwork string                 `protobuf:"bytes,7,opt,name=overrideNetwork,proto3" json:"overrideNetwork,omitempty"`
-	MacAddress      string                 `protobuf:"bytes,8,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"` // target host
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *OrchestratorHello) GetOrchestratorVersion() string {
+	if x != nil {
+		return x.OrchestratorVersion
+	}
+	return ""
 }
 
-func (x *UpdateContainerCommand) Reset() {
-	*x = UpdateContainerCommand{}
-	mi := &file_host_agent_proto_msgTypes[7]
+func (x *OrchestratorHello) GetProtocolVersion() uint32 {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return 0
+}
+
+func (x *OrchestratorHello) GetFeatures() []Feature {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+// AgentMessage is sent by the agent on the command stream: a hello first, then statuses.
+type AgentMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Field numbers of the UpdateStatus fields are left unused, so the bare UpdateStatus that
+	// agents predating the handshake send first is recognised instead of misparsed.
+	//
+	// Types that are valid to be assigned to Message:
+	//
+	//	*AgentMessage_Hello
+	//	*AgentMessage_Status
+	Message       isAgentMessage_Message `protobuf_oneof:"message"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentMessage) Reset() {
+	*x = AgentMessage{}
+	mi := &file_host_agent_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateContainerCommand) String() string {
+func (x *AgentMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateContainerCommand) ProtoMessage() {}
+func (*AgentMessage) ProtoMessage() {}
 
-func (x *UpdateContainerCommand) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[7]
+func (x *AgentMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -552,87 +2537,81 @@ func (x *UpdateContainerCommand) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateContainerCommand.ProtoReflect.Descriptor instead.
-func (*UpdateContainerCommand) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *UpdateContainerCommand) GetContainerUID() string {
-	if x != nil {
-		return x.ContainerUID
-	}
-	return ""
+// Deprecated: Use AgentMessage.ProtoReflect.Descriptor instead.
+func (*AgentMessage) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *UpdateContainerCommand) GetImage() string {
+func (x *AgentMessage) GetMessage() isAgentMessage_Message {
 	if x != nil {
-		return x.Image
+		return x.Message
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateContainerCommand) GetOverrideEnvVars() []string {
+func (x *AgentMessage) GetHello() *AgentHello {
 	if x != nil {
-		return x.OverrideEnvVars
+		if x, ok := x.Message.(*AgentMessage_Hello); ok {
+			return x.Hello
+		}
 	}
 	return nil
 }
 
-func (x *UpdateContainerCommand) GetOverridePorts() []*PortMapping {
+func (x *AgentMessage) GetStatus() *UpdateStatus {
 	if x != nil {
-		return x.OverridePorts
+		if x, ok := x.Message.(*AgentMessage_Status); ok {
+			return x.Status
+		}
 	}
 	return nil
 }
 
-func (x *UpdateContainerCommand) GetOverrideVolumes() []string {
-	if x != nil {
-		return x.OverrideVolumes
-	}
-	return nil
+type isAgentMessage_Message interface {
+	isAgentMessage_Message()
 }
 
-func (x *UpdateContainerCommand) GetOverrideNetwork() string {
-	if x != nil {
-		return x.OverrideNetwork
-	}
-	return ""
+type AgentMessage_Hello struct {
+	Hello *AgentHello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"`
 }
 
-func (x *UpdateContainerCommand) GetMacAddress() string {
-	if x != nil {
-		return x.MacAddress
-	}
-	return ""
+type AgentMessage_Status struct {
+	Status *UpdateStatus `protobuf:"bytes,16,opt,name=status,proto3,oneof"`
 }
 
-type UpdateStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ContainerUID  string                 `protobuf:"bytes,2,opt,name=containerUID,proto3" json:"containerUID,omitempty"`
-	Image         string                 `protobuf:"bytes,7,opt,name=image,proto3" json:"image,omitempty"`                             // target image (repo:tag or digest)
-	MacAddress    string                 `protobuf:"bytes,6,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"` // target host
-	Stage         UpdateStatus_Stage     `protobuf:"varint,3,opt,name=stage,proto3,enum=orchestrator.UpdateStatus_Stage" json:"stage,omitempty"`
-	Logs          string                 `protobuf:"bytes,4,opt,name=logs,proto3" json:"logs,omitempty"`           // status log/err messages
-	Timestamp     string                 `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // optional, useful for ordering
+func (*AgentMessage_Hello) isAgentMessage_Message() {}
+
+func (*AgentMessage_Status) isAgentMessage_Message() {}
+
+// OrchestratorMessage is sent by the orchestrator on the command stream: a hello first, then
+// commands and cancellations.
+type OrchestratorMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Message:
+	//
+	//	*OrchestratorMessage_Hello
+	//	*OrchestratorMessage_Command
+	//	*OrchestratorMessage_Cancel
+	Message       isOrchestratorMessage_Message `protobuf_oneof:"message"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateStatus) Reset() {
-	*x = UpdateStatus{}
-	mi := &file_host_agent_proto_msgTypes[8]
+func (x *OrchestratorMessage) Reset() {
+	*x = OrchestratorMessage{}
+	mi := &file_host_agent_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateStatus) String() string {
+func (x *OrchestratorMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateStatus) ProtoMessage() {}
+func (*OrchestratorMessage) ProtoMessage() {}
 
-func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_host_agent_proto_msgTypes[8]
+func (x *OrchestratorMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_host_agent_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,53 +2622,67 @@ func (x *UpdateStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateStatus.ProtoReflect.Descriptor instead.
-func (*UpdateStatus) Descriptor() ([]byte, []int) {
-	return file_host_agent_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use OrchestratorMessage.ProtoReflect.Descriptor instead.
+func (*OrchestratorMessage) Descriptor() ([]byte, []int) {
+	return file_host_agent_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *UpdateStatus) GetContainerUID() string {
+func (x *OrchestratorMessage) GetMessage() isOrchestratorMessage_Message {
 	if x != nil {
-		return x.ContainerUID
+		return x.Message
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateStatus) GetImage() string {
+func (x *OrchestratorMessage) GetHello() *OrchestratorHello {
 	if x != nil {
-		return x.Image
+		if x, ok := x.Message.(*OrchestratorMessage_Hello); ok {
+			return x.Hello
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateStatus) GetMacAddress() string {
+func (x *OrchestratorMessage) GetCommand() *UpdateContainerCommand {
 	if x != nil {
-		return x.MacAddress
+		if x, ok := x.Message.(*OrchestratorMessage_Command); ok {
+			return x.Command
+		}
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateStatus) GetStage() UpdateStatus_Stage {
+func (x *OrchestratorMessage) GetCancel() *CancelUpdate {
 	if x != nil {
-		return x.Stage
+		if x, ok := x.Message.(*OrchestratorMessage_Cancel); ok {
+			return x.Cancel
+		}
 	}
-	return UpdateStatus_UNKNOWN
+	return nil
 }
 
-func (x *UpdateStatus) GetLogs() string {
-	if x != nil {
-		return x.Logs
-	}
-	return ""
+type isOrchestratorMessage_Message interface {
+	isOrchestratorMessage_Message()
 }
 
-func (x *UpdateStatus) GetTimestamp() string {
-	if x != nil {
-		return x.Timestamp
-	}
-	return ""
+type OrchestratorMessage_Hello struct {
+	Hello *OrchestratorHello `protobuf:"bytes,1,opt,name=hello,proto3,oneof"`
+}
+
+type OrchestratorMessage_Command struct {
+	Command *UpdateContainerCommand `protobuf:"bytes,2,opt,name=command,proto3,oneof"`
+}
+
+type OrchestratorMessage_Cancel struct {
+	Cancel *CancelUpdate `protobuf:"bytes,3,opt,name=cancel,proto3,oneof"`
 }
 
+func (*OrchestratorMessage_Hello) isOrchestratorMessage_Message() {}
+
+func (*OrchestratorMessage_Command) isOrchestratorMessage_Message() {}
+
+func (*OrchestratorMessage_Cancel) isOrchestratorMessage_Message() {}
+
 var File_host_agent_proto protoreflect.FileDescriptor
 
 const file_host_agent_proto_rawDesc = "" +
@@ -699,7 +2692,7 @@ const file_host_agent_proto_rawDesc = "" +
 	"\ahost_ip\x18\x01 \x01(\tR\x06hostIp\x12\x1b\n" +
 	"\thost_port\x18\x02 \x01(\rR\bhostPort\x12%\n" +
 	"\x0econtainer_port\x18\x03 \x01(\rR\rcontainerPort\x12\x1a\n" +
-	"\bprotocol\x18\x04 \x01(\tR\bprotocol\"\xda\x01\n" +
+	"\bprotocol\x18\x04 \x01(\tR\bprotocol\"\xc9\x04\n" +
 	"\rContainerInfo\x12 \n" +
 	"\vcontainerID\x18\x01 \x01(\tR\vcontainerID\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -707,7 +2700,106 @@ const file_host_agent_proto_rawDesc = "" +
 	"\x05ports\x18\x04 \x03(\v2\x19.orchestrator.PortMappingR\x05ports\x12\x18\n" +
 	"\aenvVars\x18\x05 \x03(\tR\aenvVars\x12\x18\n" +
 	"\avolumes\x18\x06 \x03(\tR\avolumes\x12\x18\n" +
-	"\anetwork\x18\a \x01(\tR\anetwork\"\xa3\x01\n" +
+	"\anetwork\x18\a \x01(\tR\anetwork\x12?\n" +
+	"\x06labels\x18\b \x03(\v2'.orchestrator.ContainerInfo.LabelsEntryR\x06labels\x12!\n" +
+	"\fimage_digest\x18\t \x01(\tR\vimageDigest\x12'\n" +
+	"\x0fcompose_project\x18\n" +
+	" \x01(\tR\x0ecomposeProject\x12'\n" +
+	"\x0fcompose_service\x18\v \x01(\tR\x0ecomposeService\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\f \x03(\tR\tdependsOn\x12/\n" +
+	"\x04spec\x18\r \x01(\v2\x1b.orchestrator.ContainerSpecR\x04spec\x12\x14\n" +
+	"\x05state\x18\x0e \x01(\tR\x05state\x12\x16\n" +
+	"\x06health\x18\x0f \x01(\tR\x06health\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa5\x06\n" +
+	"\rContainerSpec\x12\x10\n" +
+	"\x03env\x18\x01 \x03(\tR\x03env\x12/\n" +
+	"\x05ports\x18\x02 \x03(\v2\x19.orchestrator.PortMappingR\x05ports\x12/\n" +
+	"\x06mounts\x18\x03 \x03(\v2\x17.orchestrator.MountSpecR\x06mounts\x12?\n" +
+	"\x06labels\x18\x04 \x03(\v2'.orchestrator.ContainerSpec.LabelsEntryR\x06labels\x12B\n" +
+	"\x0erestart_policy\x18\x05 \x01(\v2\x1b.orchestrator.RestartPolicyR\rrestartPolicy\x125\n" +
+	"\tresources\x18\x06 \x01(\v2\x17.orchestrator.ResourcesR\tresources\x12\x17\n" +
+	"\acap_add\x18\a \x03(\tR\x06capAdd\x12\x19\n" +
+	"\bcap_drop\x18\b \x03(\tR\acapDrop\x125\n" +
+	"\adevices\x18\t \x03(\v2\x1b.orchestrator.DeviceMappingR\adevices\x12;\n" +
+	"\vhealthcheck\x18\n" +
+	" \x01(\v2\x19.orchestrator.HealthcheckR\vhealthcheck\x12\x12\n" +
+	"\x04user\x18\v \x01(\tR\x04user\x12\x1e\n" +
+	"\n" +
+	"entrypoint\x18\f \x03(\tR\n" +
+	"entrypoint\x12\x10\n" +
+	"\x03cmd\x18\r \x03(\tR\x03cmd\x12!\n" +
+	"\fnetwork_mode\x18\x0e \x01(\tR\vnetworkMode\x12;\n" +
+	"\bnetworks\x18\x0f \x03(\v2\x1f.orchestrator.NetworkAttachmentR\bnetworks\x12\x1f\n" +
+	"\vworking_dir\x18\x10 \x01(\tR\n" +
+	"workingDir\x12\x1a\n" +
+	"\bhostname\x18\x11 \x01(\tR\bhostname\x12\x1e\n" +
+	"\n" +
+	"privileged\x18\x12 \x01(\bR\n" +
+	"privileged\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x02\n" +
+	"\tMountSpec\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x03 \x01(\tR\x06target\x12\x1b\n" +
+	"\tread_only\x18\x04 \x01(\bR\breadOnly\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\x12 \n" +
+	"\vpropagation\x18\x06 \x01(\tR\vpropagation\x120\n" +
+	"\x05tmpfs\x18\a \x01(\v2\x1a.orchestrator.TmpfsOptionsR\x05tmpfs\x123\n" +
+	"\x06volume\x18\b \x01(\v2\x1b.orchestrator.VolumeOptionsR\x06volume\"[\n" +
+	"\fTmpfsOptions\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x01 \x01(\x03R\tsizeBytes\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\rR\x04mode\x12\x18\n" +
+	"\aoptions\x18\x03 \x03(\tR\aoptions\"\xef\x02\n" +
+	"\rVolumeOptions\x12\x17\n" +
+	"\ano_copy\x18\x01 \x01(\bR\x06noCopy\x12?\n" +
+	"\x06labels\x18\x02 \x03(\v2'.orchestrator.VolumeOptions.LabelsEntryR\x06labels\x12\x18\n" +
+	"\asubpath\x18\x03 \x01(\tR\asubpath\x12\x16\n" +
+	"\x06driver\x18\x04 \x01(\tR\x06driver\x12U\n" +
+	"\x0edriver_options\x18\x05 \x03(\v2..orchestrator.VolumeOptions.DriverOptionsEntryR\rdriverOptions\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a@\n" +
+	"\x12DriverOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\rRestartPolicy\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12.\n" +
+	"\x13maximum_retry_count\x18\x02 \x01(\x05R\x11maximumRetryCount\"\xc0\x01\n" +
+	"\tResources\x12\x16\n" +
+	"\x06memory\x18\x01 \x01(\x03R\x06memory\x12\x1f\n" +
+	"\vmemory_swap\x18\x02 \x01(\x03R\n" +
+	"memorySwap\x12\x1b\n" +
+	"\tnano_cpus\x18\x03 \x01(\x03R\bnanoCpus\x12\x1d\n" +
+	"\n" +
+	"cpu_shares\x18\x04 \x01(\x03R\tcpuShares\x12\x1d\n" +
+	"\n" +
+	"pids_limit\x18\x05 \x01(\x03R\tpidsLimit\x12\x1f\n" +
+	"\vcpuset_cpus\x18\x06 \x01(\tR\n" +
+	"cpusetCpus\"\x8c\x01\n" +
+	"\rDeviceMapping\x12 \n" +
+	"\fpath_on_host\x18\x01 \x01(\tR\n" +
+	"pathOnHost\x12*\n" +
+	"\x11path_in_container\x18\x02 \x01(\tR\x0fpathInContainer\x12-\n" +
+	"\x12cgroup_permissions\x18\x03 \x01(\tR\x11cgroupPermissions\"\xa3\x01\n" +
+	"\vHealthcheck\x12\x12\n" +
+	"\x04test\x18\x01 \x03(\tR\x04test\x12\x1f\n" +
+	"\vinterval_ns\x18\x02 \x01(\x03R\n" +
+	"intervalNs\x12\x1d\n" +
+	"\n" +
+	"timeout_ns\x18\x03 \x01(\x03R\ttimeoutNs\x12&\n" +
+	"\x0fstart_period_ns\x18\x04 \x01(\x03R\rstartPeriodNs\x12\x18\n" +
+	"\aretries\x18\x05 \x01(\x05R\aretries\"\x87\x01\n" +
+	"\x11NetworkAttachment\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aaliases\x18\x02 \x03(\tR\aaliases\x12!\n" +
+	"\fipv4_address\x18\x03 \x01(\tR\vipv4Address\x12!\n" +
+	"\fipv6_address\x18\x04 \x01(\tR\vipv6Address\"\xe5\x01\n" +
 	"\bHostInfo\x12\x1f\n" +
 	"\vmac_address\x18\x01 \x01(\tR\n" +
 	"macAddress\x12\x1a\n" +
@@ -716,38 +2808,109 @@ const file_host_agent_proto_rawDesc = "" +
 	"ip_address\x18\x03 \x01(\tR\tipAddress\x12;\n" +
 	"\n" +
 	"containers\x18\x05 \x03(\v2\x1b.orchestrator.ContainerInfoR\n" +
-	"containers\"A\n" +
+	"containers\x12\x19\n" +
+	"\bagent_id\x18\x06 \x01(\tR\aagentId\x12%\n" +
+	"\x0einventory_hash\x18\a \x01(\tR\rinventoryHash\"A\n" +
 	"\x13RegisterHostRequest\x12*\n" +
 	"\x04host\x18\x01 \x01(\v2\x16.orchestrator.HostInfoR\x04host\"J\n" +
 	"\x14RegisterHostResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"p\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9d\x02\n" +
 	"\x10HeartbeatRequest\x12\x1f\n" +
 	"\vmac_address\x18\x01 \x01(\tR\n" +
 	"macAddress\x12;\n" +
 	"\n" +
 	"containers\x18\x03 \x03(\v2\x1b.orchestrator.ContainerInfoR\n" +
-	"containers\"G\n" +
+	"containers\x12\x19\n" +
+	"\bagent_id\x18\x04 \x01(\tR\aagentId\x12\x18\n" +
+	"\apartial\x18\x05 \x01(\bR\apartial\x122\n" +
+	"\x15removed_container_ids\x18\x06 \x03(\tR\x13removedContainerIds\x12%\n" +
+	"\x0einventory_hash\x18\a \x01(\tR\rinventoryHash\x12\x1b\n" +
+	"\tbase_hash\x18\b \x01(\tR\bbaseHash\"\x97\x01\n" +
 	"\x11HeartbeatResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xb2\x02\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x0einventory_hash\x18\x03 \x01(\tR\rinventoryHash\x12'\n" +
+	"\x0fresync_required\x18\x04 \x01(\bR\x0eresyncRequired\"\xa3\x04\n" +
 	"\x16UpdateContainerCommand\x12\"\n" +
 	"\fcontainerUID\x18\x02 \x01(\tR\fcontainerUID\x12\x14\n" +
 	"\x05image\x18\x03 \x01(\tR\x05image\x12(\n" +
 	"\x0foverrideEnvVars\x18\x04 \x03(\tR\x0foverrideEnvVars\x12?\n" +
 	"\roverridePorts\x18\x05 \x03(\v2\x19.orchestrator.PortMappingR\roverridePorts\x12(\n" +
 	"\x0foverrideVolumes\x18\x06 \x03(\tR\x0foverrideVolumes\x12(\n" +
-	"\x0foverrideNetwork\x18\a \x01(\tR\x0foverrideNetwork\x12\x1f\n" +
-	"\vmac_address\x18\b \x01(\tR\n" +
-	"macAddress\"\xcc\x02\n" +
+	"\x0foverrideNetwork\x18\a \x01(\tR\x0foverrideNetwork\x12\x19\n" +
+	"\bagent_id\x18\b \x01(\tR\aagentId\x12%\n" +
+	"\x0econtainer_name\x18\t \x01(\tR\rcontainerName\x12\x16\n" +
+	"\x06digest\x18\n" +
+	" \x01(\tR\x06digest\x12(\n" +
+	"\x05hooks\x18\v \x03(\v2\x12.orchestrator.HookR\x05hooks\x12<\n" +
+	"\fhealth_check\x18\f \x01(\v2\x19.orchestrator.HealthCheckR\vhealthCheck\x12/\n" +
+	"\x04spec\x18\r \x01(\v2\x1b.orchestrator.ContainerSpecR\x04spec\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x0e \x01(\tR\tcommandId\"l\n" +
+	"\fCancelUpdate\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x01 \x01(\tR\tcommandId\x12%\n" +
+	"\x0econtainer_name\x18\x02 \x01(\tR\rcontainerName\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xe2\x01\n" +
+	"\vHealthCheck\x122\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1e.orchestrator.HealthCheck.KindR\x04kind\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12'\n" +
+	"\x0ftimeout_seconds\x18\x03 \x01(\x05R\x0etimeoutSeconds\x12#\n" +
+	"\rgrace_seconds\x18\x04 \x01(\x05R\fgraceSeconds\"9\n" +
+	"\x04Kind\x12\n" +
+	"\n" +
+	"\x06DOCKER\x10\x00\x12\b\n" +
+	"\x04HTTP\x10\x01\x12\a\n" +
+	"\x03TCP\x10\x02\x12\b\n" +
+	"\x04EXEC\x10\x03\x12\b\n" +
+	"\x04NONE\x10\x04\"\x95\x03\n" +
+	"\x04Hook\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12.\n" +
+	"\x05phase\x18\x02 \x01(\x0e2\x18.orchestrator.Hook.PhaseR\x05phase\x12+\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x17.orchestrator.Hook.KindR\x04kind\x12\x18\n" +
+	"\acommand\x18\x04 \x01(\tR\acommand\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12\x16\n" +
+	"\x06method\x18\x06 \x01(\tR\x06method\x12'\n" +
+	"\x0ftimeout_seconds\x18\a \x01(\x05R\x0etimeoutSeconds\x12;\n" +
+	"\n" +
+	"on_failure\x18\b \x01(\x0e2\x1c.orchestrator.Hook.OnFailureR\tonFailure\"\x1a\n" +
+	"\x05Phase\x12\a\n" +
+	"\x03PRE\x10\x00\x12\b\n" +
+	"\x04POST\x10\x01\"$\n" +
+	"\x04Kind\x12\b\n" +
+	"\x04EXEC\x10\x00\x12\b\n" +
+	"\x04HOST\x10\x01\x12\b\n" +
+	"\x04HTTP\x10\x02\"0\n" +
+	"\tOnFailure\x12\t\n" +
+	"\x05ABORT\x10\x00\x12\f\n" +
+	"\bROLLBACK\x10\x01\x12\n" +
+	"\n" +
+	"\x06IGNORE\x10\x02\"V\n" +
+	"\x0ePreflightCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06passed\x18\x02 \x01(\bR\x06passed\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xaa\x06\n" +
 	"\fUpdateStatus\x12\"\n" +
 	"\fcontainerUID\x18\x02 \x01(\tR\fcontainerUID\x12\x14\n" +
-	"\x05image\x18\a \x01(\tR\x05image\x12\x1f\n" +
-	"\vmac_address\x18\x06 \x01(\tR\n" +
-	"macAddress\x126\n" +
+	"\x05image\x18\a \x01(\tR\x05image\x12\x19\n" +
+	"\bagent_id\x18\x06 \x01(\tR\aagentId\x12%\n" +
+	"\x0econtainer_name\x18\b \x01(\tR\rcontainerName\x12%\n" +
+	"\x0eprevious_image\x18\t \x01(\tR\rpreviousImage\x12'\n" +
+	"\x0fprevious_digest\x18\n" +
+	" \x01(\tR\x0epreviousDigest\x12!\n" +
+	"\fimage_digest\x18\v \x01(\tR\vimageDigest\x12#\n" +
+	"\rtarget_digest\x18\f \x01(\tR\ftargetDigest\x12*\n" +
+	"\x11new_container_uid\x18\r \x01(\tR\x0fnewContainerUid\x128\n" +
+	"\bstrategy\x18\x0e \x01(\x0e2\x1c.orchestrator.UpdateStrategyR\bstrategy\x12\x1f\n" +
+	"\vqueue_depth\x18\x0f \x01(\rR\n" +
+	"queueDepth\x12\x1d\n" +
+	"\n" +
+	"command_id\x18\x10 \x01(\tR\tcommandId\x12:\n" +
+	"\tpreflight\x18\x11 \x03(\v2\x1c.orchestrator.PreflightCheckR\tpreflight\x126\n" +
 	"\x05stage\x18\x03 \x01(\x0e2 .orchestrator.UpdateStatus.StageR\x05stage\x12\x12\n" +
 	"\x04logs\x18\x04 \x01(\tR\x04logs\x12\x1c\n" +
-	"\ttimestamp\x18\x05 \x01(\tR\ttimestamp\"w\n" +
+	"\ttimestamp\x18\x05 \x01(\tR\ttimestamp\"\xb9\x01\n" +
 	"\x05Stage\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aPULLING\x10\x01\x12\f\n" +
@@ -757,11 +2920,53 @@ const file_host_agent_proto_rawDesc = "" +
 	"\bROLLBACK\x10\x05\x12\n" +
 	"\n" +
 	"\x06FAILED\x10\x06\x12\v\n" +
-	"\aRUNNING\x10\a2\x93\x02\n" +
+	"\aRUNNING\x10\a\x12\b\n" +
+	"\x04HOOK\x10\b\x12\n" +
+	"\n" +
+	"\x06QUEUED\x10\t\x12\f\n" +
+	"\bREJECTED\x10\n" +
+	"\x12\r\n" +
+	"\tCANCELLED\x10\v\x12\r\n" +
+	"\tPREFLIGHT\x10\f\"\xf5\x01\n" +
+	"\n" +
+	"AgentHello\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12#\n" +
+	"\ragent_version\x18\x02 \x01(\tR\fagentVersion\x12)\n" +
+	"\x10protocol_version\x18\x03 \x01(\rR\x0fprotocolVersion\x12\x0e\n" +
+	"\x02os\x18\x04 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x05 \x01(\tR\x04arch\x12%\n" +
+	"\x0edocker_version\x18\x06 \x01(\tR\rdockerVersion\x121\n" +
+	"\bfeatures\x18\a \x03(\x0e2\x15.orchestrator.FeatureR\bfeatures\"\xda\x01\n" +
+	"\x11OrchestratorHello\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x121\n" +
+	"\x14orchestrator_version\x18\x03 \x01(\tR\x13orchestratorVersion\x12)\n" +
+	"\x10protocol_version\x18\x04 \x01(\rR\x0fprotocolVersion\x121\n" +
+	"\bfeatures\x18\x05 \x03(\x0e2\x15.orchestrator.FeatureR\bfeatures\"\x81\x01\n" +
+	"\fAgentMessage\x120\n" +
+	"\x05hello\x18\x01 \x01(\v2\x18.orchestrator.AgentHelloH\x00R\x05hello\x124\n" +
+	"\x06status\x18\x10 \x01(\v2\x1a.orchestrator.UpdateStatusH\x00R\x06statusB\t\n" +
+	"\amessage\"\xd1\x01\n" +
+	"\x13OrchestratorMessage\x127\n" +
+	"\x05hello\x18\x01 \x01(\v2\x1f.orchestrator.OrchestratorHelloH\x00R\x05hello\x12@\n" +
+	"\acommand\x18\x02 \x01(\v2$.orchestrator.UpdateContainerCommandH\x00R\acommand\x124\n" +
+	"\x06cancel\x18\x03 \x01(\v2\x1a.orchestrator.CancelUpdateH\x00R\x06cancelB\t\n" +
+	"\amessage*1\n" +
+	"\x0eUpdateStrategy\x12\x0e\n" +
+	"\n" +
+	"STOP_FIRST\x10\x00\x12\x0f\n" +
+	"\vSTART_FIRST\x10\x01*\x92\x01\n" +
+	"\aFeature\x12\x17\n" +
+	"\x13FEATURE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15FEATURE_HEALTH_CHECKS\x10\x01\x12\x11\n" +
+	"\rFEATURE_HOOKS\x10\x02\x12\x13\n" +
+	"\x0fFEATURE_COMPOSE\x10\x03\x12\x17\n" +
+	"\x13FEATURE_START_FIRST\x10\x04\x12\x12\n" +
+	"\x0eFEATURE_CANCEL\x10\x052\x90\x02\n" +
 	"\x10HostAgentService\x12U\n" +
 	"\fRegisterHost\x12!.orchestrator.RegisterHostRequest\x1a\".orchestrator.RegisterHostResponse\x12L\n" +
-	"\tHeartbeat\x12\x1e.orchestrator.HeartbeatRequest\x1a\x1f.orchestrator.HeartbeatResponse\x12Z\n" +
-	"\x12ConnectAgentStream\x12\x1a.orchestrator.UpdateStatus\x1a$.orchestrator.UpdateContainerCommand(\x010\x01BBZ@github.com/MadhavKrishanGoswami/Lighthouse/services/orchestratorb\x06proto3"
+	"\tHeartbeat\x12\x1e.orchestrator.HeartbeatRequest\x1a\x1f.orchestrator.HeartbeatResponse\x12W\n" +
+	"\x12ConnectAgentStream\x12\x1a.orchestrator.AgentMessage\x1a!.orchestrator.OrchestratorMessage(\x010\x01BBZ@github.com/MadhavKrishanGoswami/Lighthouse/services/orchestratorb\x06proto3"
 
 var (
 	file_host_agent_proto_rawDescOnce sync.Once
@@ -775,38 +2980,95 @@ func file_host_agent_proto_rawDescGZIP() []byte {
 	return file_host_agent_proto_rawDescData
 }
 
-var file_host_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_host_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_host_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_host_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_host_agent_proto_goTypes = []any{
-	(UpdateStatus_Stage)(0),        // 0: orchestrator.UpdateStatus.Stage
-	(*PortMapping)(nil),            // 1: orchestrator.PortMapping
-	(*ContainerInfo)(nil),          // 2: orchestrator.ContainerInfo
-	(*HostInfo)(nil),               // 3: orchestrator.HostInfo
-	(*RegisterHostRequest)(nil),    // 4: orchestrator.RegisterHostRequest
-	(*RegisterHostResponse)(nil),   // 5: orchestrator.RegisterHostResponse
-	(*HeartbeatRequest)(nil),       // 6: orchestrator.HeartbeatRequest
-	(*HeartbeatResponse)(nil),      // 7: orchestrator.HeartbeatResponse
-	(*UpdateContainerCommand)(nil), // 8: orchestrator.UpdateContainerCommand
-	(*UpdateStatus)(nil),           // 9: orchestrator.UpdateStatus
+	(UpdateStrategy)(0),            // 0: orchestrator.UpdateStrategy
+	(Feature)(0),                   // 1: orchestrator.Feature
+	(HealthCheck_Kind)(0),          // 2: orchestrator.HealthCheck.Kind
+	(Hook_Phase)(0),                // 3: orchestrator.Hook.Phase
+	(Hook_Kind)(0),                 // 4: orchestrator.Hook.Kind
+	(Hook_OnFailure)(0),            // 5: orchestrator.Hook.OnFailure
+	(UpdateStatus_Stage)(0),        // 6: orchestrator.UpdateStatus.Stage
+	(*PortMapping)(nil),            // 7: orchestrator.PortMapping
+	(*ContainerInfo)(nil),          // 8: orchestrator.ContainerInfo
+	(*ContainerSpec)(nil),          // 9: orchestrator.ContainerSpec
+	(*MountSpec)(nil),              // 10: orchestrator.MountSpec
+	(*TmpfsOptions)(nil),           // 11: orchestrator.TmpfsOptions
+	(*VolumeOptions)(nil),          // 12: orchestrator.VolumeOptions
+	(*RestartPolicy)(nil),          // 13: orchestrator.RestartPolicy
+	(*Resources)(nil),              // 14: orchestrator.Resources
+	(*DeviceMapping)(nil),          // 15: orchestrator.DeviceMapping
+	(*Healthcheck)(nil),            // 16: orchestrator.Healthcheck
+	(*NetworkAttachment)(nil),      // 17: orchestrator.NetworkAttachment
+	(*HostInfo)(nil),               // 18: orchestrator.HostInfo
+	(*RegisterHostRequest)(nil),    // 19: orchestrator.RegisterHostRequest
+	(*RegisterHostResponse)(nil),   // 20: orchestrator.RegisterHostResponse
+	(*HeartbeatRequest)(nil),       // 21: orchestrator.HeartbeatRequest
+	(*HeartbeatResponse)(nil),      // 22: orchestrator.HeartbeatResponse
+	(*UpdateContainerCommand)(nil), // 23: orchestrator.UpdateContainerCommand
+	(*CancelUpdate)(nil),           // 24: orchestrator.CancelUpdate
+	(*HealthCheck)(nil),            // 25: orchestrator.HealthCheck
+	(*Hook)(nil),                   // 26: orchestrator.Hook
+	(*PreflightCheck)(nil),         // 27: orchestrator.PreflightCheck
+	(*UpdateStatus)(nil),           // 28: orchestrator.UpdateStatus
+	(*AgentHello)(nil),             // 29: orchestrator.AgentHello
+	(*OrchestratorHello)(nil),      // 30: orchestrator.OrchestratorHello
+	(*AgentMessage)(nil),           // 31: orchestrator.AgentMessage
+	(*OrchestratorMessage)(nil),    // 32: orchestrator.OrchestratorMessage
+	nil,                            // 33: orchestrator.ContainerInfo.LabelsEntry
+	nil,                            // 34: orchestrator.ContainerSpec.LabelsEntry
+	nil,                            // 35: orchestrator.VolumeOptions.LabelsEntry
+	nil,                            // 36: orchestrator.VolumeOptions.DriverOptionsEntry
 }
 var file_host_agent_proto_depIdxs = []int32{
-	1, // 0: orchestrator.ContainerInfo.ports:type_name -> orchestrator.PortMapping
-	2, // 1: orchestrator.HostInfo.containers:type_name -> orchestrator.ContainerInfo
-	3, // 2: orchestrator.RegisterHostRequest.host:type_name -> orchestrator.HostInfo
-	2, // 3: orchestrator.HeartbeatRequest.containers:type_name -> orchestrator.ContainerInfo
-	1, // 4: orchestrator.UpdateContainerCommand.overridePorts:type_name -> orchestrator.PortMapping
-	0, // 5: orchestrator.UpdateStatus.stage:type_name -> orchestrator.UpdateStatus.Stage
-	4, // 6: orchestrator.HostAgentService.RegisterHost:input_type -> orchestrator.RegisterHostRequest
-	6, // 7: orchestrator.HostAgentService.Heartbeat:input_type -> orchestrator.HeartbeatRequest
-	9, // 8: orchestrator.HostAgentService.ConnectAgentStream:input_type -> orchestrator.UpdateStatus
-	5, // 9: orchestrator.HostAgentService.RegisterHost:output_type -> orchestrator.RegisterHostResponse
-	7, // 10: orchestrator.HostAgentService.Heartbeat:output_type -> orchestrator.HeartbeatResponse
-	8, // 11: orchestrator.HostAgentService.ConnectAgentStream:output_type -> orchestrator.UpdateContainerCommand
-	9, // [9:12] is the sub-list for method output_type
-	6, // [6:9] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	7,  // 0: orchestrator.ContainerInfo.ports:type_name -> orchestrator.PortMapping
+	33, // 1: orchestrator.ContainerInfo.labels:type_name -> orchestrator.ContainerInfo.LabelsEntry
+	9,  // 2: orchestrator.ContainerInfo.spec:type_name -> orchestrator.ContainerSpec
+	7,  // 3: orchestrator.ContainerSpec.ports:type_name -> orchestrator.PortMapping
+	10, // 4: orchestrator.ContainerSpec.mounts:type_name -> orchestrator.MountSpec
+	34, // 5: orchestrator.ContainerSpec.labels:type_name -> orchestrator.ContainerSpec.LabelsEntry
+	13, // 6: orchestrator.ContainerSpec.restart_policy:type_name -> orchestrator.RestartPolicy
+	14, // 7: orchestrator.ContainerSpec.resources:type_name -> orchestrator.Resources
+	15, // 8: orchestrator.ContainerSpec.devices:type_name -> orchestrator.DeviceMapping
+	16, // 9: orchestrator.ContainerSpec.healthcheck:type_name -> orchestrator.Healthcheck
+	17, // 10: orchestrator.ContainerSpec.networks:type_name -> orchestrator.NetworkAttachment
+	11, // 11: orchestrator.MountSpec.tmpfs:type_name -> orchestrator.TmpfsOptions
+	12, // 12: orchestrator.MountSpec.volume:type_name -> orchestrator.VolumeOptions
+	35, // 13: orchestrator.VolumeOptions.labels:type_name -> orchestrator.VolumeOptions.LabelsEntry
+	36, // 14: orchestrator.VolumeOptions.driver_options:type_name -> orchestrator.VolumeOptions.DriverOptionsEntry
+	8,  // 15: orchestrator.HostInfo.containers:type_name -> orchestrator.ContainerInfo
+	18, // 16: orchestrator.RegisterHostRequest.host:type_name -> orchestrator.HostInfo
+	8,  // 17: orchestrator.HeartbeatRequest.containers:type_name -> orchestrator.ContainerInfo
+	7,  // 18: orchestrator.UpdateContainerCommand.overridePorts:type_name -> orchestrator.PortMapping
+	26, // 19: orchestrator.UpdateContainerCommand.hooks:type_name -> orchestrator.Hook
+	25, // 20: orchestrator.UpdateContainerCommand.health_check:type_name -> orchestrator.HealthCheck
+	9,  // 21: orchestrator.UpdateContainerCommand.spec:type_name -> orchestrator.ContainerSpec
+	2,  // 22: orchestrator.HealthCheck.kind:type_name -> orchestrator.HealthCheck.Kind
+	3,  // 23: orchestrator.Hook.phase:type_name -> orchestrator.Hook.Phase
+	4,  // 24: orchestrator.Hook.kind:type_name -> orchestrator.Hook.Kind
+	5,  // 25: orchestrator.Hook.on_failure:type_name -> orchestrator.Hook.OnFailure
+	0,  // 26: orchestrator.UpdateStatus.strategy:type_name -> orchestrator.UpdateStrategy
+	27, // 27: orchestrator.UpdateStatus.preflight:type_name -> orchestrator.PreflightCheck
+	6,  // 28: orchestrator.UpdateStatus.stage:type_name -> orchestrator.UpdateStatus.Stage
+	1,  // 29: orchestrator.AgentHello.features:type_name -> orchestrator.Feature
+	1,  // 30: orchestrator.OrchestratorHello.features:type_name -> orchestrator.Feature
+	29, // 31: orchestrator.AgentMessage.hello:type_name -> orchestrator.AgentHello
+	28, // 32: orchestrator.AgentMessage.status:type_name -> orchestrator.UpdateStatus
+	30, // 33: orchestrator.OrchestratorMessage.hello:type_name -> orchestrator.OrchestratorHello
+	23, // 34: orchestrator.OrchestratorMessage.command:type_name -> orchestrator.UpdateContainerCommand
+	24, // 35: orchestrator.OrchestratorMessage.cancel:type_name -> orchestrator.CancelUpdate
+	19, // 36: orchestrator.HostAgentService.RegisterHost:input_type -> orchestrator.RegisterHostRequest
+	21, // 37: orchestrator.HostAgentService.Heartbeat:input_type -> orchestrator.HeartbeatRequest
+	31, // 38: orchestrator.HostAgentService.ConnectAgentStream:input_type -> orchestrator.AgentMessage
+	20, // 39: orchestrator.HostAgentService.RegisterHost:output_type -> orchestrator.RegisterHostResponse
+	22, // 40: orchestrator.HostAgentService.Heartbeat:output_type -> orchestrator.HeartbeatResponse
+	32, // 41: orchestrator.HostAgentService.ConnectAgentStream:output_type -> orchestrator.OrchestratorMessage
+	39, // [39:42] is the sub-list for method output_type
+	36, // [36:39] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_host_agent_proto_init() }
@@ -814,13 +3076,22 @@ func file_host_agent_proto_init() {
 	if File_host_agent_proto != nil {
 		return
 	}
+	file_host_agent_proto_msgTypes[24].OneofWrappers = []any{
+		(*AgentMessage_Hello)(nil),
+		(*AgentMessage_Status)(nil),
+	}
+	file_host_agent_proto_msgTypes[25].OneofWrappers = []any{
+		(*OrchestratorMessage_Hello)(nil),
+		(*OrchestratorMessage_Command)(nil),
+		(*OrchestratorMessage_Cancel)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_host_agent_proto_rawDesc), len(file_host_agent_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      7,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
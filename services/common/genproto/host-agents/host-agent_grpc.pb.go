@@ -33,9 +33,10 @@ type HostAgentServiceClient interface {
 	// Agent -> Orchestrator: Periodic heartbeat (unary)
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	// Agent -> Orchestrator: Initiates bidirectional stream
-	// Orchestrator can send UpdateContainerCommand anytime after stream is open
+	// The agent opens with an AgentHello and the orchestrator answers with an OrchestratorHello.
+	// Orchestrator can send UpdateContainerCommand anytime after the handshake
 	// Agent responds with UpdateStatus for each command
-	ConnectAgentStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UpdateStatus, UpdateContainerCommand], error)
+	ConnectAgentStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AgentMessage, OrchestratorMessage], error)
 }
 
 type hostAgentServiceClient struct {
@@ -66,18 +67,18 @@ func (c *hostAgentServiceClient) Heartbeat(ctx context.Context, in *HeartbeatReq
 	return out, nil
 }
 
-func (c *hostAgentServiceClient) ConnectAgentStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UpdateStatus, UpdateContainerCommand], error) {
+func (c *hostAgentServiceClient) ConnectAgentStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AgentMessage, OrchestratorMessage], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &HostAgentService_ServiceDesc.Streams[0], HostAgentService_ConnectAgentStream_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[UpdateStatus, UpdateContainerCommand]{ClientStream: stream}
+	x := &grpc.GenericClientStream[AgentMessage, OrchestratorMessage]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type HostAgentService_ConnectAgentStreamClient = grpc.BidiStreamingClient[UpdateStatus, UpdateContainerCommand]
+type HostAgentService_ConnectAgentStreamClient = grpc.BidiStreamingClient[AgentMessage, OrchestratorMessage]
 
 // HostAgentServiceServer is the server API for HostAgentService service.
 // All implementations must embed UnimplementedHostAgentServiceServer
@@ -88,9 +89,10 @@ type HostAgentServiceServer interface {
 	// Agent -> Orchestrator: Periodic heartbeat (unary)
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	// Agent -> Orchestrator: Initiates bidirectional stream
-	// Orchestrator can send UpdateContainerCommand anytime after stream is open
+	// The agent opens with an AgentHello and the orchestrator answers with an OrchestratorHello.
+	// Orchestrator can send UpdateContainerCommand anytime after the handshake
 	// Agent responds with UpdateStatus for each command
-	ConnectAgentStream(grpc.BidiStreamingServer[UpdateStatus, UpdateContainerCommand]) error
+	ConnectAgentStream(grpc.BidiStreamingServer[AgentMessage, OrchestratorMessage]) error
 	mustEmbedUnimplementedHostAgentServiceServer()
 }
 
@@ -107,7 +109,7 @@ func (UnimplementedHostAgentServiceServer) RegisterHost(context.Context, *Regist
 func (UnimplementedHostAgentServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
 }
-func (UnimplementedHostAgentServiceServer) ConnectAgentStream(grpc.BidiStreamingServer[UpdateStatus, UpdateContainerCommand]) error {
+func (UnimplementedHostAgentServiceServer) ConnectAgentStream(grpc.BidiStreamingServer[AgentMessage, OrchestratorMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method ConnectAgentStream not implemented")
 }
 func (UnimplementedHostAgentServiceServer) mustEmbedUnimplementedHostAgentServiceServer() {}
@@ -168,11 +170,11 @@ func _HostAgentService_Heartbeat_Handler(srv interface{}, ctx context.Context, d
 }
 
 func _HostAgentService_ConnectAgentStream_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(HostAgentServiceServer).ConnectAgentStream(&grpc.GenericServerStream[UpdateStatus, UpdateContainerCommand]{ServerStream: stream})
+	return srv.(HostAgentServiceServer).ConnectAgentStream(&grpc.GenericServerStream[AgentMessage, OrchestratorMessage]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type HostAgentService_ConnectAgentStreamServer = grpc.BidiStreamingServer[UpdateStatus, UpdateContainerCommand]
+type HostAgentService_ConnectAgentStreamServer = grpc.BidiStreamingServer[AgentMessage, OrchestratorMessage]
 
 // HostAgentService_ServiceDesc is the grpc.ServiceDesc for HostAgentService service.
 // It's only intended for direct use with grpc.RegisterService,
@@ -26,9 +26,12 @@ const (
 // ==========================
 type ImageInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ContainerUid  string                 `protobuf:"bytes,1,opt,name=containerUid,proto3" json:"containerUid,omitempty"` // Unique ID for the container
-	Repository    string                 `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`     // e.g., "nginx"
-	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`                   // e.g., "1.25.0"
+	ContainerUid  string                 `protobuf:"bytes,1,opt,name=containerUid,proto3" json:"containerUid,omitempty"`                        // Unique ID for the container
+	Repository    string                 `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`                            // e.g., "nginx"
+	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`                                          // e.g., "1.25.0"
+	Policy        string                 `protobuf:"bytes,4,opt,name=policy,proto3" json:"policy,omitempty"`                                    // "patch", "minor", "major", "digest"; empty compares against "latest"
+	TagPattern    string                 `protobuf:"bytes,5,opt,name=tag_pattern,json=tagPattern,proto3" json:"tag_pattern,omitempty"`          // optional regular expression candidate tags must match; implies "major" without a policy
+	CurrentDigest string                 `protobuf:"bytes,6,opt,name=current_digest,json=currentDigest,proto3" json:"current_digest,omitempty"` // registry digest the container runs, used by the "digest" policy
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -84,6 +87,27 @@ func (x *ImageInfo) GetTag() string {
 	return ""
 }
 
+func (x *ImageInfo) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetTagPattern() string {
+	if x != nil {
+		return x.TagPattern
+	}
+	return ""
+}
+
+func (x *ImageInfo) GetCurrentDigest() string {
+	if x != nil {
+		return x.CurrentDigest
+	}
+	return ""
+}
+
 type CheckUpdatesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Images        []*ImageInfo           `protobuf:"bytes,1,rep,name=images,proto3" json:"images,omitempty"` // List of images currently in use
@@ -134,6 +158,7 @@ type ImagetoUpdate struct {
 	NewTag        string                 `protobuf:"bytes,3,opt,name=newTag,proto3" json:"newTag,omitempty"`             // e.g., "1.25.1"
 	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`   // Optional info, e.g., "Patch release available"
 	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`      // Unix timestamp when update was detected
+	Digest        string                 `protobuf:"bytes,6,opt,name=digest,proto3" json:"digest,omitempty"`             // registry digest of the new image, e.g. "sha256:..."
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -196,6 +221,13 @@ func (x *ImagetoUpdate) GetTimestamp() int64 {
 	return 0
 }
 
+func (x *ImagetoUpdate) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
 type CheckUpdatesResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ImagestoUpdate []*ImagetoUpdate       `protobuf:"bytes,1,rep,name=ImagestoUpdate,proto3" json:"ImagestoUpdate,omitempty"` // Only images with updates
@@ -244,20 +276,25 @@ var File_registry_monitor_proto protoreflect.FileDescriptor
 
 const file_registry_monitor_proto_rawDesc = "" +
 	"\n" +
-	"\x16registry-monitor.proto\x12\x0fregistrymonitor\"a\n" +
+	"\x16registry-monitor.proto\x12\x0fregistrymonitor\"\xc1\x01\n" +
 	"\tImageInfo\x12\"\n" +
 	"\fcontainerUid\x18\x01 \x01(\tR\fcontainerUid\x12\x1e\n" +
 	"\n" +
 	"repository\x18\x02 \x01(\tR\n" +
 	"repository\x12\x10\n" +
-	"\x03tag\x18\x03 \x01(\tR\x03tag\"I\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06policy\x18\x04 \x01(\tR\x06policy\x12\x1f\n" +
+	"\vtag_pattern\x18\x05 \x01(\tR\n" +
+	"tagPattern\x12%\n" +
+	"\x0ecurrent_digest\x18\x06 \x01(\tR\rcurrentDigest\"I\n" +
 	"\x13CheckUpdatesRequest\x122\n" +
-	"\x06images\x18\x01 \x03(\v2\x1a.registrymonitor.ImageInfoR\x06images\"\x8b\x01\n" +
+	"\x06images\x18\x01 \x03(\v2\x1a.registrymonitor.ImageInfoR\x06images\"\xa3\x01\n" +
 	"\rImagetoUpdate\x12\"\n" +
 	"\fcontainerUid\x18\x01 \x01(\tR\fcontainerUid\x12\x16\n" +
 	"\x06newTag\x18\x03 \x01(\tR\x06newTag\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1c\n" +
-	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"^\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x16\n" +
+	"\x06digest\x18\x06 \x01(\tR\x06digest\"^\n" +
 	"\x14CheckUpdatesResponse\x12F\n" +
 	"\x0eImagestoUpdate\x18\x01 \x03(\v2\x1e.registrymonitor.ImagetoUpdateR\x0eImagestoUpdate2u\n" +
 	"\x16RegistryMonitorService\x12[\n" +
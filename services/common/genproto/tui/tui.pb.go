@@ -128,17 +128,29 @@ func (x ServicesStatusServices) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ServicesStatusServices.Descriptor instead.
 func (ServicesStatusServices) EnumDescriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{3, 0}
+	return file_tui_proto_rawDescGZIP(), []int{7, 0}
 }
 
 type ContainerInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Image         string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
-	Status        ContainerInfo_Status   `protobuf:"varint,3,opt,name=status,proto3,enum=tui.ContainerInfo_Status" json:"status,omitempty"`
-	Watch         bool                   `protobuf:"varint,4,opt,name=watch,proto3" json:"watch,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Image          string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	Status         ContainerInfo_Status   `protobuf:"varint,3,opt,name=status,proto3,enum=tui.ContainerInfo_Status" json:"status,omitempty"`
+	Watch          bool                   `protobuf:"varint,4,opt,name=watch,proto3" json:"watch,omitempty"`
+	LastDenial     *AdmissionDenial       `protobuf:"bytes,5,opt,name=last_denial,json=lastDenial,proto3" json:"last_denial,omitempty"` // set when the latest admission review denied an update
+	ContainerUid   string                 `protobuf:"bytes,6,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
+	Update         *UpdateProgress        `protobuf:"bytes,7,opt,name=update,proto3" json:"update,omitempty"`                                        // latest update status reported by the agent, if any
+	PreviousImage  string                 `protobuf:"bytes,8,opt,name=previous_image,json=previousImage,proto3" json:"previous_image,omitempty"`     // image a rollback would restore, empty when unknown
+	Pinned         bool                   `protobuf:"varint,9,opt,name=pinned,proto3" json:"pinned,omitempty"`                                       // automatic updates are suspended for the container
+	Quarantine     *Quarantine            `protobuf:"bytes,10,opt,name=quarantine,proto3" json:"quarantine,omitempty"`                               // set while a candidate image is quarantined after repeated failures
+	Policy         string                 `protobuf:"bytes,11,opt,name=policy,proto3" json:"policy,omitempty"`                                       // summary of the update policy from labels and the policy file, e.g. "minor, 02:00-05:00"
+	WatchSource    string                 `protobuf:"bytes,12,opt,name=watch_source,json=watchSource,proto3" json:"watch_source,omitempty"`          // where watch comes from: "default", "label", "file" or "tui"
+	Drift          string                 `protobuf:"bytes,13,opt,name=drift,proto3" json:"drift,omitempty"`                                         // set when a TUI override contradicts the policy file
+	Available      *AvailableUpdate       `protobuf:"bytes,14,opt,name=available,proto3" json:"available,omitempty"`                                 // newest update a check found, applied or not
+	ComposeProject string                 `protobuf:"bytes,15,opt,name=compose_project,json=composeProject,proto3" json:"compose_project,omitempty"` // Docker Compose project the container belongs to, if any
+	Health         string                 `protobuf:"bytes,16,opt,name=health,proto3" json:"health,omitempty"`                                       // Docker health: starting, healthy or unhealthy; empty without a HEALTHCHECK
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ContainerInfo) Reset() {
@@ -199,31 +211,114 @@ func (x *ContainerInfo) GetWatch() bool {
 	return false
 }
 
-type HostInfo struct {
+func (x *ContainerInfo) GetLastDenial() *AdmissionDenial {
+	if x != nil {
+		return x.LastDenial
+	}
+	return nil
+}
+
+func (x *ContainerInfo) GetContainerUid() string {
+	if x != nil {
+		return x.ContainerUid
+	}
+	return ""
+}
+
+func (x *ContainerInfo) GetUpdate() *UpdateProgress {
+	if x != nil {
+		return x.Update
+	}
+	return nil
+}
+
+func (x *ContainerInfo) GetPreviousImage() string {
+	if x != nil {
+		return x.PreviousImage
+	}
+	return ""
+}
+
+func (x *ContainerInfo) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *ContainerInfo) GetQuarantine() *Quarantine {
+	if x != nil {
+		return x.Quarantine
+	}
+	return nil
+}
+
+func (x *ContainerInfo) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *ContainerInfo) GetWatchSource() string {
+	if x != nil {
+		return x.WatchSource
+	}
+	return ""
+}
+
+func (x *ContainerInfo) GetDrift() string {
+	if x != nil {
+		return x.Drift
+	}
+	return ""
+}
+
+func (x *ContainerInfo) GetAvailable() *AvailableUpdate {
+	if x != nil {
+		return x.Available
+	}
+	return nil
+}
+
+func (x *ContainerInfo) GetComposeProject() string {
+	if x != nil {
+		return x.ComposeProject
+	}
+	return ""
+}
+
+func (x *ContainerInfo) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
+type AvailableUpdate struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MacAddress    string                 `protobuf:"bytes,1,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
-	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	IpAddress     string                 `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	LastHeartbeat string                 `protobuf:"bytes,4,opt,name=lastHeartbeat,proto3" json:"lastHeartbeat,omitempty"`
-	Containers    []*ContainerInfo       `protobuf:"bytes,5,rep,name=containers,proto3" json:"containers,omitempty"`
+	Image         string                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Digest        string                 `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	DetectedAt    string                 `protobuf:"bytes,4,opt,name=detected_at,json=detectedAt,proto3" json:"detected_at,omitempty"` // RFC3339, when this image and digest were first found
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostInfo) Reset() {
-	*x = HostInfo{}
+func (x *AvailableUpdate) Reset() {
+	*x = AvailableUpdate{}
 	mi := &file_tui_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostInfo) String() string {
+func (x *AvailableUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostInfo) ProtoMessage() {}
+func (*AvailableUpdate) ProtoMessage() {}
 
-func (x *HostInfo) ProtoReflect() protoreflect.Message {
+func (x *AvailableUpdate) ProtoReflect() protoreflect.Message {
 	mi := &file_tui_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -235,68 +330,1252 @@ func (x *HostInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostInfo.ProtoReflect.Descriptor instead.
-func (*HostInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use AvailableUpdate.ProtoReflect.Descriptor instead.
+func (*AvailableUpdate) Descriptor() ([]byte, []int) {
 	return file_tui_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *HostInfo) GetMacAddress() string {
+func (x *AvailableUpdate) GetImage() string {
 	if x != nil {
-		return x.MacAddress
+		return x.Image
+	}
+	return ""
+}
+
+func (x *AvailableUpdate) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *AvailableUpdate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AvailableUpdate) GetDetectedAt() string {
+	if x != nil {
+		return x.DetectedAt
+	}
+	return ""
+}
+
+type Quarantine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Image         string                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	Digest        string                 `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	Failures      int32                  `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
+	LastError     string                 `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastFailureAt string                 `protobuf:"bytes,5,opt,name=last_failure_at,json=lastFailureAt,proto3" json:"last_failure_at,omitempty"` // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quarantine) Reset() {
+	*x = Quarantine{}
+	mi := &file_tui_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quarantine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quarantine) ProtoMessage() {}
+
+func (x *Quarantine) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quarantine.ProtoReflect.Descriptor instead.
+func (*Quarantine) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Quarantine) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Quarantine) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *Quarantine) GetFailures() int32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *Quarantine) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Quarantine) GetLastFailureAt() string {
+	if x != nil {
+		return x.LastFailureAt
+	}
+	return ""
+}
+
+type UpdateProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`                          // PULLING, STARTING, RUNNING, HEALTH_CHECK, COMPLETED, ROLLBACK, FAILED
+	Image         string                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`                          // target image of the update
+	Logs          string                 `protobuf:"bytes,3,opt,name=logs,proto3" json:"logs,omitempty"`                            // last log message of the stage
+	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProgress) Reset() {
+	*x = UpdateProgress{}
+	mi := &file_tui_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProgress) ProtoMessage() {}
+
+func (x *UpdateProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProgress.ProtoReflect.Descriptor instead.
+func (*UpdateProgress) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateProgress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *UpdateProgress) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *UpdateProgress) GetLogs() string {
+	if x != nil {
+		return x.Logs
+	}
+	return ""
+}
+
+func (x *UpdateProgress) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type AdmissionDenial struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhook       string                 `protobuf:"bytes,1,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	NewImage      string                 `protobuf:"bytes,3,opt,name=new_image,json=newImage,proto3" json:"new_image,omitempty"`
+	DeniedAt      string                 `protobuf:"bytes,4,opt,name=denied_at,json=deniedAt,proto3" json:"denied_at,omitempty"` // RFC3339
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdmissionDenial) Reset() {
+	*x = AdmissionDenial{}
+	mi := &file_tui_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdmissionDenial) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdmissionDenial) ProtoMessage() {}
+
+func (x *AdmissionDenial) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdmissionDenial.ProtoReflect.Descriptor instead.
+func (*AdmissionDenial) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AdmissionDenial) GetWebhook() string {
+	if x != nil {
+		return x.Webhook
+	}
+	return ""
+}
+
+func (x *AdmissionDenial) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AdmissionDenial) GetNewImage() string {
+	if x != nil {
+		return x.NewImage
+	}
+	return ""
+}
+
+func (x *AdmissionDenial) GetDeniedAt() string {
+	if x != nil {
+		return x.DeniedAt
+	}
+	return ""
+}
+
+type HostInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MacAddress    string                 `protobuf:"bytes,1,opt,name=mac_address,json=macAddress,proto3" json:"mac_address,omitempty"`
+	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	IpAddress     string                 `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	LastHeartbeat string                 `protobuf:"bytes,4,opt,name=lastHeartbeat,proto3" json:"lastHeartbeat,omitempty"`
+	Containers    []*ContainerInfo       `protobuf:"bytes,5,rep,name=containers,proto3" json:"containers,omitempty"`
+	AgentId       string                 `protobuf:"bytes,6,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"` // identifies the host in requests
+	Online        bool                   `protobuf:"varint,7,opt,name=online,proto3" json:"online,omitempty"`                 // the agent is connected; offline hosts keep their containers and history
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostInfo) Reset() {
+	*x = HostInfo{}
+	mi := &file_tui_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfo) ProtoMessage() {}
+
+func (x *HostInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfo.ProtoReflect.Descriptor instead.
+func (*HostInfo) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HostInfo) GetMacAddress() string {
+	if x != nil {
+		return x.MacAddress
+	}
+	return ""
+}
+
+func (x *HostInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *HostInfo) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *HostInfo) GetLastHeartbeat() string {
+	if x != nil {
+		return x.LastHeartbeat
+	}
+	return ""
+}
+
+func (x *HostInfo) GetContainers() []*ContainerInfo {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+func (x *HostInfo) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HostInfo) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+type HostList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hosts         []*HostInfo            `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostList) Reset() {
+	*x = HostList{}
+	mi := &file_tui_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostList) ProtoMessage() {}
+
+func (x *HostList) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostList.ProtoReflect.Descriptor instead.
+func (*HostList) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HostList) GetHosts() []*HostInfo {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type ServicesStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServicesStatus ServicesStatusServices `protobuf:"varint,1,opt,name=services_status,json=servicesStatus,proto3,enum=tui.ServicesStatusServices" json:"services_status,omitempty"`
+	Status         bool                   `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ServicesStatus) Reset() {
+	*x = ServicesStatus{}
+	mi := &file_tui_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServicesStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServicesStatus) ProtoMessage() {}
+
+func (x *ServicesStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServicesStatus.ProtoReflect.Descriptor instead.
+func (*ServicesStatus) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ServicesStatus) GetServicesStatus() ServicesStatusServices {
+	if x != nil {
+		return x.ServicesStatus
+	}
+	return ServicesStatus_ORCHESTRATOR
+}
+
+func (x *ServicesStatus) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+// ===================
+// Request / Response Wrappers
+// ===================
+type DataStreamSend struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	HostList       *HostList              `protobuf:"bytes,1,opt,name=host_list,json=hostList,proto3" json:"host_list,omitempty"`
+	Logs           string                 `protobuf:"bytes,2,opt,name=logs,proto3" json:"logs,omitempty"` // legacy logs field (will be trimmed or empty once StreamLogs used)
+	CronTime       int32                  `protobuf:"varint,3,opt,name=cron_time,json=cronTime,proto3" json:"cron_time,omitempty"`
+	ServicesStatus []*ServicesStatus      `protobuf:"bytes,4,rep,name=services_status,json=servicesStatus,proto3" json:"services_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DataStreamSend) Reset() {
+	*x = DataStreamSend{}
+	mi := &file_tui_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataStreamSend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataStreamSend) ProtoMessage() {}
+
+func (x *DataStreamSend) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataStreamSend.ProtoReflect.Descriptor instead.
+func (*DataStreamSend) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DataStreamSend) GetHostList() *HostList {
+	if x != nil {
+		return x.HostList
+	}
+	return nil
+}
+
+func (x *DataStreamSend) GetLogs() string {
+	if x != nil {
+		return x.Logs
+	}
+	return ""
+}
+
+func (x *DataStreamSend) GetCronTime() int32 {
+	if x != nil {
+		return x.CronTime
+	}
+	return 0
+}
+
+func (x *DataStreamSend) GetServicesStatus() []*ServicesStatus {
+	if x != nil {
+		return x.ServicesStatus
+	}
+	return nil
+}
+
+type DataStreamReceived struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ack           string                 `protobuf:"bytes,1,opt,name=ack,proto3" json:"ack,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DataStreamReceived) Reset() {
+	*x = DataStreamReceived{}
+	mi := &file_tui_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataStreamReceived) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataStreamReceived) ProtoMessage() {}
+
+func (x *DataStreamReceived) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataStreamReceived.ProtoReflect.Descriptor instead.
+func (*DataStreamReceived) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DataStreamReceived) GetAck() string {
+	if x != nil {
+		return x.Ack
+	}
+	return ""
+}
+
+// Streaming log line (simple)
+type LogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          string                 `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"` // already timestamped string
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLine) Reset() {
+	*x = LogLine{}
+	mi := &file_tui_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLine) ProtoMessage() {}
+
+func (x *LogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *LogLine) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+type SetWatchlistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContainerName string                 `protobuf:"bytes,1,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	HostId        string                 `protobuf:"bytes,2,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	Watch         bool                   `protobuf:"varint,3,opt,name=watch,proto3" json:"watch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetWatchlistRequest) Reset() {
+	*x = SetWatchlistRequest{}
+	mi := &file_tui_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetWatchlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetWatchlistRequest) ProtoMessage() {}
+
+func (x *SetWatchlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetWatchlistRequest.ProtoReflect.Descriptor instead.
+func (*SetWatchlistRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetWatchlistRequest) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *SetWatchlistRequest) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *SetWatchlistRequest) GetWatch() bool {
+	if x != nil {
+		return x.Watch
+	}
+	return false
+}
+
+type SetWatchlistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetWatchlistResponse) Reset() {
+	*x = SetWatchlistResponse{}
+	mi := &file_tui_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetWatchlistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetWatchlistResponse) ProtoMessage() {}
+
+func (x *SetWatchlistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetWatchlistResponse.ProtoReflect.Descriptor instead.
+func (*SetWatchlistResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetWatchlistResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetWatchlistResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SetCronTimeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CronTime      int32                  `protobuf:"varint,1,opt,name=cron_time,json=cronTime,proto3" json:"cron_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCronTimeRequest) Reset() {
+	*x = SetCronTimeRequest{}
+	mi := &file_tui_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCronTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCronTimeRequest) ProtoMessage() {}
+
+func (x *SetCronTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCronTimeRequest.ProtoReflect.Descriptor instead.
+func (*SetCronTimeRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SetCronTimeRequest) GetCronTime() int32 {
+	if x != nil {
+		return x.CronTime
+	}
+	return 0
+}
+
+type SetCronTimeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetCronTimeResponse) Reset() {
+	*x = SetCronTimeResponse{}
+	mi := &file_tui_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetCronTimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetCronTimeResponse) ProtoMessage() {}
+
+func (x *SetCronTimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetCronTimeResponse.ProtoReflect.Descriptor instead.
+func (*SetCronTimeResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SetCronTimeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetCronTimeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// CheckNow runs an update check immediately. Leave host_id and container_uid
+// empty to check the whole fleet.
+type CheckNowRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`                   // restrict the check to one host
+	ContainerUid  string                 `protobuf:"bytes,2,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"` // restrict the check to one container
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                  // return the plan without dispatching or storing anything
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckNowRequest) Reset() {
+	*x = CheckNowRequest{}
+	mi := &file_tui_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckNowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckNowRequest) ProtoMessage() {}
+
+func (x *CheckNowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckNowRequest.ProtoReflect.Descriptor instead.
+func (*CheckNowRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CheckNowRequest) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *CheckNowRequest) GetContainerUid() string {
+	if x != nil {
+		return x.ContainerUid
+	}
+	return ""
+}
+
+func (x *CheckNowRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type PlannedUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	Hostname      string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	ContainerUid  string                 `protobuf:"bytes,3,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
+	ContainerName string                 `protobuf:"bytes,4,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
+	CurrentImage  string                 `protobuf:"bytes,5,opt,name=current_image,json=currentImage,proto3" json:"current_image,omitempty"`
+	NewImage      string                 `protobuf:"bytes,6,opt,name=new_image,json=newImage,proto3" json:"new_image,omitempty"`
+	Description   string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	Dispatched    bool                   `protobuf:"varint,8,opt,name=dispatched,proto3" json:"dispatched,omitempty"`                  // the update command was sent to the agent
+	SkipReason    string                 `protobuf:"bytes,9,opt,name=skip_reason,json=skipReason,proto3" json:"skip_reason,omitempty"` // why the update was (or would be) skipped
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlannedUpdate) Reset() {
+	*x = PlannedUpdate{}
+	mi := &file_tui_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlannedUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlannedUpdate) ProtoMessage() {}
+
+func (x *PlannedUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlannedUpdate.ProtoReflect.Descriptor instead.
+func (*PlannedUpdate) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PlannedUpdate) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetContainerUid() string {
+	if x != nil {
+		return x.ContainerUid
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetContainerName() string {
+	if x != nil {
+		return x.ContainerName
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetCurrentImage() string {
+	if x != nil {
+		return x.CurrentImage
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetNewImage() string {
+	if x != nil {
+		return x.NewImage
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PlannedUpdate) GetDispatched() bool {
+	if x != nil {
+		return x.Dispatched
+	}
+	return false
+}
+
+func (x *PlannedUpdate) GetSkipReason() string {
+	if x != nil {
+		return x.SkipReason
+	}
+	return ""
+}
+
+type CheckNowResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Plan          []*PlannedUpdate       `protobuf:"bytes,3,rep,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckNowResponse) Reset() {
+	*x = CheckNowResponse{}
+	mi := &file_tui_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckNowResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckNowResponse) ProtoMessage() {}
+
+func (x *CheckNowResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckNowResponse.ProtoReflect.Descriptor instead.
+func (*CheckNowResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CheckNowResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CheckNowResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CheckNowResponse) GetPlan() []*PlannedUpdate {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+// UpdateContainer forces an update of one container. Leave tag and digest empty
+// to re-pull the image the container is currently running.
+type UpdateContainerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	ContainerUid  string                 `protobuf:"bytes,2,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
+	Tag           string                 `protobuf:"bytes,3,opt,name=tag,proto3" json:"tag,omitempty"`       // optional target tag, e.g. "1.27"
+	Digest        string                 `protobuf:"bytes,4,opt,name=digest,proto3" json:"digest,omitempty"` // optional target digest, e.g. "sha256:..."; wins over tag
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateContainerRequest) Reset() {
+	*x = UpdateContainerRequest{}
+	mi := &file_tui_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateContainerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateContainerRequest) ProtoMessage() {}
+
+func (x *UpdateContainerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateContainerRequest.ProtoReflect.Descriptor instead.
+func (*UpdateContainerRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateContainerRequest) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *UpdateContainerRequest) GetContainerUid() string {
+	if x != nil {
+		return x.ContainerUid
+	}
+	return ""
+}
+
+func (x *UpdateContainerRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *UpdateContainerRequest) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+type UpdateContainerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"` // image reference sent to the agent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateContainerResponse) Reset() {
+	*x = UpdateContainerResponse{}
+	mi := &file_tui_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateContainerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateContainerResponse) ProtoMessage() {}
+
+func (x *UpdateContainerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateContainerResponse.ProtoReflect.Descriptor instead.
+func (*UpdateContainerResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateContainerResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateContainerResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UpdateContainerResponse) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+// RollbackContainer restores the image a container ran before its last successful update.
+type RollbackContainerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	ContainerUid  string                 `protobuf:"bytes,2,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
+	Pin           bool                   `protobuf:"varint,3,opt,name=pin,proto3" json:"pin,omitempty"` // suspend automatic updates so the bad version is not re-applied
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RollbackContainerRequest) Reset() {
+	*x = RollbackContainerRequest{}
+	mi := &file_tui_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RollbackContainerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RollbackContainerRequest) ProtoMessage() {}
+
+func (x *RollbackContainerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *HostInfo) GetHostname() string {
-	if x != nil {
-		return x.Hostname
-	}
-	return ""
+// Deprecated: Use RollbackContainerRequest.ProtoReflect.Descriptor instead.
+func (*RollbackContainerRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *HostInfo) GetIpAddress() string {
+func (x *RollbackContainerRequest) GetHostId() string {
 	if x != nil {
-		return x.IpAddress
+		return x.HostId
 	}
 	return ""
 }
 
-func (x *HostInfo) GetLastHeartbeat() string {
+func (x *RollbackContainerRequest) GetContainerUid() string {
 	if x != nil {
-		return x.LastHeartbeat
+		return x.ContainerUid
 	}
 	return ""
 }
 
-func (x *HostInfo) GetContainers() []*ContainerInfo {
+func (x *RollbackContainerRequest) GetPin() bool {
 	if x != nil {
-		return x.Containers
+		return x.Pin
 	}
-	return nil
+	return false
 }
 
-type HostList struct {
+type RollbackContainerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Hosts         []*HostInfo            `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Image         string                 `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"` // image the container is rolled back to
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostList) Reset() {
-	*x = HostList{}
-	mi := &file_tui_proto_msgTypes[2]
+func (x *RollbackContainerResponse) Reset() {
+	*x = RollbackContainerResponse{}
+	mi := &file_tui_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostList) String() string {
+func (x *RollbackContainerResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostList) ProtoMessage() {}
+func (*RollbackContainerResponse) ProtoMessage() {}
 
-func (x *HostList) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[2]
+func (x *RollbackContainerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -307,41 +1586,56 @@ func (x *HostList) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostList.ProtoReflect.Descriptor instead.
-func (*HostList) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use RollbackContainerResponse.ProtoReflect.Descriptor instead.
+func (*RollbackContainerResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *HostList) GetHosts() []*HostInfo {
+func (x *RollbackContainerResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Hosts
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type ServicesStatus struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ServicesStatus ServicesStatusServices `protobuf:"varint,1,opt,name=services_status,json=servicesStatus,proto3,enum=tui.ServicesStatusServices" json:"services_status,omitempty"`
-	Status         bool                   `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *RollbackContainerResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
 
-func (x *ServicesStatus) Reset() {
-	*x = ServicesStatus{}
-	mi := &file_tui_proto_msgTypes[3]
+func (x *RollbackContainerResponse) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+type SetPinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	ContainerUid  string                 `protobuf:"bytes,2,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
+	Pinned        bool                   `protobuf:"varint,3,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPinRequest) Reset() {
+	*x = SetPinRequest{}
+	mi := &file_tui_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ServicesStatus) String() string {
+func (x *SetPinRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServicesStatus) ProtoMessage() {}
+func (*SetPinRequest) ProtoMessage() {}
 
-func (x *ServicesStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[3]
+func (x *SetPinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -352,53 +1646,55 @@ func (x *ServicesStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServicesStatus.ProtoReflect.Descriptor instead.
-func (*ServicesStatus) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use SetPinRequest.ProtoReflect.Descriptor instead.
+func (*SetPinRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ServicesStatus) GetServicesStatus() ServicesStatusServices {
+func (x *SetPinRequest) GetHostId() string {
 	if x != nil {
-		return x.ServicesStatus
+		return x.HostId
 	}
-	return ServicesStatus_ORCHESTRATOR
+	return ""
 }
 
-func (x *ServicesStatus) GetStatus() bool {
+func (x *SetPinRequest) GetContainerUid() string {
 	if x != nil {
-		return x.Status
+		return x.ContainerUid
+	}
+	return ""
+}
+
+func (x *SetPinRequest) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
 	}
 	return false
 }
 
-// ===================
-// Request / Response Wrappers
-// ===================
-type DataStreamSend struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	HostList       *HostList              `protobuf:"bytes,1,opt,name=host_list,json=hostList,proto3" json:"host_list,omitempty"`
-	Logs           string                 `protobuf:"bytes,2,opt,name=logs,proto3" json:"logs,omitempty"` // legacy logs field (will be trimmed or empty once StreamLogs used)
-	CronTime       int32                  `protobuf:"varint,3,opt,name=cron_time,json=cronTime,proto3" json:"cron_time,omitempty"`
-	ServicesStatus []*ServicesStatus      `protobuf:"bytes,4,rep,name=services_status,json=servicesStatus,proto3" json:"services_status,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type SetPinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DataStreamSend) Reset() {
-	*x = DataStreamSend{}
-	mi := &file_tui_proto_msgTypes[4]
+func (x *SetPinResponse) Reset() {
+	*x = SetPinResponse{}
+	mi := &file_tui_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DataStreamSend) String() string {
+func (x *SetPinResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DataStreamSend) ProtoMessage() {}
+func (*SetPinResponse) ProtoMessage() {}
 
-func (x *DataStreamSend) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[4]
+func (x *SetPinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -409,61 +1705,50 @@ func (x *DataStreamSend) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DataStreamSend.ProtoReflect.Descriptor instead.
-func (*DataStreamSend) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use SetPinResponse.ProtoReflect.Descriptor instead.
+func (*SetPinResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *DataStreamSend) GetHostList() *HostList {
+func (x *SetPinResponse) GetSuccess() bool {
 	if x != nil {
-		return x.HostList
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-func (x *DataStreamSend) GetLogs() string {
+func (x *SetPinResponse) GetMessage() string {
 	if x != nil {
-		return x.Logs
+		return x.Message
 	}
 	return ""
 }
 
-func (x *DataStreamSend) GetCronTime() int32 {
-	if x != nil {
-		return x.CronTime
-	}
-	return 0
-}
-
-func (x *DataStreamSend) GetServicesStatus() []*ServicesStatus {
-	if x != nil {
-		return x.ServicesStatus
-	}
-	return nil
-}
-
-type DataStreamReceived struct {
+// ClearQuarantine forgets the failed update attempts of a container so its
+// quarantined candidate images are tried again.
+type ClearQuarantineRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Ack           string                 `protobuf:"bytes,1,opt,name=ack,proto3" json:"ack,omitempty"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	ContainerUid  string                 `protobuf:"bytes,2,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DataStreamReceived) Reset() {
-	*x = DataStreamReceived{}
-	mi := &file_tui_proto_msgTypes[5]
+func (x *ClearQuarantineRequest) Reset() {
+	*x = ClearQuarantineRequest{}
+	mi := &file_tui_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DataStreamReceived) String() string {
+func (x *ClearQuarantineRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DataStreamReceived) ProtoMessage() {}
+func (*ClearQuarantineRequest) ProtoMessage() {}
 
-func (x *DataStreamReceived) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[5]
+func (x *ClearQuarantineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -474,41 +1759,48 @@ func (x *DataStreamReceived) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DataStreamReceived.ProtoReflect.Descriptor instead.
-func (*DataStreamReceived) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ClearQuarantineRequest.ProtoReflect.Descriptor instead.
+func (*ClearQuarantineRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *DataStreamReceived) GetAck() string {
+func (x *ClearQuarantineRequest) GetHostId() string {
 	if x != nil {
-		return x.Ack
+		return x.HostId
 	}
 	return ""
 }
 
-// Streaming log line (simple)
-type LogLine struct {
+func (x *ClearQuarantineRequest) GetContainerUid() string {
+	if x != nil {
+		return x.ContainerUid
+	}
+	return ""
+}
+
+type ClearQuarantineResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Line          string                 `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"` // already timestamped string
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogLine) Reset() {
-	*x = LogLine{}
-	mi := &file_tui_proto_msgTypes[6]
+func (x *ClearQuarantineResponse) Reset() {
+	*x = ClearQuarantineResponse{}
+	mi := &file_tui_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogLine) String() string {
+func (x *ClearQuarantineResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogLine) ProtoMessage() {}
+func (*ClearQuarantineResponse) ProtoMessage() {}
 
-func (x *LogLine) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[6]
+func (x *ClearQuarantineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -519,42 +1811,49 @@ func (x *LogLine) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
-func (*LogLine) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ClearQuarantineResponse.ProtoReflect.Descriptor instead.
+func (*ClearQuarantineResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *LogLine) GetLine() string {
+func (x *ClearQuarantineResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Line
+		return x.Success
+	}
+	return false
+}
+
+func (x *ClearQuarantineResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-type SetWatchlistRequest struct {
+// CancelUpdate aborts the update of a container that is queued or in progress on its agent.
+type CancelUpdateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ContainerName string                 `protobuf:"bytes,1,opt,name=container_name,json=containerName,proto3" json:"container_name,omitempty"`
-	HostMac       string                 `protobuf:"bytes,2,opt,name=host_mac,json=hostMac,proto3" json:"host_mac,omitempty"`
-	Watch         bool                   `protobuf:"varint,3,opt,name=watch,proto3" json:"watch,omitempty"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	ContainerUid  string                 `protobuf:"bytes,2,opt,name=container_uid,json=containerUid,proto3" json:"container_uid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetWatchlistRequest) Reset() {
-	*x = SetWatchlistRequest{}
-	mi := &file_tui_proto_msgTypes[7]
+func (x *CancelUpdateRequest) Reset() {
+	*x = CancelUpdateRequest{}
+	mi := &file_tui_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetWatchlistRequest) String() string {
+func (x *CancelUpdateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetWatchlistRequest) ProtoMessage() {}
+func (*CancelUpdateRequest) ProtoMessage() {}
 
-func (x *SetWatchlistRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[7]
+func (x *CancelUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -565,33 +1864,26 @@ func (x *SetWatchlistRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetWatchlistRequest.ProtoReflect.Descriptor instead.
-func (*SetWatchlistRequest) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CancelUpdateRequest.ProtoReflect.Descriptor instead.
+func (*CancelUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *SetWatchlistRequest) GetContainerName() string {
+func (x *CancelUpdateRequest) GetHostId() string {
 	if x != nil {
-		return x.ContainerName
+		return x.HostId
 	}
 	return ""
 }
 
-func (x *SetWatchlistRequest) GetHostMac() string {
+func (x *CancelUpdateRequest) GetContainerUid() string {
 	if x != nil {
-		return x.HostMac
+		return x.ContainerUid
 	}
 	return ""
 }
 
-func (x *SetWatchlistRequest) GetWatch() bool {
-	if x != nil {
-		return x.Watch
-	}
-	return false
-}
-
-type SetWatchlistResponse struct {
+type CancelUpdateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
@@ -599,21 +1891,21 @@ type SetWatchlistResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetWatchlistResponse) Reset() {
-	*x = SetWatchlistResponse{}
-	mi := &file_tui_proto_msgTypes[8]
+func (x *CancelUpdateResponse) Reset() {
+	*x = CancelUpdateResponse{}
+	mi := &file_tui_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetWatchlistResponse) String() string {
+func (x *CancelUpdateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetWatchlistResponse) ProtoMessage() {}
+func (*CancelUpdateResponse) ProtoMessage() {}
 
-func (x *SetWatchlistResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[8]
+func (x *CancelUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,47 +1916,50 @@ func (x *SetWatchlistResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetWatchlistResponse.ProtoReflect.Descriptor instead.
-func (*SetWatchlistResponse) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use CancelUpdateResponse.ProtoReflect.Descriptor instead.
+func (*CancelUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *SetWatchlistResponse) GetSuccess() bool {
+func (x *CancelUpdateResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *SetWatchlistResponse) GetMessage() string {
+func (x *CancelUpdateResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-type SetCronTimeRequest struct {
+// UpdateProject updates the services of a compose project that have updates available,
+// in dependency order, rolling the whole project back if one fails.
+type UpdateProjectRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CronTime      int32                  `protobuf:"varint,1,opt,name=cron_time,json=cronTime,proto3" json:"cron_time,omitempty"`
+	HostId        string                 `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	Project       string                 `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetCronTimeRequest) Reset() {
-	*x = SetCronTimeRequest{}
-	mi := &file_tui_proto_msgTypes[9]
+func (x *UpdateProjectRequest) Reset() {
+	*x = UpdateProjectRequest{}
+	mi := &file_tui_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetCronTimeRequest) String() string {
+func (x *UpdateProjectRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetCronTimeRequest) ProtoMessage() {}
+func (*UpdateProjectRequest) ProtoMessage() {}
 
-func (x *SetCronTimeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[9]
+func (x *UpdateProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -675,41 +1970,49 @@ func (x *SetCronTimeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetCronTimeRequest.ProtoReflect.Descriptor instead.
-func (*SetCronTimeRequest) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use UpdateProjectRequest.ProtoReflect.Descriptor instead.
+func (*UpdateProjectRequest) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *SetCronTimeRequest) GetCronTime() int32 {
+func (x *UpdateProjectRequest) GetHostId() string {
 	if x != nil {
-		return x.CronTime
+		return x.HostId
 	}
-	return 0
+	return ""
 }
 
-type SetCronTimeResponse struct {
+func (x *UpdateProjectRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+type UpdateProjectResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Services      []string               `protobuf:"bytes,3,rep,name=services,proto3" json:"services,omitempty"` // services being updated, in order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetCronTimeResponse) Reset() {
-	*x = SetCronTimeResponse{}
-	mi := &file_tui_proto_msgTypes[10]
+func (x *UpdateProjectResponse) Reset() {
+	*x = UpdateProjectResponse{}
+	mi := &file_tui_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetCronTimeResponse) String() string {
+func (x *UpdateProjectResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetCronTimeResponse) ProtoMessage() {}
+func (*UpdateProjectResponse) ProtoMessage() {}
 
-func (x *SetCronTimeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_tui_proto_msgTypes[10]
+func (x *UpdateProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tui_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -720,35 +2023,58 @@ func (x *SetCronTimeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetCronTimeResponse.ProtoReflect.Descriptor instead.
-func (*SetCronTimeResponse) Descriptor() ([]byte, []int) {
-	return file_tui_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use UpdateProjectResponse.ProtoReflect.Descriptor instead.
+func (*UpdateProjectResponse) Descriptor() ([]byte, []int) {
+	return file_tui_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *SetCronTimeResponse) GetSuccess() bool {
+func (x *UpdateProjectResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *SetCronTimeResponse) GetMessage() string {
+func (x *UpdateProjectResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
+func (x *UpdateProjectResponse) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
 var File_tui_proto protoreflect.FileDescriptor
 
 const file_tui_proto_rawDesc = "" +
 	"\n" +
-	"\ttui.proto\x12\x03tui\"\xe5\x01\n" +
+	"\ttui.proto\x12\x03tui\"\xa4\x05\n" +
 	"\rContainerInfo\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05image\x18\x02 \x01(\tR\x05image\x121\n" +
 	"\x06status\x18\x03 \x01(\x0e2\x19.tui.ContainerInfo.StatusR\x06status\x12\x14\n" +
-	"\x05watch\x18\x04 \x01(\bR\x05watch\"a\n" +
+	"\x05watch\x18\x04 \x01(\bR\x05watch\x125\n" +
+	"\vlast_denial\x18\x05 \x01(\v2\x14.tui.AdmissionDenialR\n" +
+	"lastDenial\x12#\n" +
+	"\rcontainer_uid\x18\x06 \x01(\tR\fcontainerUid\x12+\n" +
+	"\x06update\x18\a \x01(\v2\x13.tui.UpdateProgressR\x06update\x12%\n" +
+	"\x0eprevious_image\x18\b \x01(\tR\rpreviousImage\x12\x16\n" +
+	"\x06pinned\x18\t \x01(\bR\x06pinned\x12/\n" +
+	"\n" +
+	"quarantine\x18\n" +
+	" \x01(\v2\x0f.tui.QuarantineR\n" +
+	"quarantine\x12\x16\n" +
+	"\x06policy\x18\v \x01(\tR\x06policy\x12!\n" +
+	"\fwatch_source\x18\f \x01(\tR\vwatchSource\x12\x14\n" +
+	"\x05drift\x18\r \x01(\tR\x05drift\x122\n" +
+	"\tavailable\x18\x0e \x01(\v2\x14.tui.AvailableUpdateR\tavailable\x12'\n" +
+	"\x0fcompose_project\x18\x0f \x01(\tR\x0ecomposeProject\x12\x16\n" +
+	"\x06health\x18\x10 \x01(\tR\x06health\"a\n" +
 	"\x06Status\x12\v\n" +
 	"\aUNKNOWN\x10\x00\x12\v\n" +
 	"\aRUNNING\x10\x01\x12\v\n" +
@@ -759,7 +2085,32 @@ const file_tui_proto_rawDesc = "" +
 	"RESTARTING\x10\x04\x12\n" +
 	"\n" +
 	"\x06EXITED\x10\x05\x12\b\n" +
-	"\x04DEAD\x10\x06\"\xc0\x01\n" +
+	"\x04DEAD\x10\x06\"\x82\x01\n" +
+	"\x0fAvailableUpdate\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12\x16\n" +
+	"\x06digest\x18\x02 \x01(\tR\x06digest\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vdetected_at\x18\x04 \x01(\tR\n" +
+	"detectedAt\"\x9d\x01\n" +
+	"\n" +
+	"Quarantine\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\tR\x05image\x12\x16\n" +
+	"\x06digest\x18\x02 \x01(\tR\x06digest\x12\x1a\n" +
+	"\bfailures\x18\x03 \x01(\x05R\bfailures\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12&\n" +
+	"\x0flast_failure_at\x18\x05 \x01(\tR\rlastFailureAt\"o\n" +
+	"\x0eUpdateProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x14\n" +
+	"\x05image\x18\x02 \x01(\tR\x05image\x12\x12\n" +
+	"\x04logs\x18\x03 \x01(\tR\x04logs\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"}\n" +
+	"\x0fAdmissionDenial\x12\x18\n" +
+	"\awebhook\x18\x01 \x01(\tR\awebhook\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tnew_image\x18\x03 \x01(\tR\bnewImage\x12\x1b\n" +
+	"\tdenied_at\x18\x04 \x01(\tR\bdeniedAt\"\xf3\x01\n" +
 	"\bHostInfo\x12\x1f\n" +
 	"\vmac_address\x18\x01 \x01(\tR\n" +
 	"macAddress\x12\x1a\n" +
@@ -769,7 +2120,9 @@ const file_tui_proto_rawDesc = "" +
 	"\rlastHeartbeat\x18\x04 \x01(\tR\rlastHeartbeat\x122\n" +
 	"\n" +
 	"containers\x18\x05 \x03(\v2\x12.tui.ContainerInfoR\n" +
-	"containers\"/\n" +
+	"containers\x12\x19\n" +
+	"\bagent_id\x18\x06 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06online\x18\a \x01(\bR\x06online\"/\n" +
 	"\bHostList\x12#\n" +
 	"\x05hosts\x18\x01 \x03(\v2\r.tui.HostInfoR\x05hosts\"\xb1\x01\n" +
 	"\x0eservicesStatus\x12E\n" +
@@ -787,10 +2140,10 @@ const file_tui_proto_rawDesc = "" +
 	"\x12DataStreamReceived\x12\x10\n" +
 	"\x03ack\x18\x01 \x01(\tR\x03ack\"\x1d\n" +
 	"\aLogLine\x12\x12\n" +
-	"\x04line\x18\x01 \x01(\tR\x04line\"m\n" +
+	"\x04line\x18\x01 \x01(\tR\x04line\"k\n" +
 	"\x13SetWatchlistRequest\x12%\n" +
-	"\x0econtainer_name\x18\x01 \x01(\tR\rcontainerName\x12\x19\n" +
-	"\bhost_mac\x18\x02 \x01(\tR\ahostMac\x12\x14\n" +
+	"\x0econtainer_name\x18\x01 \x01(\tR\rcontainerName\x12\x17\n" +
+	"\ahost_id\x18\x02 \x01(\tR\x06hostId\x12\x14\n" +
 	"\x05watch\x18\x03 \x01(\bR\x05watch\"J\n" +
 	"\x14SetWatchlistResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
@@ -799,14 +2152,85 @@ const file_tui_proto_rawDesc = "" +
 	"\tcron_time\x18\x01 \x01(\x05R\bcronTime\"I\n" +
 	"\x13SetCronTimeResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\x8c\x02\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"h\n" +
+	"\x0fCheckNowRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12#\n" +
+	"\rcontainer_uid\x18\x02 \x01(\tR\fcontainerUid\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"\xb5\x02\n" +
+	"\rPlannedUpdate\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12#\n" +
+	"\rcontainer_uid\x18\x03 \x01(\tR\fcontainerUid\x12%\n" +
+	"\x0econtainer_name\x18\x04 \x01(\tR\rcontainerName\x12#\n" +
+	"\rcurrent_image\x18\x05 \x01(\tR\fcurrentImage\x12\x1b\n" +
+	"\tnew_image\x18\x06 \x01(\tR\bnewImage\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"dispatched\x18\b \x01(\bR\n" +
+	"dispatched\x12\x1f\n" +
+	"\vskip_reason\x18\t \x01(\tR\n" +
+	"skipReason\"n\n" +
+	"\x10CheckNowResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x04plan\x18\x03 \x03(\v2\x12.tui.PlannedUpdateR\x04plan\"\x80\x01\n" +
+	"\x16UpdateContainerRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12#\n" +
+	"\rcontainer_uid\x18\x02 \x01(\tR\fcontainerUid\x12\x10\n" +
+	"\x03tag\x18\x03 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06digest\x18\x04 \x01(\tR\x06digest\"c\n" +
+	"\x17UpdateContainerResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\"j\n" +
+	"\x18RollbackContainerRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12#\n" +
+	"\rcontainer_uid\x18\x02 \x01(\tR\fcontainerUid\x12\x10\n" +
+	"\x03pin\x18\x03 \x01(\bR\x03pin\"e\n" +
+	"\x19RollbackContainerResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05image\x18\x03 \x01(\tR\x05image\"e\n" +
+	"\rSetPinRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12#\n" +
+	"\rcontainer_uid\x18\x02 \x01(\tR\fcontainerUid\x12\x16\n" +
+	"\x06pinned\x18\x03 \x01(\bR\x06pinned\"D\n" +
+	"\x0eSetPinResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"V\n" +
+	"\x16ClearQuarantineRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12#\n" +
+	"\rcontainer_uid\x18\x02 \x01(\tR\fcontainerUid\"M\n" +
+	"\x17ClearQuarantineResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"S\n" +
+	"\x13CancelUpdateRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12#\n" +
+	"\rcontainer_uid\x18\x02 \x01(\tR\fcontainerUid\"J\n" +
+	"\x14CancelUpdateResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"I\n" +
+	"\x14UpdateProjectRequest\x12\x17\n" +
+	"\ahost_id\x18\x01 \x01(\tR\x06hostId\x12\x18\n" +
+	"\aproject\x18\x02 \x01(\tR\aproject\"g\n" +
+	"\x15UpdateProjectResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1a\n" +
+	"\bservices\x18\x03 \x03(\tR\bservices2\xf5\x05\n" +
 	"\n" +
 	"TUIService\x12B\n" +
 	"\x0eSendDatastream\x12\x17.tui.DataStreamReceived\x1a\x13.tui.DataStreamSend(\x010\x01\x127\n" +
 	"\n" +
 	"StreamLogs\x12\x17.tui.DataStreamReceived\x1a\f.tui.LogLine(\x010\x01\x12?\n" +
 	"\bSetWatch\x12\x18.tui.SetWatchlistRequest\x1a\x19.tui.SetWatchlistResponse\x12@\n" +
-	"\vSetCronTime\x12\x17.tui.SetCronTimeRequest\x1a\x18.tui.SetCronTimeResponseBIZGgithub.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/tuib\x06proto3"
+	"\vSetCronTime\x12\x17.tui.SetCronTimeRequest\x1a\x18.tui.SetCronTimeResponse\x127\n" +
+	"\bCheckNow\x12\x14.tui.CheckNowRequest\x1a\x15.tui.CheckNowResponse\x12L\n" +
+	"\x0fUpdateContainer\x12\x1b.tui.UpdateContainerRequest\x1a\x1c.tui.UpdateContainerResponse\x12R\n" +
+	"\x11RollbackContainer\x12\x1d.tui.RollbackContainerRequest\x1a\x1e.tui.RollbackContainerResponse\x121\n" +
+	"\x06SetPin\x12\x12.tui.SetPinRequest\x1a\x13.tui.SetPinResponse\x12L\n" +
+	"\x0fClearQuarantine\x12\x1b.tui.ClearQuarantineRequest\x1a\x1c.tui.ClearQuarantineResponse\x12C\n" +
+	"\fCancelUpdate\x12\x18.tui.CancelUpdateRequest\x1a\x19.tui.CancelUpdateResponse\x12F\n" +
+	"\rUpdateProject\x12\x19.tui.UpdateProjectRequest\x1a\x1a.tui.UpdateProjectResponseBIZGgithub.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/tuib\x06proto3"
 
 var (
 	file_tui_proto_rawDescOnce sync.Once
@@ -821,42 +2245,80 @@ func file_tui_proto_rawDescGZIP() []byte {
 }
 
 var file_tui_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_tui_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_tui_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_tui_proto_goTypes = []any{
-	(ContainerInfo_Status)(0),    // 0: tui.ContainerInfo.Status
-	(ServicesStatusServices)(0),  // 1: tui.servicesStatus.services
-	(*ContainerInfo)(nil),        // 2: tui.ContainerInfo
-	(*HostInfo)(nil),             // 3: tui.HostInfo
-	(*HostList)(nil),             // 4: tui.HostList
-	(*ServicesStatus)(nil),       // 5: tui.servicesStatus
-	(*DataStreamSend)(nil),       // 6: tui.DataStreamSend
-	(*DataStreamReceived)(nil),   // 7: tui.DataStreamReceived
-	(*LogLine)(nil),              // 8: tui.LogLine
-	(*SetWatchlistRequest)(nil),  // 9: tui.SetWatchlistRequest
-	(*SetWatchlistResponse)(nil), // 10: tui.SetWatchlistResponse
-	(*SetCronTimeRequest)(nil),   // 11: tui.SetCronTimeRequest
-	(*SetCronTimeResponse)(nil),  // 12: tui.SetCronTimeResponse
+	(ContainerInfo_Status)(0),         // 0: tui.ContainerInfo.Status
+	(ServicesStatusServices)(0),       // 1: tui.servicesStatus.services
+	(*ContainerInfo)(nil),             // 2: tui.ContainerInfo
+	(*AvailableUpdate)(nil),           // 3: tui.AvailableUpdate
+	(*Quarantine)(nil),                // 4: tui.Quarantine
+	(*UpdateProgress)(nil),            // 5: tui.UpdateProgress
+	(*AdmissionDenial)(nil),           // 6: tui.AdmissionDenial
+	(*HostInfo)(nil),                  // 7: tui.HostInfo
+	(*HostList)(nil),                  // 8: tui.HostList
+	(*ServicesStatus)(nil),            // 9: tui.servicesStatus
+	(*DataStreamSend)(nil),            // 10: tui.DataStreamSend
+	(*DataStreamReceived)(nil),        // 11: tui.DataStreamReceived
+	(*LogLine)(nil),                   // 12: tui.LogLine
+	(*SetWatchlistRequest)(nil),       // 13: tui.SetWatchlistRequest
+	(*SetWatchlistResponse)(nil),      // 14: tui.SetWatchlistResponse
+	(*SetCronTimeRequest)(nil),        // 15: tui.SetCronTimeRequest
+	(*SetCronTimeResponse)(nil),       // 16: tui.SetCronTimeResponse
+	(*CheckNowRequest)(nil),           // 17: tui.CheckNowRequest
+	(*PlannedUpdate)(nil),             // 18: tui.PlannedUpdate
+	(*CheckNowResponse)(nil),          // 19: tui.CheckNowResponse
+	(*UpdateContainerRequest)(nil),    // 20: tui.UpdateContainerRequest
+	(*UpdateContainerResponse)(nil),   // 21: tui.UpdateContainerResponse
+	(*RollbackContainerRequest)(nil),  // 22: tui.RollbackContainerRequest
+	(*RollbackContainerResponse)(nil), // 23: tui.RollbackContainerResponse
+	(*SetPinRequest)(nil),             // 24: tui.SetPinRequest
+	(*SetPinResponse)(nil),            // 25: tui.SetPinResponse
+	(*ClearQuarantineRequest)(nil),    // 26: tui.ClearQuarantineRequest
+	(*ClearQuarantineResponse)(nil),   // 27: tui.ClearQuarantineResponse
+	(*CancelUpdateRequest)(nil),       // 28: tui.CancelUpdateRequest
+	(*CancelUpdateResponse)(nil),      // 29: tui.CancelUpdateResponse
+	(*UpdateProjectRequest)(nil),      // 30: tui.UpdateProjectRequest
+	(*UpdateProjectResponse)(nil),     // 31: tui.UpdateProjectResponse
 }
 var file_tui_proto_depIdxs = []int32{
 	0,  // 0: tui.ContainerInfo.status:type_name -> tui.ContainerInfo.Status
-	2,  // 1: tui.HostInfo.containers:type_name -> tui.ContainerInfo
-	3,  // 2: tui.HostList.hosts:type_name -> tui.HostInfo
-	1,  // 3: tui.servicesStatus.services_status:type_name -> tui.servicesStatus.services
-	4,  // 4: tui.DataStreamSend.host_list:type_name -> tui.HostList
-	5,  // 5: tui.DataStreamSend.services_status:type_name -> tui.servicesStatus
-	7,  // 6: tui.TUIService.SendDatastream:input_type -> tui.DataStreamReceived
-	7,  // 7: tui.TUIService.StreamLogs:input_type -> tui.DataStreamReceived
-	9,  // 8: tui.TUIService.SetWatch:input_type -> tui.SetWatchlistRequest
-	11, // 9: tui.TUIService.SetCronTime:input_type -> tui.SetCronTimeRequest
-	6,  // 10: tui.TUIService.SendDatastream:output_type -> tui.DataStreamSend
-	8,  // 11: tui.TUIService.StreamLogs:output_type -> tui.LogLine
-	10, // 12: tui.TUIService.SetWatch:output_type -> tui.SetWatchlistResponse
-	12, // 13: tui.TUIService.SetCronTime:output_type -> tui.SetCronTimeResponse
-	10, // [10:14] is the sub-list for method output_type
-	6,  // [6:10] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	6,  // 1: tui.ContainerInfo.last_denial:type_name -> tui.AdmissionDenial
+	5,  // 2: tui.ContainerInfo.update:type_name -> tui.UpdateProgress
+	4,  // 3: tui.ContainerInfo.quarantine:type_name -> tui.Quarantine
+	3,  // 4: tui.ContainerInfo.available:type_name -> tui.AvailableUpdate
+	2,  // 5: tui.HostInfo.containers:type_name -> tui.ContainerInfo
+	7,  // 6: tui.HostList.hosts:type_name -> tui.HostInfo
+	1,  // 7: tui.servicesStatus.services_status:type_name -> tui.servicesStatus.services
+	8,  // 8: tui.DataStreamSend.host_list:type_name -> tui.HostList
+	9,  // 9: tui.DataStreamSend.services_status:type_name -> tui.servicesStatus
+	18, // 10: tui.CheckNowResponse.plan:type_name -> tui.PlannedUpdate
+	11, // 11: tui.TUIService.SendDatastream:input_type -> tui.DataStreamReceived
+	11, // 12: tui.TUIService.StreamLogs:input_type -> tui.DataStreamReceived
+	13, // 13: tui.TUIService.SetWatch:input_type -> tui.SetWatchlistRequest
+	15, // 14: tui.TUIService.SetCronTime:input_type -> tui.SetCronTimeRequest
+	17, // 15: tui.TUIService.CheckNow:input_type -> tui.CheckNowRequest
+	20, // 16: tui.TUIService.UpdateContainer:input_type -> tui.UpdateContainerRequest
+	22, // 17: tui.TUIService.RollbackContainer:input_type -> tui.RollbackContainerRequest
+	24, // 18: tui.TUIService.SetPin:input_type -> tui.SetPinRequest
+	26, // 19: tui.TUIService.ClearQuarantine:input_type -> tui.ClearQuarantineRequest
+	28, // 20: tui.TUIService.CancelUpdate:input_type -> tui.CancelUpdateRequest
+	30, // 21: tui.TUIService.UpdateProject:input_type -> tui.UpdateProjectRequest
+	10, // 22: tui.TUIService.SendDatastream:output_type -> tui.DataStreamSend
+	12, // 23: tui.TUIService.StreamLogs:output_type -> tui.LogLine
+	14, // 24: tui.TUIService.SetWatch:output_type -> tui.SetWatchlistResponse
+	16, // 25: tui.TUIService.SetCronTime:output_type -> tui.SetCronTimeResponse
+	19, // 26: tui.TUIService.CheckNow:output_type -> tui.CheckNowResponse
+	21, // 27: tui.TUIService.UpdateContainer:output_type -> tui.UpdateContainerResponse
+	23, // 28: tui.TUIService.RollbackContainer:output_type -> tui.RollbackContainerResponse
+	25, // 29: tui.TUIService.SetPin:output_type -> tui.SetPinResponse
+	27, // 30: tui.TUIService.ClearQuarantine:output_type -> tui.ClearQuarantineResponse
+	29, // 31: tui.TUIService.CancelUpdate:output_type -> tui.CancelUpdateResponse
+	31, // 32: tui.TUIService.UpdateProject:output_type -> tui.UpdateProjectResponse
+	22, // [22:33] is the sub-list for method output_type
+	11, // [11:22] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_tui_proto_init() }
@@ -870,7 +2332,7 @@ func file_tui_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tui_proto_rawDesc), len(file_tui_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   11,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,10 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TUIService_SendDatastream_FullMethodName = "/tui.TUIService/SendDatastream"
-	TUIService_StreamLogs_FullMethodName     = "/tui.TUIService/StreamLogs"
-	TUIService_SetWatch_FullMethodName       = "/tui.TUIService/SetWatch"
-	TUIService_SetCronTime_FullMethodName    = "/tui.TUIService/SetCronTime"
+	TUIService_SendDatastream_FullMethodName    = "/tui.TUIService/SendDatastream"
+	TUIService_StreamLogs_FullMethodName        = "/tui.TUIService/StreamLogs"
+	TUIService_SetWatch_FullMethodName          = "/tui.TUIService/SetWatch"
+	TUIService_SetCronTime_FullMethodName       = "/tui.TUIService/SetCronTime"
+	TUIService_CheckNow_FullMethodName          = "/tui.TUIService/CheckNow"
+	TUIService_UpdateContainer_FullMethodName   = "/tui.TUIService/UpdateContainer"
+	TUIService_RollbackContainer_FullMethodName = "/tui.TUIService/RollbackContainer"
+	TUIService_SetPin_FullMethodName            = "/tui.TUIService/SetPin"
+	TUIService_ClearQuarantine_FullMethodName   = "/tui.TUIService/ClearQuarantine"
+	TUIService_CancelUpdate_FullMethodName      = "/tui.TUIService/CancelUpdate"
+	TUIService_UpdateProject_FullMethodName     = "/tui.TUIService/UpdateProject"
 )
 
 // TUIServiceClient is the client API for TUIService service.
@@ -38,6 +45,13 @@ type TUIServiceClient interface {
 	StreamLogs(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[DataStreamReceived, LogLine], error)
 	SetWatch(ctx context.Context, in *SetWatchlistRequest, opts ...grpc.CallOption) (*SetWatchlistResponse, error)
 	SetCronTime(ctx context.Context, in *SetCronTimeRequest, opts ...grpc.CallOption) (*SetCronTimeResponse, error)
+	CheckNow(ctx context.Context, in *CheckNowRequest, opts ...grpc.CallOption) (*CheckNowResponse, error)
+	UpdateContainer(ctx context.Context, in *UpdateContainerRequest, opts ...grpc.CallOption) (*UpdateContainerResponse, error)
+	RollbackContainer(ctx context.Context, in *RollbackContainerRequest, opts ...grpc.CallOption) (*RollbackContainerResponse, error)
+	SetPin(ctx context.Context, in *SetPinRequest, opts ...grpc.CallOption) (*SetPinResponse, error)
+	ClearQuarantine(ctx context.Context, in *ClearQuarantineRequest, opts ...grpc.CallOption) (*ClearQuarantineResponse, error)
+	CancelUpdate(ctx context.Context, in *CancelUpdateRequest, opts ...grpc.CallOption) (*CancelUpdateResponse, error)
+	UpdateProject(ctx context.Context, in *UpdateProjectRequest, opts ...grpc.CallOption) (*UpdateProjectResponse, error)
 }
 
 type tUIServiceClient struct {
@@ -94,6 +108,76 @@ func (c *tUIServiceClient) SetCronTime(ctx context.Context, in *SetCronTimeReque
 	return out, nil
 }
 
+func (c *tUIServiceClient) CheckNow(ctx context.Context, in *CheckNowRequest, opts ...grpc.CallOption) (*CheckNowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckNowResponse)
+	err := c.cc.Invoke(ctx, TUIService_CheckNow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tUIServiceClient) UpdateContainer(ctx context.Context, in *UpdateContainerRequest, opts ...grpc.CallOption) (*UpdateContainerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateContainerResponse)
+	err := c.cc.Invoke(ctx, TUIService_UpdateContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tUIServiceClient) RollbackContainer(ctx context.Context, in *RollbackContainerRequest, opts ...grpc.CallOption) (*RollbackContainerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RollbackContainerResponse)
+	err := c.cc.Invoke(ctx, TUIService_RollbackContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tUIServiceClient) SetPin(ctx context.Context, in *SetPinRequest, opts ...grpc.CallOption) (*SetPinResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPinResponse)
+	err := c.cc.Invoke(ctx, TUIService_SetPin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tUIServiceClient) ClearQuarantine(ctx context.Context, in *ClearQuarantineRequest, opts ...grpc.CallOption) (*ClearQuarantineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearQuarantineResponse)
+	err := c.cc.Invoke(ctx, TUIService_ClearQuarantine_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tUIServiceClient) CancelUpdate(ctx context.Context, in *CancelUpdateRequest, opts ...grpc.CallOption) (*CancelUpdateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelUpdateResponse)
+	err := c.cc.Invoke(ctx, TUIService_CancelUpdate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tUIServiceClient) UpdateProject(ctx context.Context, in *UpdateProjectRequest, opts ...grpc.CallOption) (*UpdateProjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateProjectResponse)
+	err := c.cc.Invoke(ctx, TUIService_UpdateProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TUIServiceServer is the server API for TUIService service.
 // All implementations must embed UnimplementedTUIServiceServer
 // for forward compatibility.
@@ -107,6 +191,13 @@ type TUIServiceServer interface {
 	StreamLogs(grpc.BidiStreamingServer[DataStreamReceived, LogLine]) error
 	SetWatch(context.Context, *SetWatchlistRequest) (*SetWatchlistResponse, error)
 	SetCronTime(context.Context, *SetCronTimeRequest) (*SetCronTimeResponse, error)
+	CheckNow(context.Context, *CheckNowRequest) (*CheckNowResponse, error)
+	UpdateContainer(context.Context, *UpdateContainerRequest) (*UpdateContainerResponse, error)
+	RollbackContainer(context.Context, *RollbackContainerRequest) (*RollbackContainerResponse, error)
+	SetPin(context.Context, *SetPinRequest) (*SetPinResponse, error)
+	ClearQuarantine(context.Context, *ClearQuarantineRequest) (*ClearQuarantineResponse, error)
+	CancelUpdate(context.Context, *CancelUpdateRequest) (*CancelUpdateResponse, error)
+	UpdateProject(context.Context, *UpdateProjectRequest) (*UpdateProjectResponse, error)
 	mustEmbedUnimplementedTUIServiceServer()
 }
 
@@ -129,6 +220,27 @@ func (UnimplementedTUIServiceServer) SetWatch(context.Context, *SetWatchlistRequ
 func (UnimplementedTUIServiceServer) SetCronTime(context.Context, *SetCronTimeRequest) (*SetCronTimeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetCronTime not implemented")
 }
+func (UnimplementedTUIServiceServer) CheckNow(context.Context, *CheckNowRequest) (*CheckNowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckNow not implemented")
+}
+func (UnimplementedTUIServiceServer) UpdateContainer(context.Context, *UpdateContainerRequest) (*UpdateContainerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateContainer not implemented")
+}
+func (UnimplementedTUIServiceServer) RollbackContainer(context.Context, *RollbackContainerRequest) (*RollbackContainerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RollbackContainer not implemented")
+}
+func (UnimplementedTUIServiceServer) SetPin(context.Context, *SetPinRequest) (*SetPinResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPin not implemented")
+}
+func (UnimplementedTUIServiceServer) ClearQuarantine(context.Context, *ClearQuarantineRequest) (*ClearQuarantineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearQuarantine not implemented")
+}
+func (UnimplementedTUIServiceServer) CancelUpdate(context.Context, *CancelUpdateRequest) (*CancelUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelUpdate not implemented")
+}
+func (UnimplementedTUIServiceServer) UpdateProject(context.Context, *UpdateProjectRequest) (*UpdateProjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProject not implemented")
+}
 func (UnimplementedTUIServiceServer) mustEmbedUnimplementedTUIServiceServer() {}
 func (UnimplementedTUIServiceServer) testEmbeddedByValue()                    {}
 
@@ -200,6 +312,132 @@ func _TUIService_SetCronTime_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TUIService_CheckNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).CheckNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_CheckNow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).CheckNow(ctx, req.(*CheckNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TUIService_UpdateContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateContainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).UpdateContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_UpdateContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).UpdateContainer(ctx, req.(*UpdateContainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TUIService_RollbackContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackContainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).RollbackContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_RollbackContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).RollbackContainer(ctx, req.(*RollbackContainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TUIService_SetPin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).SetPin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_SetPin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).SetPin(ctx, req.(*SetPinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TUIService_ClearQuarantine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearQuarantineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).ClearQuarantine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_ClearQuarantine_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).ClearQuarantine(ctx, req.(*ClearQuarantineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TUIService_CancelUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).CancelUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_CancelUpdate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).CancelUpdate(ctx, req.(*CancelUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TUIService_UpdateProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TUIServiceServer).UpdateProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TUIService_UpdateProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TUIServiceServer).UpdateProject(ctx, req.(*UpdateProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TUIService_ServiceDesc is the grpc.ServiceDesc for TUIService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -215,6 +453,34 @@ var TUIService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SetCronTime",
 			Handler:    _TUIService_SetCronTime_Handler,
 		},
+		{
+			MethodName: "CheckNow",
+			Handler:    _TUIService_CheckNow_Handler,
+		},
+		{
+			MethodName: "UpdateContainer",
+			Handler:    _TUIService_UpdateContainer_Handler,
+		},
+		{
+			MethodName: "RollbackContainer",
+			Handler:    _TUIService_RollbackContainer_Handler,
+		},
+		{
+			MethodName: "SetPin",
+			Handler:    _TUIService_SetPin_Handler,
+		},
+		{
+			MethodName: "ClearQuarantine",
+			Handler:    _TUIService_ClearQuarantine_Handler,
+		},
+		{
+			MethodName: "CancelUpdate",
+			Handler:    _TUIService_CancelUpdate_Handler,
+		},
+		{
+			MethodName: "UpdateProject",
+			Handler:    _TUIService_UpdateProject_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
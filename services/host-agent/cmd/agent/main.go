@@ -76,9 +76,18 @@ func (p *program) run() {
 	// ---  Configuration Loading ---
 	p.config = config.MustLoad()
 	logger.Info("Configuration loaded successfully.")
+	agent.SetComposeWriteBack(p.config.ComposeWriteBack)
+	agent.SetMaxParallelUpdates(p.config.MaxParallelUpdates)
+	agent.SetJournalDir(p.config.JournalDir)
+	agentID, err := agent.LoadAgentID(p.config.IDFile)
+	if err != nil {
+		logger.Errorf("Agent ID init failed: %v", err)
+		return // Exit without an identity
+	}
+	agent.SetAgentID(agentID)
+	logger.Infof("Agent ID %s", agentID)
 
 	// --- 1. Start gRPC client ---
-	var err error
 	p.grpcClient, p.grpcConn, err = client.StartClient(*p.config)
 	if err != nil {
 		logger.Errorf("gRPC client init failed: %v", err)
@@ -97,16 +106,37 @@ func (p *program) run() {
 	var ctx context.Context
 	ctx, p.cancel = context.WithCancel(context.Background())
 
-	// --- 4. Register agent with orchestrator ---
-	regCtx, regCancel := context.WithTimeout(ctx, 10*time.Second)
-	defer regCancel()
-	if err := agent.RegisterAgent(p.dockerCli, regCtx, p.grpcClient); err != nil {
-		logger.Errorf("Agent registration failed: %v", err)
-		return // Exit if we can't register
+	// --- 4. Settle updates interrupted by a crash before anything else touches the containers ---
+	agent.ReplayJournal(p.dockerCli, ctx)
+
+	// --- 5. Connection supervisor: registers the agent and keeps its stream open ---
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		agent.Supervise(p.dockerCli, ctx, p.grpcClient)
+		logger.Info("Orchestrator connection supervisor stopped.")
+	}()
+
+	// --- 6. Local connection status endpoint ---
+	if p.config.StatusAddr != "" {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			if err := agent.ServeStatus(ctx, p.config.StatusAddr); err != nil {
+				logger.Warningf("Status endpoint on %s failed: %v", p.config.StatusAddr, err)
+			}
+		}()
 	}
-	logger.Info("Agent registered with orchestrator.")
 
-	// --- 6. Heartbeat goroutine ---
+	// --- 7. Container inventory from Docker events ---
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		agent.WatchInventory(p.dockerCli, ctx, p.grpcClient)
+		logger.Info("Inventory watcher stopped.")
+	}()
+
+	// --- 8. Heartbeat goroutine ---
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
@@ -115,6 +145,9 @@ func (p *program) run() {
 		for {
 			select {
 			case <-ticker.C:
+				if agent.ConnectionStatus().State != agent.StateConnected {
+					continue // the host is registered again on reconnect
+				}
 				if err := agent.Heartbeat(p.dockerCli, ctx, p.grpcClient); err != nil {
 					logger.Warningf("Heartbeat send failed: %v", err)
 				}
@@ -125,16 +158,6 @@ func (p *program) run() {
 		}
 	}()
 
-	// --- 7. UpdateContainer gRPC stream goroutine ---
-	p.wg.Add(1)
-	go func() {
-		defer p.wg.Done()
-		if err := agent.UpdateContainerStream(p.dockerCli, ctx, p.grpcClient); err != nil && ctx.Err() == nil {
-			logger.Errorf("UpdateContainer stream error: %v", err)
-		}
-		logger.Info("UpdateContainer stream stopped.")
-	}()
-
 	logger.Info("Agent is now running in the background.")
 }
 
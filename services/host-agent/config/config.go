@@ -12,6 +12,16 @@ import (
 // Config holds all configuration for the application.
 type Config struct {
 	OrchestratorAddr string `mapstructure:"orchestrator_addr"`
+	// ComposeWriteBack writes updated image tags back to the compose files of compose-managed containers.
+	ComposeWriteBack bool `mapstructure:"compose_write_back"`
+	// IDFile stores the ID the agent generates on first start to identify its host.
+	IDFile string `mapstructure:"id_file"`
+	// StatusAddr is the local address serving the connection status at /status; empty disables it.
+	StatusAddr string `mapstructure:"status_addr"`
+	// MaxParallelUpdates is how many updates of different containers run at once.
+	MaxParallelUpdates int `mapstructure:"max_parallel_updates"`
+	// JournalDir keeps the journal of updates in progress, replayed after a crash; empty disables it.
+	JournalDir string `mapstructure:"journal_dir"`
 }
 
 // MustLoad reads configuration using a priority system: flags > env > file > defaults.
@@ -38,11 +48,21 @@ func MustLoad() *Config {
 
 	// --- Set Defaults (Lowest Priority) ---
 	viper.SetDefault("orchestrator_addr", "localhost:50051")
+	viper.SetDefault("compose_write_back", false)
+	viper.SetDefault("id_file", defaultIDFile())
+	viper.SetDefault("status_addr", "127.0.0.1:9810")
+	viper.SetDefault("max_parallel_updates", 2)
+	viper.SetDefault("journal_dir", defaultJournalDir())
 
 	// --- Bind to Environment Variables ---
 	// This allows overriding config file values with env vars
 	viper.SetEnvPrefix("LIGHTHOUSE") // will look for LIGHTHOUSE_ORCHESTRATOR_ADDR
 	viper.BindEnv("orchestrator_addr", "ORCHESTRATOR_ADDR")
+	viper.BindEnv("compose_write_back")   // LIGHTHOUSE_COMPOSE_WRITE_BACK
+	viper.BindEnv("id_file")              // LIGHTHOUSE_ID_FILE
+	viper.BindEnv("status_addr")          // LIGHTHOUSE_STATUS_ADDR
+	viper.BindEnv("max_parallel_updates") // LIGHTHOUSE_MAX_PARALLEL_UPDATES
+	viper.BindEnv("journal_dir")          // LIGHTHOUSE_JOURNAL_DIR
 
 	// --- Read Configuration from file ---
 	if err := viper.ReadInConfig(); err != nil {
@@ -70,3 +90,19 @@ func MustLoad() *Config {
 
 	return &cfg
 }
+
+// defaultIDFile returns where the agent ID is stored when id_file is not configured.
+func defaultIDFile() string {
+	if dir := os.Getenv("ProgramData"); dir != "" {
+		return filepath.Join(dir, "LighthouseHostAgent", "agent-id")
+	}
+	return "/var/lib/lighthouse-host-agent/agent-id"
+}
+
+// defaultJournalDir returns where update journals are kept when journal_dir is not configured.
+func defaultJournalDir() string {
+	if dir := os.Getenv("ProgramData"); dir != "" {
+		return filepath.Join(dir, "LighthouseHostAgent", "journal")
+	}
+	return "/var/lib/lighthouse-host-agent/journal"
+}
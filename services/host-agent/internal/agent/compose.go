@@ -0,0 +1,234 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Labels Docker Compose sets on the containers it creates.
+const (
+	composeProjectLabel   = "com.docker.compose.project"
+	composeServiceLabel   = "com.docker.compose.service"
+	composeDependsOnLabel = "com.docker.compose.depends_on"
+)
+
+// composeInfo returns the compose project and service of a container and the services it
+// depends on. The depends_on label lists "service:condition:restart" entries separated by commas.
+func composeInfo(labels map[string]string) (project, service string, dependsOn []string) {
+	for _, dep := range strings.Split(labels[composeDependsOnLabel], ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(dep), ":")
+		if name != "" {
+			dependsOn = append(dependsOn, name)
+		}
+	}
+	return labels[composeProjectLabel], labels[composeServiceLabel], dependsOn
+}
+
+// composeConfigFilesLabel lists the compose files a project was started from, comma separated.
+const composeConfigFilesLabel = "com.docker.compose.project.config_files"
+
+var (
+	composeMu        sync.RWMutex
+	composeWriteBack bool
+	// composeFilesMu keeps updates running in parallel from rewriting a compose file at once.
+	composeFilesMu sync.Mutex
+)
+
+// SetComposeWriteBack enables writing updated image tags back to the compose files of a project.
+func SetComposeWriteBack(enabled bool) {
+	composeMu.Lock()
+	defer composeMu.Unlock()
+	composeWriteBack = enabled
+}
+
+// writeBackCompose replaces oldImage with newImage in the image field of the container's service
+// in its compose files, so the next "docker compose up" keeps the update. Every changed file is
+// backed up first. It returns a summary for the update status logs, empty when nothing was done.
+func writeBackCompose(labels map[string]string, oldImage, newImage string) string {
+	composeMu.RLock()
+	enabled := composeWriteBack
+	composeMu.RUnlock()
+	files := labels[composeConfigFilesLabel]
+	service := labels[composeServiceLabel]
+	if !enabled || files == "" || service == "" || oldImage == newImage {
+		return ""
+	}
+
+	composeFilesMu.Lock()
+	defer composeFilesMu.Unlock()
+	var notes []string
+	for _, path := range strings.Split(files, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		backup, err := rewriteComposeImage(path, service, oldImage, newImage)
+		switch {
+		case err != nil:
+			log.Printf("Compose write-back to %s failed: %v", path, err)
+			notes = append(notes, fmt.Sprintf("compose file %s not updated: %v", path, err))
+		case backup != "":
+			log.Printf("Compose file %s: service %s image %s -> %s (backup %s)", path, service, oldImage, newImage, backup)
+			notes = append(notes, fmt.Sprintf("compose file %s updated to %s (backup %s)", path, newImage, backup))
+		}
+	}
+	return strings.Join(notes, "; ")
+}
+
+// rewriteComposeImage edits the image of a service in one compose file in place, leaving the
+// rest of the file byte for byte as it was. It returns the backup path, or "" when the file
+// does not set the service's image to oldImage.
+func rewriteComposeImage(path, service, oldImage, newImage string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return "", fmt.Errorf("parse: %w", err)
+	}
+	services := mappingValue(documentRoot(&doc), "services")
+	serviceNode := mappingValue(services, service)
+	node := mappingValue(serviceNode, "image")
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return "", nil
+	}
+	if node.Value != oldImage {
+		if strings.Contains(node.Value, "$") {
+			return "", fmt.Errorf("image %q uses variables", node.Value)
+		}
+		return "", nil
+	}
+
+	lines := strings.SplitAfter(string(raw), "\n")
+	if node.Line < 1 || node.Line > len(lines) {
+		return "", fmt.Errorf("image of %s not found at line %d", service, node.Line)
+	}
+	line := lines[node.Line-1]
+	start := node.Column - 1
+	if start < 0 || start >= len(line) {
+		return "", fmt.Errorf("image of %s not found at line %d", service, node.Line)
+	}
+	// Inside a flow mapping such as {image: nginx, ports: [...]} a plain scalar ends at , ] or }
+	flow := documentRoot(&doc).Style&yaml.FlowStyle != 0 || services.Style&yaml.FlowStyle != 0 || serviceNode.Style&yaml.FlowStyle != 0
+	end, err := scalarEnd(line, start, node.Style, flow)
+	if err != nil {
+		return "", fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	if node.Style == 0 && line[start:end] != oldImage {
+		return "", fmt.Errorf("line %d: image %q spans more than the plain value %q", node.Line, line[start:end], oldImage)
+	}
+	lines[node.Line-1] = line[:start] + quoteLike(newImage, node.Style) + line[end:]
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	backup, err := writeBackup(path, raw, info.Mode().Perm())
+	if err != nil {
+		return "", fmt.Errorf("backup: %w", err)
+	}
+	tmp := path + ".lighthouse.tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "")), info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return backup, nil
+}
+
+// writeBackup saves raw next to path under a timestamped name that is not taken yet.
+func writeBackup(path string, raw []byte, perm os.FileMode) (string, error) {
+	base := fmt.Sprintf("%s.%s", path, time.Now().Format("20060102T150405"))
+	backup := base + ".bak"
+	for i := 1; ; i++ {
+		f, err := os.OpenFile(backup, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) {
+			backup = fmt.Sprintf("%s.%d.bak", base, i)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err := f.Write(raw); err != nil {
+			f.Close()
+			return "", err
+		}
+		return backup, f.Close()
+	}
+}
+
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarEnd returns the offset just past the scalar starting at start in line. flow tells
+// whether the scalar is inside a flow collection.
+func scalarEnd(line string, start int, style yaml.Style, flow bool) (int, error) {
+	switch style {
+	case yaml.DoubleQuotedStyle, yaml.SingleQuotedStyle:
+		quote := line[start]
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' && quote == '"' {
+				i++
+				continue
+			}
+			if line[i] == quote {
+				if quote == '\'' && i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("unterminated quoted image")
+	case 0:
+		end := len(strings.TrimRight(line, "\r\n"))
+		if i := strings.Index(line[start:], " #"); i >= 0 && start+i < end {
+			end = start + i
+		}
+		if flow {
+			if i := strings.IndexAny(line[start:end], ",]}"); i >= 0 {
+				end = start + i
+			}
+		}
+		return start + len(strings.TrimRight(line[start:end], " \t")), nil
+	default:
+		return 0, fmt.Errorf("unsupported image style")
+	}
+}
+
+// quoteLike renders an image reference in the quoting style of the value it replaces.
+func quoteLike(s string, style yaml.Style) string {
+	switch style {
+	case yaml.DoubleQuotedStyle:
+		return `"` + s + `"`
+	case yaml.SingleQuotedStyle:
+		return "'" + s + "'"
+	}
+	return s
+}
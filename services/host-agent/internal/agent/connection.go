@@ -0,0 +1,133 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	dockerclient "github.com/docker/docker/client"
+)
+
+const (
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = 2 * time.Minute
+	// stableConnection is how long a connection has to last for the backoff to start over.
+	stableConnection = time.Minute
+	registerTimeout  = 10 * time.Second
+	// helloTimeout bounds the wait for the orchestrator's answer to the handshake.
+	helloTimeout = 10 * time.Second
+)
+
+// ConnState is the state of the agent's connection to the orchestrator.
+type ConnState string
+
+const (
+	StateConnecting   ConnState = "connecting"
+	StateConnected    ConnState = "connected"
+	StateDisconnected ConnState = "disconnected"
+	StateStopped      ConnState = "stopped"
+)
+
+// ConnStatus describes the connection to the orchestrator.
+type ConnStatus struct {
+	State      ConnState `json:"state"`
+	Since      time.Time `json:"since"`
+	LastError  string    `json:"lastError,omitempty"`
+	Reconnects int       `json:"reconnects"`
+	// RetryAt is when the next connection attempt is made while disconnected.
+	RetryAt time.Time `json:"retryAt,omitzero"`
+}
+
+var (
+	connMu     sync.RWMutex
+	connStatus = ConnStatus{State: StateConnecting, Since: time.Now()}
+	// everConnected tells reconnections apart from the first connection.
+	everConnected bool
+)
+
+// ConnectionStatus returns the current state of the connection to the orchestrator.
+func ConnectionStatus() ConnStatus {
+	connMu.RLock()
+	defer connMu.RUnlock()
+	return connStatus
+}
+
+func setConnState(state ConnState, err error, retryAt time.Time) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	if state == StateConnected {
+		if everConnected {
+			connStatus.Reconnects++
+		}
+		everConnected = true
+	}
+	if state != connStatus.State {
+		log.Printf("Orchestrator connection %s", state)
+		connStatus.Since = time.Now()
+	}
+	connStatus.State = state
+	connStatus.RetryAt = retryAt
+	if err != nil {
+		connStatus.LastError = err.Error()
+	}
+}
+
+// Supervise keeps the agent connected to the orchestrator until ctx is done. Each connection
+// opens the command stream and registers the host again, so the agent recovers from
+// orchestrator restarts and network failures; lost connections are retried with jittered
+// exponential backoff.
+func Supervise(cli *dockerclient.Client, ctx context.Context, gRPCClient orchestrator.HostAgentServiceClient) {
+	backoff := minReconnectBackoff
+	for {
+		setConnState(StateConnecting, nil, time.Time{})
+		connectedAt, err := serveStream(cli, ctx, gRPCClient)
+		if ctx.Err() != nil {
+			setConnState(StateStopped, nil, time.Time{})
+			return
+		}
+		if !connectedAt.IsZero() && time.Since(connectedAt) >= stableConnection {
+			backoff = minReconnectBackoff
+		}
+		// Equal jitter, half the backoff plus a random half, keeps agents from reconnecting in
+		// lockstep after an orchestrator restart.
+		wait := backoff/2 + rand.N(backoff/2+1)
+		log.Printf("Orchestrator connection lost: %v; reconnecting in %s", err, wait.Round(time.Millisecond))
+		setConnState(StateDisconnected, err, time.Now().Add(wait))
+		if sleepContext(ctx, wait) != nil {
+			setConnState(StateStopped, nil, time.Time{})
+			return
+		}
+		backoff = min(backoff*2, maxReconnectBackoff)
+	}
+}
+
+// ServeStatus serves the connection status as JSON on addr at /status until ctx is done.
+func ServeStatus(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		status := ConnectionStatus()
+		w.Header().Set("Content-Type", "application/json")
+		if status.State != StateConnected {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(struct {
+			AgentID string `json:"agentId"`
+			ConnStatus
+		}{AgentID(), status})
+	})
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
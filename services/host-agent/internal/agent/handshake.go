@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"slices"
+	"sync"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	dockerclient "github.com/docker/docker/client"
+)
+
+// ProtocolVersion is the command stream protocol the agent speaks.
+const ProtocolVersion = 1
+
+// Version is the agent version reported to the orchestrator, set at build time with
+// -ldflags "-X .../internal/agent.Version=v1.2.3".
+var Version = "dev"
+
+// supportedFeatures are the features the agent offers in its hello.
+var supportedFeatures = []orchestrator.Feature{
+	orchestrator.Feature_FEATURE_HEALTH_CHECKS,
+	orchestrator.Feature_FEATURE_HOOKS,
+	orchestrator.Feature_FEATURE_COMPOSE,
+	orchestrator.Feature_FEATURE_START_FIRST,
+	orchestrator.Feature_FEATURE_CANCEL,
+}
+
+var (
+	featuresMu sync.RWMutex
+	// acceptedFeatures are the features the orchestrator accepted on the current stream.
+	acceptedFeatures []orchestrator.Feature
+)
+
+// featureAccepted reports whether the orchestrator accepted a feature in the last handshake.
+func featureAccepted(f orchestrator.Feature) bool {
+	featuresMu.RLock()
+	defer featuresMu.RUnlock()
+	return slices.Contains(acceptedFeatures, f)
+}
+
+// hello opens a command stream with an AgentHello and waits for the orchestrator's answer.
+// It fails when the orchestrator refuses the agent.
+func hello(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient) error {
+	dockerVersion := ""
+	if v, err := cli.ServerVersion(ctx); err != nil {
+		log.Printf("Docker version lookup failed: %v", err)
+	} else {
+		dockerVersion = v.Version
+	}
+	err := stream.Send(&orchestrator.AgentMessage{Message: &orchestrator.AgentMessage_Hello{Hello: &orchestrator.AgentHello{
+		AgentId:         AgentID(),
+		AgentVersion:    Version,
+		ProtocolVersion: ProtocolVersion,
+		Os:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		DockerVersion:   dockerVersion,
+		Features:        supportedFeatures,
+	}}})
+	if err != nil {
+		return err
+	}
+
+	in, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	reply := in.GetHello()
+	if reply == nil {
+		return errors.New("orchestrator did not answer the handshake, it may predate it")
+	}
+	if !reply.Accepted {
+		return fmt.Errorf("refused by orchestrator %s: %s", reply.OrchestratorVersion, reply.Message)
+	}
+	if reply.ProtocolVersion > ProtocolVersion {
+		return fmt.Errorf("orchestrator %s chose protocol %d, newer than %d", reply.OrchestratorVersion, reply.ProtocolVersion, ProtocolVersion)
+	}
+	log.Printf("Handshake with orchestrator %s (protocol %d) done", reply.OrchestratorVersion, reply.ProtocolVersion)
+	if reply.Message != "" {
+		log.Printf("Orchestrator: %s", reply.Message)
+	}
+
+	featuresMu.Lock()
+	acceptedFeatures = reply.Features
+	featuresMu.Unlock()
+	return nil
+}
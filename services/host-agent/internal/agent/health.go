@@ -0,0 +1,212 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"github.com/docker/docker/api/types/container"
+	dockerclient "github.com/docker/docker/client"
+)
+
+const (
+	defaultHealthTimeout = 60 * time.Second
+	defaultHealthGrace   = 10 * time.Second
+	healthProbeInterval  = 2 * time.Second
+	healthProbeTimeout   = 5 * time.Second
+)
+
+var (
+	// errNotYetHealthy is returned by a probe while a Docker HEALTHCHECK is still starting.
+	errNotYetHealthy = errors.New("health check still starting")
+	// errUnhealthy is returned by a probe when Docker reports the container unhealthy.
+	errUnhealthy = errors.New("container is unhealthy")
+)
+
+// waitHealthy probes a new container until it is healthy, then watches it for the grace period.
+// It fails when the probe doesn't pass in time, or the container stops or restarts.
+func waitHealthy(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, containerID string) error {
+	hc := update.HealthCheck
+	if hc == nil {
+		hc = &orchestrator.HealthCheck{}
+	}
+	if hc.Kind == orchestrator.HealthCheck_NONE {
+		return nil
+	}
+	timeout := defaultHealthTimeout
+	if hc.TimeoutSeconds > 0 {
+		timeout = time.Duration(hc.TimeoutSeconds) * time.Second
+	}
+	grace := defaultHealthGrace
+	if hc.GraceSeconds > 0 {
+		grace = time.Duration(hc.GraceSeconds) * time.Second
+	}
+
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("inspect new container: %w", err)
+	}
+	restarts := inspect.RestartCount
+	target := strings.ReplaceAll(hc.Target, "{ip}", containerIP(inspect))
+	sendStatus(stream, update, orchestrator.UpdateStatus_HEALTH_CHECK, fmt.Sprintf("Waiting up to %s for the %s", timeout, describeHealthCheck(hc.Kind, target)))
+
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		if err := checkRunning(cli, ctx, containerID, restarts); err != nil {
+			return err
+		}
+		lastErr = probe(cli, ctx, hc.Kind, target, containerID)
+		if lastErr == nil {
+			break
+		}
+		if errors.Is(lastErr, errUnhealthy) {
+			return lastErr
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not healthy after %s: %v", timeout, lastErr)
+		}
+		if err := sleepContext(ctx, healthProbeInterval); err != nil {
+			return err
+		}
+	}
+
+	sendStatus(stream, update, orchestrator.UpdateStatus_HEALTH_CHECK, fmt.Sprintf("Healthy, watching for restarts for %s", grace))
+	end := time.Now().Add(grace)
+	for time.Now().Before(end) {
+		if err := sleepContext(ctx, min(healthProbeInterval, time.Until(end))); err != nil {
+			return err
+		}
+		if err := checkRunning(cli, ctx, containerID, restarts); err != nil {
+			return err
+		}
+		if hc.Kind == orchestrator.HealthCheck_DOCKER {
+			if err := probe(cli, ctx, hc.Kind, target, containerID); errors.Is(err, errUnhealthy) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRunning fails when the container is no longer running or restarted since restarts was read.
+func checkRunning(cli *dockerclient.Client, ctx context.Context, containerID string, restarts int) error {
+	inspect, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("inspect new container: %w", err)
+	}
+	if inspect.RestartCount > restarts || inspect.State.Restarting {
+		return fmt.Errorf("container restarted %d time(s)", inspect.RestartCount-restarts)
+	}
+	if !inspect.State.Running {
+		return fmt.Errorf("container %s with exit code %d", inspect.State.Status, inspect.State.ExitCode)
+	}
+	return nil
+}
+
+// probe runs one health check attempt.
+func probe(cli *dockerclient.Client, ctx context.Context, kind orchestrator.HealthCheck_Kind, target, containerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+	switch kind {
+	case orchestrator.HealthCheck_DOCKER:
+		inspect, err := cli.ContainerInspect(ctx, containerID)
+		if err != nil {
+			return err
+		}
+		health := inspect.State.Health
+		if health == nil {
+			return nil // the image defines no HEALTHCHECK
+		}
+		switch health.Status {
+		case container.Healthy:
+			return nil
+		case container.Unhealthy:
+			if n := len(health.Log); n > 0 {
+				return fmt.Errorf("%w: %s", errUnhealthy, strings.TrimSpace(health.Log[n-1].Output))
+			}
+			return errUnhealthy
+		}
+		return errNotYetHealthy
+	case orchestrator.HealthCheck_HTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("GET %s returned %s", target, resp.Status)
+		}
+		return nil
+	case orchestrator.HealthCheck_TCP:
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case orchestrator.HealthCheck_EXEC:
+		var out bytes.Buffer
+		if err := execHook(cli, ctx, containerID, target, &out); err != nil {
+			if msg := strings.TrimSpace(out.String()); msg != "" {
+				return fmt.Errorf("%v: %s", err, msg)
+			}
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown health check kind %s", kind)
+}
+
+func describeHealthCheck(kind orchestrator.HealthCheck_Kind, target string) string {
+	switch kind {
+	case orchestrator.HealthCheck_HTTP:
+		return "HTTP check of " + target
+	case orchestrator.HealthCheck_TCP:
+		return "TCP check of " + target
+	case orchestrator.HealthCheck_EXEC:
+		return "check command " + target
+	}
+	return "Docker health check"
+}
+
+// containerIP returns the IP address of a container, from the first of its networks by name.
+func containerIP(inspect container.InspectResponse) string {
+	if inspect.NetworkSettings == nil {
+		return ""
+	}
+	names := make([]string, 0, len(inspect.NetworkSettings.Networks))
+	for name := range inspect.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ip := inspect.NetworkSettings.Networks[name].IPAddress; ip != "" {
+			return ip
+		}
+	}
+	return inspect.NetworkSettings.IPAddress
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -3,136 +3,25 @@ package agent
 
 import (
 	"context"
-	"fmt"
 	"log"
-	"net"
-	"strconv"
-	"strings"
 
 	host_agent "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
-	"github.com/docker/docker/api/types/container"
 	dockerclient "github.com/docker/docker/client"
 )
 
+// Heartbeat reports the inventory kept up to date by WatchInventory, loading it first if needed.
+// Only its hash is sent while the orchestrator holds the same inventory.
 func Heartbeat(cli *dockerclient.Client, ctx context.Context, gRPCClient host_agent.HostAgentServiceClient) error {
-	// List all containers on the host
-	containersList, err := cli.ContainerList(ctx, container.ListOptions{
-		All: true, // include stopped containers if you want
-	})
-	if err != nil {
-		log.Printf("Failed listing containers: %v", err)
-		return err
-	}
-	var containers []*host_agent.ContainerInfo
-
-	// Iterate through each container and inspect it for full details
-	// Iterate through each container and inspect it for full details
-	for _, c := range containersList {
-		inspect, err := cli.ContainerInspect(ctx, c.ID)
-		if err != nil {
-			log.Printf("Inspect failed for container %s: %v", c.ID, err)
-			continue
-		}
-		// Ports -> structured PortMapping
-		var ports []*host_agent.PortMapping
-		if inspect.NetworkSettings != nil {
-			for containerPortProto, bindings := range inspect.NetworkSettings.Ports {
-				// containerPortProto looks like "80/tcp"
-				parts := strings.Split(string(containerPortProto), "/")
-				if len(parts) != 2 {
-					continue
-				}
-				containerPort, err := strconv.Atoi(parts[0])
-				if err != nil {
-					continue
-				}
-				protocol := parts[1]
-
-				// If no bindings, it's exposed internally only
-				if len(bindings) == 0 {
-					ports = append(ports, &host_agent.PortMapping{
-						HostIp:        "",
-						HostPort:      0,
-						ContainerPort: uint32(containerPort),
-						Protocol:      protocol,
-					})
-					continue
-				}
-
-				for _, b := range bindings {
-					hostIP := b.HostIP
-					if hostIP == "" {
-						hostIP = "0.0.0.0"
-					}
-					hostPort, _ := strconv.Atoi(b.HostPort)
-
-					ports = append(ports, &host_agent.PortMapping{
-						HostIp:        hostIP,
-						HostPort:      uint32(hostPort),
-						ContainerPort: uint32(containerPort),
-						Protocol:      protocol,
-					})
-				}
-			}
-		}
-
-		// Env vars
-		var envVars []string
-		if inspect.Config != nil {
-			envVars = append(envVars, inspect.Config.Env...)
-		}
-
-		// Volumes (mount sources)
-		var volumes []string
-		for _, m := range inspect.Mounts {
-			volumes = append(volumes, m.Source)
-		}
-
-		// Build our container info
-		cInfo := host_agent.ContainerInfo{
-			ContainerID: c.ID,
-			Name:        strings.TrimPrefix(inspect.Name, "/"),
-			Image:       inspect.Config.Image,
-			Ports:       ports,
-			EnvVars:     envVars,
-			Volumes:     volumes,
-			Network:     string(inspect.HostConfig.NetworkMode),
+	heartbeatMu.Lock()
+	defer heartbeatMu.Unlock()
+	if inventorySnapshot() == nil {
+		if _, err := resyncInventory(cli, ctx); err != nil {
+			return err
 		}
-
-		containers = append(containers, &cInfo)
-	}
-	macAddress, err := getLocalMacAddress()
-	if err != nil {
-		log.Printf("MAC address lookup failed: %v", err)
 	}
-
-	HeartbeatRequest := &host_agent.HeartbeatRequest{
-		MacAddress: macAddress,
-		Containers: containers,
-	}
-	_, err = gRPCClient.Heartbeat(context.Background(), HeartbeatRequest)
-	if err != nil {
+	if err := reportInventory(ctx, gRPCClient, nil, nil); err != nil {
 		log.Printf("Heartbeat send failed: %v", err)
 		return err
 	}
 	return nil
 }
-
-func getLocalMacAddress() (string, error) {
-	interfaces, err := net.Interfaces()
-	if err != nil {
-		return "", fmt.Errorf("failed to get network interfaces: %v", err)
-	}
-
-	for _, iface := range interfaces {
-		// Skip loopback and down interfaces
-		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
-			continue
-		}
-		mac := iface.HardwareAddr.String()
-		if mac != "" {
-			return mac, nil
-		}
-	}
-	return "", fmt.Errorf("no valid MAC address found")
-}
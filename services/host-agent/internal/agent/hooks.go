@@ -0,0 +1,207 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"github.com/docker/docker/api/types/container"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	defaultHookTimeout = 60 * time.Second
+	hookFlushInterval  = time.Second
+	hookFlushSize      = 4 << 10
+)
+
+// hookEnv describes the update a hook runs for.
+type hookEnv struct {
+	container string
+	oldImage  string
+	newImage  string
+}
+
+// runHooks runs the hooks of a phase in order, in containerID for exec hooks. It returns the
+// failure policy of the first failing hook that is not ignored, and its error.
+func runHooks(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, phase orchestrator.Hook_Phase, containerID string, env hookEnv) (orchestrator.Hook_OnFailure, error) {
+	for _, hook := range update.Hooks {
+		if hook.Phase != phase {
+			continue
+		}
+		name := hook.Name
+		if name == "" {
+			name = strings.ToLower(hook.Kind.String())
+		}
+		sendStatus(stream, update, orchestrator.UpdateStatus_HOOK, fmt.Sprintf("Running %s hook %s", strings.ToLower(phase.String()), name))
+
+		timeout := defaultHookTimeout
+		if hook.TimeoutSeconds > 0 {
+			timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+		out := &hookWriter{stream: stream, update: update, prefix: "[" + name + "] "}
+		var err error
+		switch hook.Kind {
+		case orchestrator.Hook_EXEC:
+			err = execHook(cli, hookCtx, containerID, hook.Command, out)
+		case orchestrator.Hook_HOST:
+			err = hostHook(hookCtx, hook.Command, phase, env, out)
+		case orchestrator.Hook_HTTP:
+			err = httpHook(hookCtx, hook, phase, env, out)
+		default:
+			err = fmt.Errorf("unknown hook kind %s", hook.Kind)
+		}
+		if err == nil && hookCtx.Err() != nil {
+			err = hookCtx.Err()
+		}
+		cancel()
+		out.Flush()
+
+		if err == nil {
+			sendStatus(stream, update, orchestrator.UpdateStatus_HOOK, fmt.Sprintf("Hook %s succeeded", name))
+			continue
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		log.Printf("Hook %s of %s failed: %v", name, update.ContainerName, err)
+		if hook.OnFailure == orchestrator.Hook_IGNORE {
+			sendStatus(stream, update, orchestrator.UpdateStatus_HOOK, fmt.Sprintf("Hook %s failed, ignored: %v", name, err))
+			continue
+		}
+		return hook.OnFailure, fmt.Errorf("hook %s: %w", name, err)
+	}
+	return orchestrator.Hook_ABORT, nil
+}
+
+// execHook runs a shell command inside a container and fails on a non-zero exit code.
+func execHook(cli *dockerclient.Client, ctx context.Context, containerID, command string, out io.Writer) error {
+	created, err := cli.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          []string{"sh", "-c", command},
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("create exec: %w", err)
+	}
+	resp, err := cli.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("attach exec: %w", err)
+	}
+	defer resp.Close()
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(out, out, resp.Reader)
+		copied <- err
+	}()
+	select {
+	case err := <-copied:
+		if err != nil {
+			return fmt.Errorf("read output: %w", err)
+		}
+	case <-ctx.Done():
+		resp.Close()
+		<-copied
+		return ctx.Err()
+	}
+
+	inspect, err := cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return fmt.Errorf("inspect exec: %w", err)
+	}
+	if inspect.ExitCode != 0 {
+		return fmt.Errorf("exit code %d", inspect.ExitCode)
+	}
+	return nil
+}
+
+// hostHook runs a shell command on the host with the update described in its environment.
+func hostHook(ctx context.Context, command string, phase orchestrator.Hook_Phase, env hookEnv, out io.Writer) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"LIGHTHOUSE_CONTAINER="+env.container,
+		"LIGHTHOUSE_OLD_IMAGE="+env.oldImage,
+		"LIGHTHOUSE_NEW_IMAGE="+env.newImage,
+		"LIGHTHOUSE_PHASE="+strings.ToLower(phase.String()),
+	)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.WaitDelay = 5 * time.Second // don't wait on children that keep the output open
+	return cmd.Run()
+}
+
+// httpHook sends the update as JSON to a URL and fails unless it answers with 2xx.
+func httpHook(ctx context.Context, hook *orchestrator.Hook, phase orchestrator.Hook_Phase, env hookEnv, out io.Writer) error {
+	body, err := json.Marshal(map[string]string{
+		"container": env.container,
+		"phase":     strings.ToLower(phase.String()),
+		"old_image": env.oldImage,
+		"new_image": env.newImage,
+	})
+	if err != nil {
+		return err
+	}
+	method := hook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(out, io.LimitReader(resp.Body, hookFlushSize))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s returned %s", req.Method, hook.Url, resp.Status)
+	}
+	return nil
+}
+
+// hookWriter streams hook output as HOOK statuses, sending what was written at most once a
+// second or once enough output accumulated.
+type hookWriter struct {
+	stream    orchestrator.HostAgentService_ConnectAgentStreamClient
+	update    *orchestrator.UpdateContainerCommand
+	prefix    string
+	buf       bytes.Buffer
+	lastFlush time.Time
+}
+
+func (w *hookWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	if w.lastFlush.IsZero() {
+		w.lastFlush = time.Now()
+	}
+	if w.buf.Len() >= hookFlushSize || time.Since(w.lastFlush) >= hookFlushInterval {
+		w.Flush()
+	}
+	return len(p), nil
+}
+
+// Flush sends the buffered output.
+func (w *hookWriter) Flush() {
+	w.lastFlush = time.Now()
+	if w.buf.Len() == 0 {
+		return
+	}
+	sendStatus(w.stream, w.update, orchestrator.UpdateStatus_HOOK, w.prefix+strings.TrimRight(w.buf.String(), "\n"))
+	w.buf.Reset()
+}
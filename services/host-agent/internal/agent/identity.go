@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var (
+	identityMu sync.RWMutex
+	agentID    string
+)
+
+// SetAgentID sets the ID the agent identifies itself with to the orchestrator.
+func SetAgentID(id string) {
+	identityMu.Lock()
+	defer identityMu.Unlock()
+	agentID = id
+}
+
+// AgentID returns the ID set with SetAgentID.
+func AgentID() string {
+	identityMu.RLock()
+	defer identityMu.RUnlock()
+	return agentID
+}
+
+// LoadAgentID reads the agent ID stored at path. On first start, when there is none, it
+// generates a UUID and stores it there so the agent keeps its identity across restarts.
+func LoadAgentID(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err == nil {
+		id := strings.TrimSpace(string(raw))
+		if _, err := uuid.Parse(id); err != nil {
+			return "", fmt.Errorf("invalid agent ID in %s: %w", path, err)
+		}
+		return id, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", fmt.Errorf("read agent ID: %w", err)
+	}
+
+	id := uuid.NewString()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create agent ID directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("write agent ID: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,363 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	host_agent "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// inventoryResyncInterval is how often the inventory is rebuilt from scratch in case an
+	// event was missed.
+	inventoryResyncInterval = 5 * time.Minute
+	// inventoryDebounce batches the events of one change, such as create and start.
+	inventoryDebounce = 500 * time.Millisecond
+	eventsRetryDelay  = 5 * time.Second
+)
+
+// inventoryEvents are the container events that change what the agent reports.
+var inventoryEvents = []string{"create", "start", "die", "destroy", "rename", "health_status", "pause", "unpause"}
+
+var (
+	inventoryMu sync.RWMutex
+	// inventory holds the reported containers by ID; nil until first loaded.
+	inventory map[string]*host_agent.ContainerInfo
+
+	// heartbeatMu serializes changes to the inventory with reporting them, so the hash sent
+	// always matches the changes sent with it.
+	heartbeatMu sync.Mutex
+	// ackedHash is the inventory hash the orchestrator last acknowledged holding. When empty
+	// the next heartbeat sends the whole inventory.
+	ackedHash string
+)
+
+// containerInfo inspects a container and describes it for the orchestrator. It returns nil for
+// containers that only exist during an update.
+func containerInfo(cli *dockerclient.Client, ctx context.Context, id string) (*host_agent.ContainerInfo, error) {
+	inspect, err := cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if isTransient([]string{inspect.Name}) || inspect.Config == nil || inspect.HostConfig == nil {
+		return nil, nil
+	}
+
+	// Ports -> structured PortMapping
+	var ports []*host_agent.PortMapping
+	if inspect.NetworkSettings != nil {
+		for containerPortProto, bindings := range inspect.NetworkSettings.Ports {
+			// containerPortProto looks like "80/tcp"
+			parts := strings.Split(string(containerPortProto), "/")
+			if len(parts) != 2 {
+				continue
+			}
+			containerPort, err := strconv.Atoi(parts[0])
+			if err != nil {
+				continue
+			}
+			protocol := parts[1]
+
+			// If no bindings, it's exposed internally only
+			if len(bindings) == 0 {
+				ports = append(ports, &host_agent.PortMapping{
+					HostIp:        "",
+					HostPort:      0,
+					ContainerPort: uint32(containerPort),
+					Protocol:      protocol,
+				})
+				continue
+			}
+
+			for _, b := range bindings {
+				hostIP := b.HostIP
+				if hostIP == "" {
+					hostIP = "0.0.0.0"
+				}
+				hostPort, _ := strconv.Atoi(b.HostPort)
+
+				ports = append(ports, &host_agent.PortMapping{
+					HostIp:        hostIP,
+					HostPort:      uint32(hostPort),
+					ContainerPort: uint32(containerPort),
+					Protocol:      protocol,
+				})
+			}
+		}
+	}
+
+	// Volumes (mount sources)
+	var volumes []string
+	for _, m := range inspect.Mounts {
+		volumes = append(volumes, m.Source)
+	}
+
+	var state, health string
+	if inspect.State != nil {
+		state = string(inspect.State.Status)
+		if inspect.State.Health != nil {
+			health = string(inspect.State.Health.Status)
+		}
+	}
+
+	project, service, dependsOn := composeInfo(inspect.Config.Labels)
+	return &host_agent.ContainerInfo{
+		ContainerID: inspect.ID,
+		Name:        strings.TrimPrefix(inspect.Name, "/"),
+		Image:       inspect.Config.Image,
+		Ports:       ports,
+		EnvVars:     inspect.Config.Env,
+		Volumes:     volumes,
+		Network:     string(inspect.HostConfig.NetworkMode),
+		Labels:      inspect.Config.Labels,
+		ImageDigest: repoDigest(cli, ctx, inspect.Image, inspect.Config.Image),
+
+		ComposeProject: project,
+		ComposeService: service,
+		DependsOn:      dependsOn,
+		Spec:           containerSpec(cli, ctx, &inspect),
+		State:          state,
+		Health:         health,
+	}, nil
+}
+
+// resyncInventory rebuilds the inventory from every container on the host and returns it.
+func resyncInventory(cli *dockerclient.Client, ctx context.Context) ([]*host_agent.ContainerInfo, error) {
+	containersList, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		log.Printf("Failed listing containers: %v", err)
+		return nil, err
+	}
+	fresh := make(map[string]*host_agent.ContainerInfo, len(containersList))
+	for _, c := range containersList {
+		if isTransient(c.Names) {
+			continue
+		}
+		info, err := containerInfo(cli, ctx, c.ID)
+		if err != nil {
+			log.Printf("Inspect failed for container %s: %v", c.ID, err)
+			continue
+		}
+		if info != nil {
+			fresh[c.ID] = info
+		}
+	}
+	inventoryMu.Lock()
+	inventory = fresh
+	inventoryMu.Unlock()
+	return inventorySnapshot(), nil
+}
+
+// inventorySnapshot returns the inventory sorted by container name, nil until it was loaded.
+func inventorySnapshot() []*host_agent.ContainerInfo {
+	inventoryMu.RLock()
+	defer inventoryMu.RUnlock()
+	if inventory == nil {
+		return nil
+	}
+	out := make([]*host_agent.ContainerInfo, 0, len(inventory))
+	for _, c := range inventory {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// refreshContainers inspects the given containers again and returns the ones that changed
+// and the IDs of the ones that are gone.
+func refreshContainers(cli *dockerclient.Client, ctx context.Context, ids map[string]bool) ([]*host_agent.ContainerInfo, []string) {
+	var changed []*host_agent.ContainerInfo
+	var removed []string
+	for id := range ids {
+		info, err := containerInfo(cli, ctx, id)
+		if err != nil && !errdefs.IsNotFound(err) {
+			log.Printf("Inspect failed for container %s: %v", id, err)
+			continue
+		}
+		inventoryMu.Lock()
+		if inventory == nil {
+			inventory = map[string]*host_agent.ContainerInfo{}
+		}
+		_, known := inventory[id]
+		if info == nil {
+			delete(inventory, id)
+		} else {
+			inventory[id] = info
+		}
+		inventoryMu.Unlock()
+		switch {
+		case info != nil:
+			changed = append(changed, info)
+		case known:
+			removed = append(removed, id)
+		}
+	}
+	return changed, removed
+}
+
+// resyncAndReport rebuilds the inventory and sends it if it changed.
+func resyncAndReport(cli *dockerclient.Client, ctx context.Context, gRPCClient host_agent.HostAgentServiceClient) error {
+	heartbeatMu.Lock()
+	defer heartbeatMu.Unlock()
+	if _, err := resyncInventory(cli, ctx); err != nil {
+		return err
+	}
+	if err := reportInventory(ctx, gRPCClient, nil, nil); err != nil {
+		log.Printf("Send inventory failed: %v", err)
+	}
+	return nil
+}
+
+// WatchInventory keeps the inventory up to date from Docker events until ctx is done, and sends
+// each change to the orchestrator right away. The inventory is also rebuilt every few minutes,
+// and whenever the event stream is interrupted, and sent if it changed.
+func WatchInventory(cli *dockerclient.Client, ctx context.Context, gRPCClient host_agent.HostAgentServiceClient) {
+	for {
+		err := watchEvents(cli, ctx, gRPCClient)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Docker event stream interrupted: %v; resubscribing in %s", err, eventsRetryDelay)
+		if sleepContext(ctx, eventsRetryDelay) != nil {
+			return
+		}
+	}
+}
+
+func watchEvents(cli *dockerclient.Client, ctx context.Context, gRPCClient host_agent.HostAgentServiceClient) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, e := range inventoryEvents {
+		args.Add("event", e)
+	}
+	// Subscribe before the resync so that no change in between is missed.
+	msgs, errs := cli.Events(ctx, events.ListOptions{Filters: args})
+	if err := resyncAndReport(cli, ctx, gRPCClient); err != nil {
+		return err
+	}
+
+	resync := time.NewTicker(inventoryResyncInterval)
+	defer resync.Stop()
+	flush := time.NewTimer(inventoryDebounce)
+	flush.Stop()
+	pending := map[string]bool{}
+	for {
+		select {
+		case msg := <-msgs:
+			if len(pending) == 0 {
+				flush.Reset(inventoryDebounce)
+			}
+			pending[msg.Actor.ID] = true
+		case <-flush.C:
+			heartbeatMu.Lock()
+			changed, removed := refreshContainers(cli, ctx, pending)
+			if len(changed) > 0 || len(removed) > 0 {
+				if err := reportInventory(ctx, gRPCClient, changed, removed); err != nil {
+					log.Printf("Send inventory changes failed: %v", err)
+				}
+			}
+			heartbeatMu.Unlock()
+			pending = map[string]bool{}
+		case <-resync.C:
+			if err := resyncAndReport(cli, ctx, gRPCClient); err != nil {
+				log.Printf("Inventory resync failed: %v", err)
+			}
+		case err := <-errs:
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// reportInventory sends the inventory to the orchestrator. Only the given changes are sent when
+// the orchestrator holds the inventory they apply to, and nothing but the hash when nothing
+// changed since it last acknowledged one. Otherwise, or when the orchestrator asks for it, the
+// whole inventory is sent. Changes made while disconnected are not sent: the host is registered
+// with its full inventory on reconnect. heartbeatMu must be held.
+func reportInventory(ctx context.Context, gRPCClient host_agent.HostAgentServiceClient, changed []*host_agent.ContainerInfo, removed []string) error {
+	if ConnectionStatus().State != StateConnected {
+		ackedHash = ""
+		return nil
+	}
+	containers := inventorySnapshot()
+	hash := inventoryHash(containers)
+	req := &host_agent.HeartbeatRequest{InventoryHash: hash}
+	switch {
+	case ackedHash == "" || (hash != ackedHash && len(changed) == 0 && len(removed) == 0):
+		req.Containers = containers
+	case hash == ackedHash:
+		req.Partial, req.BaseHash = true, ackedHash
+	default:
+		req.Partial, req.BaseHash, req.Containers, req.RemovedContainerIds = true, ackedHash, changed, removed
+		names := make([]string, 0, len(changed))
+		for _, c := range changed {
+			names = append(names, c.Name+" "+c.State)
+		}
+		log.Printf("Inventory changed: %d updated [%s], %d removed", len(changed), strings.Join(names, ", "), len(removed))
+	}
+	res, err := sendHeartbeat(ctx, gRPCClient, req)
+	if err == nil && res.ResyncRequired {
+		log.Printf("Orchestrator holds inventory %q, not %q; sending all containers", res.InventoryHash, req.BaseHash)
+		res, err = sendHeartbeat(ctx, gRPCClient, &host_agent.HeartbeatRequest{InventoryHash: hash, Containers: containers})
+	}
+	if err != nil {
+		ackedHash = "" // the orchestrator may have missed changes
+		return err
+	}
+	ackedHash = res.InventoryHash
+	return nil
+}
+
+// inventoryHash returns a hash of containers that changes with any reported setting.
+func inventoryHash(containers []*host_agent.ContainerInfo) string {
+	sorted := slices.Clone(containers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ContainerID < sorted[j].ContainerID })
+	h := sha256.New()
+	for _, c := range sorted {
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(c)
+		if err != nil {
+			log.Printf("Hash container %s failed: %v", c.Name, err)
+			continue
+		}
+		binary.Write(h, binary.BigEndian, uint64(len(b)))
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sendHeartbeat(ctx context.Context, gRPCClient host_agent.HostAgentServiceClient, req *host_agent.HeartbeatRequest) (*host_agent.HeartbeatResponse, error) {
+	macAddress, err := GetMACAddress()
+	if err != nil {
+		log.Printf("MAC address lookup failed: %v", err)
+	}
+	req.AgentId = AgentID()
+	req.MacAddress = macAddress
+	ctx, cancel := context.WithTimeout(ctx, registerTimeout)
+	defer cancel()
+	res, err := gRPCClient.Heartbeat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Success {
+		return nil, fmt.Errorf("rejected: %s", res.Message)
+	}
+	return res, nil
+}
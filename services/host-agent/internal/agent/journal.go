@@ -0,0 +1,342 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// journalStep is the step an update was about to take when its journal entry was written.
+type journalStep string
+
+const (
+	stepCreateStaging journalStep = "create-staging" // creating the new container next to the running original
+	stepStopOriginal  journalStep = "stop-original"  // stopping the original and renaming it out of the way
+	stepCreate        journalStep = "create"         // original stopped; creating the new container under its name
+	stepStart         journalStep = "start"          // new container created; starting it
+	stepVerify        journalStep = "verify"         // new container started; running hooks and health checks
+	stepSwap          journalStep = "swap"           // stopping the original and handing its name to the new container
+	stepCommit        journalStep = "commit"         // update succeeded; removing the original
+)
+
+// journalEntry is the on-disk record of an update in progress. It holds everything needed to
+// finish the update or restore the original container should the agent die half way. The name
+// of the new container is written before it is created, so one created just before the agent
+// died is found even though its ID never made it to the journal.
+type journalEntry struct {
+	CommandID        string                    `json:"command_id,omitempty"`
+	ContainerName    string                    `json:"container_name"`
+	Image            string                    `json:"image"`
+	Digest           string                    `json:"digest,omitempty"`
+	Step             journalStep               `json:"step"`
+	NewContainerName string                    `json:"new_container_name,omitempty"`
+	NewContainerID   string                    `json:"new_container_id,omitempty"`
+	Original         container.InspectResponse `json:"original"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
+}
+
+var (
+	journalMu  sync.Mutex
+	journalDir string
+	// journalReports are the outcomes of replayed entries, sent once the stream is open.
+	journalReports []*orchestrator.UpdateStatus
+)
+
+// SetJournalDir sets the directory update journals are kept in; empty disables the journal.
+func SetJournalDir(dir string) {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+	journalDir = dir
+}
+
+// newJournalEntry starts the journal of an update of the container described by inspect.
+func newJournalEntry(update *orchestrator.UpdateContainerCommand, inspect *container.InspectResponse) *journalEntry {
+	return &journalEntry{
+		CommandID:     update.CommandId,
+		ContainerName: strings.TrimPrefix(inspect.Name, "/"),
+		Image:         update.Image,
+		Digest:        update.Digest,
+		Original:      *inspect,
+	}
+}
+
+// record durably stores the entry at the given step. It returns once the entry is on disk.
+func (e *journalEntry) record(step journalStep) error {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+	if journalDir == "" {
+		return nil
+	}
+	e.Step = step
+	e.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(journalDir, 0o700); err != nil {
+		return err
+	}
+	return writeFileSync(filepath.Join(journalDir, e.ContainerName+".json"), data)
+}
+
+// clear removes the entry once the update is settled.
+func (e *journalEntry) clear() {
+	journalMu.Lock()
+	defer journalMu.Unlock()
+	if journalDir == "" {
+		return
+	}
+	if err := os.Remove(filepath.Join(journalDir, e.ContainerName+".json")); err != nil && !os.IsNotExist(err) {
+		log.Printf("Remove update journal of %s failed: %v", e.ContainerName, err)
+	}
+}
+
+// writeFileSync replaces path with data so that either the old or the new content survives a crash.
+func writeFileSync(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	// Persist the rename; directories can't be synced on every platform.
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// ReplayJournal settles the updates the agent was in the middle of when it last stopped. An
+// update that had already succeeded is completed; any other has its original container
+// restored. Entries that can't be settled are kept for the next start. The outcomes are
+// reported to the orchestrator once the stream is open.
+func ReplayJournal(cli *dockerclient.Client, ctx context.Context) {
+	journalMu.Lock()
+	dir := journalDir
+	journalMu.Unlock()
+	if dir == "" {
+		return
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		log.Printf("List update journal failed: %v", err)
+		return
+	}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Read update journal %s failed: %v", path, err)
+			continue
+		}
+		var e journalEntry
+		if err := json.Unmarshal(raw, &e); err != nil || e.ContainerName == "" || e.Original.ContainerJSONBase == nil {
+			log.Printf("Skipping unreadable update journal %s: %v", path, err)
+			continue
+		}
+
+		log.Printf("Replaying interrupted update of %s to %s (step %s)", e.ContainerName, e.Image, e.Step)
+		var status *orchestrator.UpdateStatus
+		if e.Step == stepCommit {
+			status, err = completeJournaled(cli, ctx, &e)
+		} else {
+			status, err = restoreJournaled(cli, ctx, &e)
+		}
+		if err != nil {
+			log.Printf("Replay of update of %s failed, keeping its journal: %v", e.ContainerName, err)
+		} else {
+			log.Printf("Replayed update of %s: %s", e.ContainerName, status.Logs)
+			e.clear()
+		}
+		journalMu.Lock()
+		journalReports = append(journalReports, status)
+		journalMu.Unlock()
+	}
+}
+
+// flushJournalReports sends the outcomes of replayed journal entries on a newly opened stream.
+func flushJournalReports(stream orchestrator.HostAgentService_ConnectAgentStreamClient) {
+	journalMu.Lock()
+	reports := journalReports
+	journalReports = nil
+	journalMu.Unlock()
+	for _, status := range reports {
+		sendUpdateStatus(stream, status)
+	}
+}
+
+// journaledCommand rebuilds the parts of the command an entry was written for that statuses need.
+func journaledCommand(e *journalEntry) *orchestrator.UpdateContainerCommand {
+	return &orchestrator.UpdateContainerCommand{
+		CommandId:     e.CommandID,
+		ContainerUID:  e.Original.ID,
+		ContainerName: e.ContainerName,
+		Image:         e.Image,
+		Digest:        e.Digest,
+		AgentId:       AgentID(),
+	}
+}
+
+// completeJournaled finishes an update that succeeded before the agent stopped: the new
+// container gets the original name and the original is removed. Should the new container be
+// gone, the original is restored instead.
+func completeJournaled(cli *dockerclient.Client, ctx context.Context, e *journalEntry) (*orchestrator.UpdateStatus, error) {
+	update := journaledCommand(e)
+	newInspect, err := cli.ContainerInspect(ctx, e.NewContainerID)
+	if errdefs.IsNotFound(err) {
+		return restoreJournaled(cli, ctx, e)
+	}
+	if err != nil {
+		return newStatus(update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Agent restarted after updating %s; inspecting the new container failed: %v", e.ContainerName, err)), err
+	}
+	if strings.TrimPrefix(newInspect.Name, "/") != e.ContainerName {
+		if err := cli.ContainerRename(ctx, newInspect.ID, e.ContainerName); err != nil {
+			return newStatus(update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Agent restarted after updating %s; renaming the new container failed: %v", e.ContainerName, err)), err
+		}
+	}
+	if !newInspect.State.Running {
+		if err := cli.ContainerStart(ctx, newInspect.ID, container.StartOptions{}); err != nil {
+			return newStatus(update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Agent restarted after updating %s; starting the new container failed: %v", e.ContainerName, err)), err
+		}
+	}
+	if err := cli.ContainerRemove(ctx, e.Original.ID, container.RemoveOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+		log.Printf("Remove old container %s failed: %v", e.Original.ID, err)
+	}
+
+	status := newStatus(update, orchestrator.UpdateStatus_COMPLETED, fmt.Sprintf("Agent restarted while finishing the update; completed it. New ID: %s", newInspect.ID))
+	status.PreviousImage = e.Original.Config.Image
+	status.PreviousDigest = repoDigest(cli, ctx, e.Original.Image, e.Original.Config.Image)
+	status.NewContainerUid = newInspect.ID
+	status.ImageDigest = repoDigest(cli, ctx, newInspect.Image, e.Image)
+	return status, nil
+}
+
+// restoreJournaled undoes an update interrupted before it succeeded: the new container is
+// removed and the original gets its name back and is started if it was running. An original
+// that no longer exists is recreated from its inspect data.
+func restoreJournaled(cli *dockerclient.Client, ctx context.Context, e *journalEntry) (*orchestrator.UpdateStatus, error) {
+	update := journaledCommand(e)
+	failed := func(format string, err error) (*orchestrator.UpdateStatus, error) {
+		msg := fmt.Sprintf("Agent restarted during the update (step %s); "+format, e.Step, err)
+		return newStatus(update, orchestrator.UpdateStatus_FAILED, msg), errors.New(msg)
+	}
+
+	// The new container may exist under its ID, its journaled name, the original name or, for
+	// entries written without a name, any staging name of the container
+	refs := []string{e.NewContainerID, e.NewContainerName, e.ContainerName}
+	staging, err := stagingContainers(cli, ctx, e.ContainerName)
+	if err != nil {
+		return failed("listing staging containers failed: %v", err)
+	}
+	refs = append(refs, staging...)
+	removed := map[string]bool{}
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+		inspect, err := cli.ContainerInspect(ctx, ref)
+		if err != nil || inspect.ID == e.Original.ID || removed[inspect.ID] {
+			continue
+		}
+		removed[inspect.ID] = true
+		log.Printf("Replay: removing new container %s of %s", inspect.ID, e.ContainerName)
+		if err := cli.ContainerRemove(ctx, inspect.ID, container.RemoveOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+			return failed("removing the new container failed: %v", err)
+		}
+	}
+
+	original, err := cli.ContainerInspect(ctx, e.Original.ID)
+	switch {
+	case errdefs.IsNotFound(err):
+		id, err := recreateOriginal(cli, ctx, e)
+		if err != nil {
+			return failed("recreating the original container failed: %v", err)
+		}
+		original, err = cli.ContainerInspect(ctx, id)
+		if err != nil {
+			return failed("inspecting the recreated container failed: %v", err)
+		}
+	case err != nil:
+		return failed("inspecting the original container failed: %v", err)
+	case strings.TrimPrefix(original.Name, "/") != e.ContainerName:
+		if err := cli.ContainerRename(ctx, original.ID, e.ContainerName); err != nil {
+			return failed("renaming the original container back failed: %v", err)
+		}
+	}
+	if e.Original.State != nil && e.Original.State.Running && !original.State.Running {
+		if err := cli.ContainerStart(ctx, original.ID, container.StartOptions{}); err != nil {
+			return failed("starting the original container failed: %v", err)
+		}
+	}
+	return newStatus(update, orchestrator.UpdateStatus_ROLLBACK, fmt.Sprintf("Agent restarted during the update (step %s); original container restored", e.Step)), nil
+}
+
+// stagingContainers returns the IDs of the containers named as staging containers of name.
+func stagingContainers(cli *dockerclient.Client, ctx context.Context, name string) ([]string, error) {
+	prefix := name + stagingSuffix
+	list, err := cli.ContainerList(ctx, container.ListOptions{All: true, Filters: filters.NewArgs(filters.Arg("name", prefix))})
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, c := range list {
+		for _, n := range c.Names {
+			// The name filter matches substrings, e.g. the staging containers of "x-web" for "web"
+			if strings.HasPrefix(strings.TrimPrefix(n, "/"), prefix) {
+				ids = append(ids, c.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// recreateOriginal creates the original container again from its journaled inspect data, on the
+// image it ran. The image name is pointed back at that image if it was moved to the new one.
+func recreateOriginal(cli *dockerclient.Client, ctx context.Context, e *journalEntry) (string, error) {
+	ref := e.Original.Config.Image
+	if img, err := cli.ImageInspect(ctx, ref); err != nil || img.ID != e.Original.Image {
+		if err := cli.ImageTag(ctx, e.Original.Image, ref); err != nil {
+			return "", fmt.Errorf("tag original image %s as %s: %w", e.Original.Image, ref, err)
+		}
+	}
+	config := *e.Original.Config
+	hostConfig := *e.Original.HostConfig
+	var networkConfig *network.NetworkingConfig
+	if e.Original.NetworkSettings != nil {
+		networkConfig = prepareNetworkConfig(&e.Original, &hostConfig)
+	}
+	resp, err := cli.ContainerCreate(ctx, &config, &hostConfig, networkConfig, nil, e.ContainerName)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Replay: recreated original container %s as %s", e.ContainerName, resp.ID)
+	return resp.ID, nil
+}
@@ -0,0 +1,285 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// minFreeDisk is the least free space the Docker root must have left for the new container.
+const minFreeDisk = 1 << 30
+
+// preflight checks, after the pull and before the old container is touched, that the new
+// container can be created and started: enough disk, existing bind mount sources and networks,
+// free host ports and an image built for the host.
+func preflight(cli *dockerclient.Client, ctx context.Context, update *orchestrator.UpdateContainerCommand, inspect *container.InspectResponse) []*orchestrator.PreflightCheck {
+	config, hostConfig, networkConfig := newContainerConfig(update, strings.TrimPrefix(inspect.Name, "/"), inspect)
+	info, infoErr := cli.Info(ctx)
+	img, imgErr := cli.ImageInspect(ctx, config.Image)
+
+	checks := []*orchestrator.PreflightCheck{
+		checkDisk(info, infoErr, img, imgErr),
+		checkMounts(hostConfig),
+		checkNetworks(cli, ctx, hostConfig, networkConfig),
+		checkPorts(cli, ctx, inspect, hostConfig),
+		checkPlatform(info, infoErr, img, imgErr),
+	}
+	for _, c := range checks {
+		log.Printf("Pre-flight %s for %s: passed=%t %s", c.Name, inspect.Name, c.Passed, c.Message)
+	}
+	return checks
+}
+
+// runPreflight runs the pre-flight checks and reports their results in a PREFLIGHT status, or
+// in a FAILED one when a check failed.
+func runPreflight(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, inspect *container.InspectResponse) error {
+	checks := preflight(cli, ctx, update, inspect)
+	failures := failedChecks(checks)
+	if failures != "" && ctx.Err() != nil {
+		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, "Pre-flight checks aborted: "+failures)
+		return ctx.Err()
+	}
+	status := newStatus(update, orchestrator.UpdateStatus_PREFLIGHT, "Pre-flight checks passed")
+	if failures != "" {
+		status = newStatus(update, orchestrator.UpdateStatus_FAILED, "Pre-flight check failed: "+failures)
+	}
+	status.Preflight = checks
+	sendUpdateStatus(stream, status)
+	if failures != "" {
+		return fmt.Errorf("pre-flight check failed: %s", failures)
+	}
+	return nil
+}
+
+// failedChecks describes the checks that failed, or returns "" when all passed.
+func failedChecks(checks []*orchestrator.PreflightCheck) string {
+	var failed []string
+	for _, c := range checks {
+		if !c.Passed {
+			failed = append(failed, c.Name+": "+c.Message)
+		}
+	}
+	return strings.Join(failed, "; ")
+}
+
+func checkPassed(name, format string, args ...any) *orchestrator.PreflightCheck {
+	return &orchestrator.PreflightCheck{Name: name, Passed: true, Message: fmt.Sprintf(format, args...)}
+}
+
+func checkFailed(name, format string, args ...any) *orchestrator.PreflightCheck {
+	return &orchestrator.PreflightCheck{Name: name, Passed: false, Message: fmt.Sprintf(format, args...)}
+}
+
+// checkDisk requires free space on the Docker root for the new container's writable layer:
+// the size of the new image, and at least minFreeDisk. A root the agent can't see is not checked.
+func checkDisk(info system.Info, infoErr error, img image.InspectResponse, imgErr error) *orchestrator.PreflightCheck {
+	if infoErr != nil {
+		return checkFailed("disk", "Docker info unavailable: %v", infoErr)
+	}
+	if imgErr != nil {
+		return checkFailed("disk", "new image unavailable: %v", imgErr)
+	}
+	free, err := freeDiskSpace(info.DockerRootDir)
+	if err != nil {
+		return checkPassed("disk", "free space on %s not checked: %v", info.DockerRootDir, err)
+	}
+	need := uint64(max(img.Size, minFreeDisk))
+	if free < need {
+		return checkFailed("disk", "%d MiB free on %s, %d MiB needed", free>>20, info.DockerRootDir, need>>20)
+	}
+	return checkPassed("disk", "%d MiB free on %s", free>>20, info.DockerRootDir)
+}
+
+// checkMounts requires the sources of bind mounts to exist on the host.
+func checkMounts(hostConfig *container.HostConfig) *orchestrator.PreflightCheck {
+	var sources []string
+	for _, bind := range hostConfig.Binds {
+		// Named volumes are created by Docker when missing
+		if src, _, ok := strings.Cut(bind, ":"); ok && filepath.IsAbs(src) {
+			sources = append(sources, src)
+		}
+	}
+	for _, m := range hostConfig.Mounts {
+		if m.Type == mount.TypeBind {
+			sources = append(sources, m.Source)
+		}
+	}
+	for _, src := range sources {
+		if _, err := os.Stat(src); err != nil {
+			return checkFailed("mounts", "bind mount source %s: %v", src, err)
+		}
+	}
+	return checkPassed("mounts", "%d bind mount source(s) present", len(sources))
+}
+
+// checkNetworks requires the networks the new container joins to exist, and the container
+// whose network it shares to be running.
+func checkNetworks(cli *dockerclient.Client, ctx context.Context, hostConfig *container.HostConfig, networkConfig *network.NetworkingConfig) *orchestrator.PreflightCheck {
+	if hostConfig.NetworkMode.IsContainer() {
+		name := hostConfig.NetworkMode.ConnectedContainer()
+		c, err := cli.ContainerInspect(ctx, name)
+		if err != nil {
+			return checkFailed("networks", "container %s whose network is shared: %v", name, err)
+		}
+		if c.State == nil || !c.State.Running {
+			return checkFailed("networks", "container %s whose network is shared is not running", name)
+		}
+		return checkPassed("networks", "shares the network of %s", name)
+	}
+
+	names := map[string]bool{}
+	if hostConfig.NetworkMode.IsUserDefined() {
+		names[string(hostConfig.NetworkMode)] = true
+	}
+	if networkConfig != nil {
+		for name := range networkConfig.EndpointsConfig {
+			if container.NetworkMode(name).IsUserDefined() {
+				names[name] = true
+			}
+		}
+	}
+	for name := range names {
+		if _, err := cli.NetworkInspect(ctx, name, network.InspectOptions{}); err != nil {
+			return checkFailed("networks", "network %s: %v", name, err)
+		}
+	}
+	return checkPassed("networks", "%d network(s) present", len(names))
+}
+
+// checkPorts requires the host ports the new container publishes to be free. Ports the old
+// container publishes are freed when it is stopped; start-first is never used for containers
+// that publish host ports.
+func checkPorts(cli *dockerclient.Client, ctx context.Context, inspect *container.InspectResponse, hostConfig *container.HostConfig) *orchestrator.PreflightCheck {
+	own := map[string]bool{}
+	if inspect.HostConfig != nil {
+		for port, bindings := range inspect.HostConfig.PortBindings {
+			for _, b := range bindings {
+				for _, p := range hostPorts(b.HostPort) {
+					own[port.Proto()+"/"+strconv.Itoa(p)] = true
+				}
+			}
+		}
+	}
+	others := map[string]string{}
+	running, err := cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return checkFailed("ports", "list containers: %v", err)
+	}
+	for _, c := range running {
+		if c.ID == inspect.ID {
+			continue
+		}
+		for _, p := range c.Ports {
+			if p.PublicPort != 0 {
+				others[p.Type+"/"+strconv.Itoa(int(p.PublicPort))] = strings.TrimPrefix(firstName(c.Names), "/")
+			}
+		}
+	}
+
+	checked := 0
+	for port, bindings := range hostConfig.PortBindings {
+		for _, b := range bindings {
+			for _, p := range hostPorts(b.HostPort) {
+				key := port.Proto() + "/" + strconv.Itoa(p)
+				if own[key] {
+					continue
+				}
+				if name, ok := others[key]; ok {
+					return checkFailed("ports", "host port %s is published by %s", key, name)
+				}
+				if err := probePort(port.Proto(), b.HostIP, p); err != nil {
+					return checkFailed("ports", "host port %s is in use: %v", key, err)
+				}
+				checked++
+			}
+		}
+	}
+	return checkPassed("ports", "%d new host port(s) free", checked)
+}
+
+// hostPorts expands a published host port or range; an empty one lets Docker pick a free port.
+func hostPorts(hostPort string) []int {
+	if hostPort == "" {
+		return nil
+	}
+	start, end, err := nat.ParsePortRangeToInt(hostPort)
+	if err != nil {
+		return nil
+	}
+	ports := make([]int, 0, end-start+1)
+	for p := start; p <= end; p++ {
+		ports = append(ports, p)
+	}
+	return ports
+}
+
+// probePort binds the port briefly to find out whether something else holds it.
+func probePort(proto, hostIP string, port int) error {
+	addr := net.JoinHostPort(hostIP, strconv.Itoa(port))
+	switch proto {
+	case "udp":
+		conn, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case "tcp":
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		return l.Close()
+	}
+	return nil // sctp can't be probed from Go
+}
+
+func firstName(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
+
+// checkPlatform requires the new image to be built for the OS and architecture of the host.
+func checkPlatform(info system.Info, infoErr error, img image.InspectResponse, imgErr error) *orchestrator.PreflightCheck {
+	if infoErr != nil {
+		return checkFailed("platform", "Docker info unavailable: %v", infoErr)
+	}
+	if imgErr != nil {
+		return checkFailed("platform", "new image unavailable: %v", imgErr)
+	}
+	hostArch := dockerArch(info.Architecture)
+	if img.Os != info.OSType || img.Architecture != hostArch {
+		return checkFailed("platform", "image is %s/%s, host is %s/%s", img.Os, img.Architecture, info.OSType, hostArch)
+	}
+	return checkPassed("platform", "%s/%s", img.Os, img.Architecture)
+}
+
+// dockerArch turns the kernel architecture Docker reports, e.g. x86_64, into the name images use.
+func dockerArch(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64":
+		return "arm64"
+	case "armv7l", "armv6l":
+		return "arm"
+	case "i386", "i686":
+		return "386"
+	}
+	return arch
+}
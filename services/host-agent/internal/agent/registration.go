@@ -4,111 +4,32 @@ package agent
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net"
 	"os"
-	"strconv"
-	"strings"
 
 	host_agent "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
-	"github.com/docker/docker/api/types/container"
 	dockerclient "github.com/docker/docker/client"
 )
 
+// RegisterAgent registers the host and its containers with the orchestrator. A rejected
+// registration is an error, so the connection is retried.
 func RegisterAgent(cli *dockerclient.Client, ctx context.Context, gRPCClient host_agent.HostAgentServiceClient) error {
-	// List all containers on the host
-	containersList, err := cli.ContainerList(ctx, container.ListOptions{
-		All: true, // include stopped containers if you want
-	})
+	heartbeatMu.Lock()
+	defer heartbeatMu.Unlock()
+	ackedHash = ""
+
+	// Inspect all containers on the host
+	containers, err := resyncInventory(cli, ctx)
 	if err != nil {
-		log.Printf("Failed listing containers: %v", err)
 		return err
 	}
 
-	var containers []*host_agent.ContainerInfo
-
-	// Iterate through each container and inspect it for full details
-	for _, c := range containersList {
-		inspect, err := cli.ContainerInspect(ctx, c.ID)
-		if err != nil {
-			log.Printf("Inspect failed for container %s: %v", c.ID, err)
-			continue
-		}
-
-		// Ports -> structured PortMapping
-		var ports []*host_agent.PortMapping
-		if inspect.NetworkSettings != nil {
-			for containerPortProto, bindings := range inspect.NetworkSettings.Ports {
-				// containerPortProto looks like "80/tcp"
-				parts := strings.Split(string(containerPortProto), "/")
-				if len(parts) != 2 {
-					continue
-				}
-				containerPort, err := strconv.Atoi(parts[0])
-				if err != nil {
-					continue
-				}
-				protocol := parts[1]
-
-				// If no bindings, it's exposed internally only
-				if len(bindings) == 0 {
-					ports = append(ports, &host_agent.PortMapping{
-						HostIp:        "",
-						HostPort:      0,
-						ContainerPort: uint32(containerPort),
-						Protocol:      protocol,
-					})
-					continue
-				}
-
-				for _, b := range bindings {
-					hostIP := b.HostIP
-					if hostIP == "" {
-						hostIP = "0.0.0.0"
-					}
-					hostPort, _ := strconv.Atoi(b.HostPort)
-
-					ports = append(ports, &host_agent.PortMapping{
-						HostIp:        hostIP,
-						HostPort:      uint32(hostPort),
-						ContainerPort: uint32(containerPort),
-						Protocol:      protocol,
-					})
-				}
-			}
-		}
-
-		// Env vars
-		var envVars []string
-		if inspect.Config != nil {
-			envVars = append(envVars, inspect.Config.Env...)
-		}
-
-		// Volumes (mount sources)
-		var volumes []string
-		for _, m := range inspect.Mounts {
-			volumes = append(volumes, m.Source)
-		}
-
-		// Build our container info
-		cInfo := host_agent.ContainerInfo{
-			ContainerID: c.ID,
-			Name:        strings.TrimPrefix(inspect.Name, "/"),
-			Image:       inspect.Config.Image,
-			Ports:       ports,
-			EnvVars:     envVars,
-			Volumes:     volumes,
-			Network:     string(inspect.HostConfig.NetworkMode),
-		}
-
-		containers = append(containers, &cInfo)
-	}
-
 	// Get host information
-	hostID, err := GetMACAddress()
+	mac, err := GetMACAddress()
 	if err != nil {
 		log.Printf("MAC address lookup failed: %v", err)
-		return err
 	}
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -121,10 +42,13 @@ func RegisterAgent(cli *dockerclient.Client, ctx context.Context, gRPCClient hos
 		return err
 	}
 	hostInfo := &host_agent.HostInfo{
-		MacAddress: hostID,
+		AgentId:    AgentID(),
+		MacAddress: mac,
 		Hostname:   hostname,
 		IpAddress:  ip,
 		Containers: containers,
+		// Heartbeats send the changes to this inventory once it is acknowledged.
+		InventoryHash: inventoryHash(containers),
 	}
 
 	// Pretty print the host info for now
@@ -138,22 +62,28 @@ func RegisterAgent(cli *dockerclient.Client, ctx context.Context, gRPCClient hos
 		log.Printf("Host registration RPC failed: %v", err)
 		return err
 	}
-	if res.Success {
-		log.Printf("Host registered: %s", res.Message)
-	} else {
+	if !res.Success {
 		log.Printf("Host registration rejected: %s", res.Message)
+		return fmt.Errorf("registration rejected: %s", res.Message)
 	}
+	ackedHash = hostInfo.InventoryHash
+	log.Printf("Host registered: %s", res.Message)
 	return nil
 }
 
+// GetMACAddress returns the MAC address of the first interface that is up and not a loopback.
+// It is reported as an attribute of the host; the host is identified by AgentID.
 func GetMACAddress() (string, error) {
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		return "", err
 	}
 	for _, iface := range ifaces {
-		if iface.Flags&net.FlagLoopback == 0 && iface.HardwareAddr != nil {
-			return iface.HardwareAddr.String(), nil
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+		if mac := iface.HardwareAddr.String(); mac != "" {
+			return mac, nil
 		}
 	}
 	return "", nil
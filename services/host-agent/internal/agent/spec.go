@@ -0,0 +1,382 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+// containerSpec captures what is needed to recreate a container with another image. Settings
+// the container inherited from its image (entrypoint, command, user, working directory,
+// healthcheck, env vars and labels) are left out so the new image's defaults apply to them.
+func containerSpec(cli *dockerclient.Client, ctx context.Context, inspect *container.InspectResponse) *orchestrator.ContainerSpec {
+	cfg, hc := inspect.Config, inspect.HostConfig
+	if cfg == nil || hc == nil {
+		return nil
+	}
+	imageCfg := &container.Config{}
+	if img, err := cli.ImageInspect(ctx, inspect.Image); err != nil {
+		log.Printf("Inspect image %s failed: %v", inspect.Image, err)
+	} else if img.Config != nil {
+		imageCfg = &container.Config{
+			Env:         img.Config.Env,
+			Labels:      img.Config.Labels,
+			Entrypoint:  img.Config.Entrypoint,
+			Cmd:         img.Config.Cmd,
+			User:        img.Config.User,
+			WorkingDir:  img.Config.WorkingDir,
+			Healthcheck: img.Config.Healthcheck,
+		}
+	}
+
+	spec := &orchestrator.ContainerSpec{
+		Env:         subtract(cfg.Env, imageCfg.Env),
+		Ports:       specPorts(cfg.ExposedPorts, hc.PortBindings),
+		Labels:      map[string]string{},
+		CapAdd:      hc.CapAdd,
+		CapDrop:     hc.CapDrop,
+		NetworkMode: string(hc.NetworkMode),
+		Privileged:  hc.Privileged,
+		RestartPolicy: &orchestrator.RestartPolicy{
+			Name:              string(hc.RestartPolicy.Name),
+			MaximumRetryCount: int32(hc.RestartPolicy.MaximumRetryCount),
+		},
+		Resources: &orchestrator.Resources{
+			Memory:     hc.Memory,
+			MemorySwap: hc.MemorySwap,
+			NanoCpus:   hc.NanoCPUs,
+			CpuShares:  hc.CPUShares,
+			CpusetCpus: hc.CpusetCpus,
+		},
+	}
+	if hc.PidsLimit != nil {
+		spec.Resources.PidsLimit = *hc.PidsLimit
+	}
+	for k, v := range cfg.Labels {
+		if imageValue, ok := imageCfg.Labels[k]; !ok || imageValue != v {
+			spec.Labels[k] = v
+		}
+	}
+	if !slices.Equal(cfg.Entrypoint, imageCfg.Entrypoint) {
+		spec.Entrypoint = cfg.Entrypoint
+	}
+	if !slices.Equal(cfg.Cmd, imageCfg.Cmd) {
+		spec.Cmd = cfg.Cmd
+	}
+	if cfg.User != imageCfg.User {
+		spec.User = cfg.User
+	}
+	if cfg.WorkingDir != imageCfg.WorkingDir {
+		spec.WorkingDir = cfg.WorkingDir
+	}
+	if cfg.Hostname != "" && !strings.HasPrefix(inspect.ID, cfg.Hostname) {
+		spec.Hostname = cfg.Hostname // set explicitly rather than defaulted to the container ID
+	}
+	if h := cfg.Healthcheck; h != nil && !sameHealthcheck(h, imageCfg.Healthcheck) {
+		spec.Healthcheck = &orchestrator.Healthcheck{
+			Test:          h.Test,
+			IntervalNs:    int64(h.Interval),
+			TimeoutNs:     int64(h.Timeout),
+			StartPeriodNs: int64(h.StartPeriod),
+			Retries:       int32(h.Retries),
+		}
+	}
+	for _, d := range hc.Devices {
+		spec.Devices = append(spec.Devices, &orchestrator.DeviceMapping{
+			PathOnHost:        d.PathOnHost,
+			PathInContainer:   d.PathInContainer,
+			CgroupPermissions: d.CgroupPermissions,
+		})
+	}
+	// Tmpfs and volume options are only kept in the mounts the container was created with
+	declared := make(map[string]mount.Mount, len(hc.Mounts))
+	for _, m := range hc.Mounts {
+		declared[m.Target] = m
+	}
+	for _, m := range inspect.Mounts {
+		source := m.Source
+		if m.Type == mount.TypeVolume {
+			source = m.Name // keeps named and anonymous volumes with their data
+		}
+		spec.Mounts = append(spec.Mounts, &orchestrator.MountSpec{
+			Type:        string(m.Type),
+			Source:      source,
+			Target:      m.Destination,
+			ReadOnly:    !m.RW,
+			Mode:        m.Mode,
+			Propagation: string(m.Propagation),
+			Tmpfs:       tmpfsSpec(declared[m.Destination].TmpfsOptions),
+			Volume:      volumeSpec(declared[m.Destination].VolumeOptions),
+		})
+	}
+	if inspect.NetworkSettings != nil {
+		names := make([]string, 0, len(inspect.NetworkSettings.Networks))
+		for name := range inspect.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			settings := inspect.NetworkSettings.Networks[name]
+			attachment := &orchestrator.NetworkAttachment{Name: name, Aliases: settings.Aliases}
+			if ipam := settings.IPAMConfig; ipam != nil {
+				attachment.Ipv4Address, attachment.Ipv6Address = ipam.IPv4Address, ipam.IPv6Address
+			}
+			spec.Networks = append(spec.Networks, attachment)
+		}
+	}
+	return spec
+}
+
+// specPorts lists the exposed ports of a container with their host bindings.
+func specPorts(exposed nat.PortSet, bindings nat.PortMap) []*orchestrator.PortMapping {
+	ports := make(map[nat.Port]bool, len(exposed)+len(bindings))
+	for p := range exposed {
+		ports[p] = true
+	}
+	for p := range bindings {
+		ports[p] = true
+	}
+	var out []*orchestrator.PortMapping
+	for p := range ports {
+		if len(bindings[p]) == 0 {
+			out = append(out, &orchestrator.PortMapping{ContainerPort: uint32(p.Int()), Protocol: p.Proto()})
+			continue
+		}
+		for _, b := range bindings[p] {
+			hostPort, _ := strconv.Atoi(b.HostPort)
+			out = append(out, &orchestrator.PortMapping{
+				HostIp:        b.HostIP,
+				HostPort:      uint32(hostPort),
+				ContainerPort: uint32(p.Int()),
+				Protocol:      p.Proto(),
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ContainerPort != out[j].ContainerPort {
+			return out[i].ContainerPort < out[j].ContainerPort
+		}
+		return out[i].Protocol < out[j].Protocol
+	})
+	return out
+}
+
+// applySpec sets the configuration captured in spec on copies of the old container's configs.
+// Settings without a counterpart in the spec, such as sysctls, keep their inspected values.
+func applySpec(spec *orchestrator.ContainerSpec, config *container.Config, hostConfig *container.HostConfig) {
+	config.Env = spec.Env
+	config.Labels = spec.Labels
+	config.Entrypoint = spec.Entrypoint
+	config.Cmd = spec.Cmd
+	config.User = spec.User
+	config.WorkingDir = spec.WorkingDir
+	config.Hostname = spec.Hostname
+	config.Healthcheck = nil
+	if h := spec.Healthcheck; h != nil {
+		config.Healthcheck = &container.HealthConfig{
+			Test:        h.Test,
+			Interval:    time.Duration(h.IntervalNs),
+			Timeout:     time.Duration(h.TimeoutNs),
+			StartPeriod: time.Duration(h.StartPeriodNs),
+			Retries:     int(h.Retries),
+		}
+	}
+
+	config.ExposedPorts, hostConfig.PortBindings = nat.PortSet{}, nat.PortMap{}
+	for _, p := range spec.Ports {
+		port, err := nat.NewPort(p.Protocol, strconv.FormatUint(uint64(p.ContainerPort), 10))
+		if err != nil {
+			log.Printf("Invalid port %d/%s skipped: %v", p.ContainerPort, p.Protocol, err)
+			continue
+		}
+		config.ExposedPorts[port] = struct{}{}
+		if p.HostPort == 0 && p.HostIp == "" {
+			continue // exposed only
+		}
+		binding := nat.PortBinding{HostIP: p.HostIp}
+		if p.HostPort != 0 {
+			binding.HostPort = strconv.FormatUint(uint64(p.HostPort), 10)
+		}
+		hostConfig.PortBindings[port] = append(hostConfig.PortBindings[port], binding)
+	}
+
+	hostConfig.Binds, hostConfig.Mounts = nil, nil
+	for _, m := range spec.Mounts {
+		switch mount.Type(m.Type) {
+		case mount.TypeBind:
+			hostConfig.Binds = append(hostConfig.Binds, bindString(m))
+		case mount.TypeVolume:
+			if m.Volume == nil {
+				hostConfig.Binds = append(hostConfig.Binds, bindString(m))
+				continue
+			}
+			// Binds can't carry the driver config and labels of a volume
+			hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+				Type:          mount.TypeVolume,
+				Source:        m.Source,
+				Target:        m.Target,
+				ReadOnly:      m.ReadOnly,
+				VolumeOptions: volumeOptions(m.Volume),
+			})
+		default:
+			hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+				Type:         mount.Type(m.Type),
+				Source:       m.Source,
+				Target:       m.Target,
+				ReadOnly:     m.ReadOnly,
+				TmpfsOptions: tmpfsOptions(m.Tmpfs),
+			})
+		}
+	}
+
+	if rp := spec.RestartPolicy; rp != nil {
+		hostConfig.RestartPolicy = container.RestartPolicy{
+			Name:              container.RestartPolicyMode(rp.Name),
+			MaximumRetryCount: int(rp.MaximumRetryCount),
+		}
+	}
+	if r := spec.Resources; r != nil {
+		hostConfig.Memory = r.Memory
+		hostConfig.MemorySwap = r.MemorySwap
+		hostConfig.NanoCPUs = r.NanoCpus
+		hostConfig.CPUShares = r.CpuShares
+		hostConfig.CpusetCpus = r.CpusetCpus
+		hostConfig.PidsLimit = nil
+		if r.PidsLimit != 0 {
+			limit := r.PidsLimit
+			hostConfig.PidsLimit = &limit
+		}
+	}
+	hostConfig.CapAdd = spec.CapAdd
+	hostConfig.CapDrop = spec.CapDrop
+	hostConfig.Devices = nil
+	for _, d := range spec.Devices {
+		hostConfig.Devices = append(hostConfig.Devices, container.DeviceMapping{
+			PathOnHost:        d.PathOnHost,
+			PathInContainer:   d.PathInContainer,
+			CgroupPermissions: d.CgroupPermissions,
+		})
+	}
+	hostConfig.NetworkMode = container.NetworkMode(spec.NetworkMode)
+	hostConfig.Privileged = spec.Privileged
+}
+
+// tmpfsSpec captures the size, mode and mount options of a tmpfs mount.
+func tmpfsSpec(o *mount.TmpfsOptions) *orchestrator.TmpfsOptions {
+	if o == nil {
+		return nil
+	}
+	spec := &orchestrator.TmpfsOptions{SizeBytes: o.SizeBytes, Mode: uint32(o.Mode)}
+	for _, opt := range o.Options {
+		spec.Options = append(spec.Options, strings.Join(opt, "="))
+	}
+	return spec
+}
+
+func tmpfsOptions(spec *orchestrator.TmpfsOptions) *mount.TmpfsOptions {
+	if spec == nil {
+		return nil
+	}
+	o := &mount.TmpfsOptions{SizeBytes: spec.SizeBytes, Mode: os.FileMode(spec.Mode)}
+	for _, opt := range spec.Options {
+		key, value, ok := strings.Cut(opt, "=")
+		if ok {
+			o.Options = append(o.Options, []string{key, value})
+		} else {
+			o.Options = append(o.Options, []string{key})
+		}
+	}
+	return o
+}
+
+// volumeSpec captures the options a volume mount was declared with, including the driver
+// config used when the volume is created.
+func volumeSpec(o *mount.VolumeOptions) *orchestrator.VolumeOptions {
+	if o == nil {
+		return nil
+	}
+	spec := &orchestrator.VolumeOptions{NoCopy: o.NoCopy, Labels: o.Labels, Subpath: o.Subpath}
+	if d := o.DriverConfig; d != nil {
+		spec.Driver = d.Name
+		spec.DriverOptions = d.Options
+	}
+	return spec
+}
+
+func volumeOptions(spec *orchestrator.VolumeOptions) *mount.VolumeOptions {
+	o := &mount.VolumeOptions{NoCopy: spec.NoCopy, Labels: spec.Labels, Subpath: spec.Subpath}
+	if spec.Driver != "" || len(spec.DriverOptions) > 0 {
+		o.DriverConfig = &mount.Driver{Name: spec.Driver, Options: spec.DriverOptions}
+	}
+	return o
+}
+
+// bindString renders a bind or volume mount in the "source:target:options" form of Binds,
+// which, unlike Mounts, creates missing host directories like the original "docker run -v" did.
+func bindString(m *orchestrator.MountSpec) string {
+	var opts []string
+	if m.ReadOnly {
+		opts = append(opts, "ro")
+	}
+	for _, o := range strings.Split(m.Mode, ",") {
+		if o != "" && o != "ro" && o != "rw" {
+			opts = append(opts, o)
+		}
+	}
+	if mount.Type(m.Type) == mount.TypeBind && m.Propagation != "" && m.Propagation != string(mount.PropagationRPrivate) {
+		opts = append(opts, m.Propagation)
+	}
+	s := m.Source + ":" + m.Target
+	if len(opts) > 0 {
+		s += ":" + strings.Join(opts, ",")
+	}
+	return s
+}
+
+// specNetworkConfig attaches the new container to the networks of the spec with their
+// aliases and static addresses.
+func specNetworkConfig(spec *orchestrator.ContainerSpec, hostConfig *container.HostConfig) *network.NetworkingConfig {
+	if len(spec.Networks) == 0 || hostConfig.NetworkMode.IsHost() || hostConfig.NetworkMode.IsNone() || hostConfig.NetworkMode.IsContainer() {
+		return nil
+	}
+	endpoints := make(map[string]*network.EndpointSettings, len(spec.Networks))
+	for _, n := range spec.Networks {
+		endpoint := &network.EndpointSettings{Aliases: n.Aliases}
+		if n.Ipv4Address != "" || n.Ipv6Address != "" {
+			endpoint.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: n.Ipv4Address, IPv6Address: n.Ipv6Address}
+		}
+		endpoints[n.Name] = endpoint
+	}
+	return &network.NetworkingConfig{EndpointsConfig: endpoints}
+}
+
+// subtract returns the entries of list that are not in base.
+func subtract(list, base []string) []string {
+	var out []string
+	for _, s := range list {
+		if !slices.Contains(base, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func sameHealthcheck(a, b *container.HealthConfig) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return slices.Equal(a.Test, b.Test) && a.Interval == b.Interval && a.Timeout == b.Timeout &&
+		a.StartPeriod == b.StartPeriod && a.Retries == b.Retries
+}
@@ -7,13 +7,16 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	dockerclient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -23,6 +26,12 @@ func UpdateContainer(cli *dockerclient.Client, ctx context.Context, update *orch
 
 	// 1. Inspect the existing container to get its configuration
 	inspect, err := cli.ContainerInspect(ctx, update.ContainerUID)
+	if errdefs.IsNotFound(err) && update.ContainerName != "" {
+		// Replaced by an update that ran while this one was queued
+		if inspect, err = cli.ContainerInspect(ctx, update.ContainerName); err == nil {
+			update.ContainerUID = inspect.ID
+		}
+	}
 	if err != nil {
 		log.Printf("Inspect failed for %s: %v", update.ContainerUID, err)
 		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Container not found: %v", err))
@@ -30,17 +39,25 @@ func UpdateContainer(cli *dockerclient.Client, ctx context.Context, update *orch
 	}
 
 	originalConfig := inspect.Config
-	originalHostConfig := inspect.HostConfig
 	originalName := strings.TrimPrefix(inspect.Name, "/")
 	originalImage := inspect.Config.Image
+	originalDigest := repoDigest(cli, ctx, inspect.Image, originalImage)
 
-	// Defer the rollback function to execute if any subsequent step fails
+	// Defer the rollback function to execute if any subsequent step fails. The journal written
+	// before each destructive step is kept when the original couldn't be restored, so the next
+	// start of the agent tries again.
 	var newContainerID string
+	renamed := false
 	rollbackNeeded := true
+	journal := newJournalEntry(update, &inspect)
 	defer func() {
 		if rollbackNeeded {
-			rollbackChanges(cli, ctx, stream, update, originalConfig, originalHostConfig, originalName, newContainerID)
+			// A cancelled update still has to restore the original container
+			if !rollbackChanges(cli, context.WithoutCancel(ctx), stream, update, originalName, newContainerID, renamed) {
+				return
+			}
 		}
+		journal.clear()
 	}()
 
 	// 2. Pull the new Docker image
@@ -48,40 +65,146 @@ func UpdateContainer(cli *dockerclient.Client, ctx context.Context, update *orch
 		return err // Rollback will be triggered by defer
 	}
 
-	// 3. Stop and remove the old container
-	if err := stopAndRemoveContainer(cli, ctx, stream, update, update.ContainerUID); err != nil {
-		return err // Rollback will be triggered by defer
+	// Pre-flight checks catch what would make the new container fail before the old one is touched
+	if err := runPreflight(cli, ctx, stream, update, &inspect); err != nil {
+		rollbackNeeded = false // nothing was changed yet
+		return err
 	}
 
-	// 4. Create the new container
-	newContainerID, err = createNewContainer(cli, ctx, stream, update, originalName, &inspect)
-	if err != nil {
-		return err // Rollback will be triggered by defer
+	// Pre-update hooks run in the old container, which is still running
+	env := hookEnv{container: originalName, oldImage: originalImage, newImage: update.Image}
+	if _, err := runHooks(cli, ctx, stream, update, orchestrator.Hook_PRE, update.ContainerUID, env); err != nil {
+		rollbackNeeded = false // nothing was changed yet
+		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Pre-update %v", err))
+		return err
+	}
+
+	// 3. Replace the old container. Containers that can run side by side are started next to
+	// the old one, which keeps serving until the new one is healthy; others are stopped first.
+	oldName := retiredName(originalName)
+	strategy, reason := chooseStrategy(update, &inspect)
+	if strategy == orchestrator.UpdateStrategy_START_FIRST {
+		log.Printf("Starting new container for %s before stopping the old one", originalName)
+		journal.NewContainerName = stagingName(originalName)
+		if err := recordStep(stream, update, journal, stepCreateStaging); err != nil {
+			return err // Rollback will be triggered by defer
+		}
+		newContainerID, err = createNewContainer(cli, ctx, stream, update, journal.NewContainerName, &inspect)
+		if err != nil {
+			return err // Rollback will be triggered by defer
+		}
+	} else {
+		log.Printf("Stopping %s before starting the new container: %s", originalName, reason)
+		if err := recordStep(stream, update, journal, stepStopOriginal); err != nil {
+			return err // Rollback will be triggered by defer
+		}
+		if err := stopAndRenameContainer(cli, ctx, stream, update, update.ContainerUID, oldName, stopTimeout); err != nil {
+			return err // Rollback will be triggered by defer
+		}
+		renamed = true
+
+		// 4. Create the new container
+		journal.NewContainerName = originalName
+		if err := recordStep(stream, update, journal, stepCreate); err != nil {
+			return err // Rollback will be triggered by defer
+		}
+		newContainerID, err = createNewContainer(cli, ctx, stream, update, originalName, &inspect)
+		if err != nil {
+			return err // Rollback will be triggered by defer
+		}
 	}
 
 	// 5. Start the new container
+	journal.NewContainerID = newContainerID
+	if err := recordStep(stream, update, journal, stepStart); err != nil {
+		return err // Rollback will be triggered by defer
+	}
 	if err := startNewContainer(cli, ctx, stream, update, newContainerID); err != nil {
 		return err // Rollback will be triggered by defer
 	}
+	if err := recordStep(stream, update, journal, stepVerify); err != nil {
+		return err // Rollback will be triggered by defer
+	}
+
+	// 6. Wait for the new container to become healthy
+	if err := waitHealthy(cli, ctx, stream, update, newContainerID); err != nil {
+		log.Printf("Health check failed for container %s: %v", newContainerID, err)
+		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Health check failed: %v", err))
+		return err // Rollback will be triggered by defer
+	}
+
+	// Post-update hooks, often migrations, run in the new container once it passed the health
+	// gate, so a container that is rolled back for failing it never ran them
+	if onFailure, err := runHooks(cli, ctx, stream, update, orchestrator.Hook_POST, newContainerID, env); err != nil {
+		// Next to a running original the new container is simply dropped
+		if onFailure == orchestrator.Hook_ROLLBACK || strategy == orchestrator.UpdateStrategy_START_FIRST || ctx.Err() != nil {
+			sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Post-update %v", err))
+			return err // Rollback will be triggered by defer
+		}
+		// The new container is left running; the retired original would be hidden from the
+		// inventory under its temporary name, so it goes as after a successful update
+		rollbackNeeded = false
+		removeRetiredContainer(cli, ctx, update.ContainerUID)
+		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Post-update %v; new container %s left running, original container removed", err, newContainerID))
+		return err
+	}
 
-	// If all steps succeed, disable the rollback
+	// 7. Drain and stop the old container, then hand its name to the new one
+	if strategy == orchestrator.UpdateStrategy_START_FIRST {
+		if err := recordStep(stream, update, journal, stepSwap); err != nil {
+			return err // Rollback will be triggered by defer
+		}
+		if err := stopAndRenameContainer(cli, ctx, stream, update, update.ContainerUID, oldName, drainTimeout); err != nil {
+			return err // Rollback will be triggered by defer
+		}
+		renamed = true
+		if err := cli.ContainerRename(ctx, newContainerID, originalName); err != nil {
+			log.Printf("Rename failed for container %s: %v", newContainerID, err)
+			sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to rename new container: %v", err))
+			return err // Rollback will be triggered by defer
+		}
+	}
+
+	// If all steps succeed, disable the rollback and drop the old container
 	rollbackNeeded = false
+	if err := journal.record(stepCommit); err != nil {
+		log.Printf("Update journal of %s not written: %v", originalName, err)
+	}
+	removeRetiredContainer(cli, ctx, update.ContainerUID)
 
-	// 6. Send completion status and clean up the old image
+	// 8. Send completion status with the replaced version and clean up the old image
 	log.Printf("Update completed. New container ID: %s", newContainerID)
-	sendStatus(stream, update, orchestrator.UpdateStatus_COMPLETED, fmt.Sprintf("Container updated successfully. New ID: %s", newContainerID))
+	logs := fmt.Sprintf("Container updated successfully (%s). New ID: %s", strategyName(strategy), newContainerID)
+	if note := writeBackCompose(originalConfig.Labels, originalImage, update.Image); note != "" {
+		logs += ". " + note
+	}
+	status := newStatus(update, orchestrator.UpdateStatus_COMPLETED, logs)
+	status.PreviousImage = originalImage
+	status.PreviousDigest = originalDigest
+	status.NewContainerUid = newContainerID
+	status.Strategy = strategy
+	if newInspect, err := cli.ContainerInspect(ctx, newContainerID); err == nil {
+		status.ImageDigest = repoDigest(cli, ctx, newInspect.Image, update.Image)
+	}
+	sendUpdateStatus(stream, status)
 
 	go cleanupOldImage(cli, context.Background(), originalImage)
 
 	return nil
 }
 
-// rollbackChanges attempts to restore the original container if the update fails
-func rollbackChanges(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, originalConfig *container.Config, originalHostConfig *container.HostConfig, originalName, newContainerID string) {
+// rollbackChanges restores the original container if the update fails: the new container is
+// removed and the original, which was only stopped and renamed, gets its name back and is started.
+// It reports whether the original is running again.
+func rollbackChanges(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, originalName, newContainerID string, renamed bool) bool {
 	log.Printf("Starting rollback for %s", update.ContainerUID)
-	sendStatus(stream, update, orchestrator.UpdateStatus_ROLLBACK, "Update failed, attempting to roll back.")
+	if _, ok := cancelReason(update); ok {
+		sendStatus(stream, update, orchestrator.UpdateStatus_ROLLBACK, "Update cancelled, attempting to roll back.")
+	} else {
+		sendStatus(stream, update, orchestrator.UpdateStatus_ROLLBACK, "Update failed, attempting to roll back.")
+	}
 
-	// If a new container was created, try to remove it
+	// If a new container was created, remove it to free the name
 	if newContainerID != "" {
 		log.Printf("Rollback: removing failed new container %s", newContainerID)
 		if err := cli.ContainerRemove(ctx, newContainerID, container.RemoveOptions{Force: true}); err != nil {
@@ -89,68 +212,203 @@ func rollbackChanges(cli *dockerclient.Client, ctx context.Context, stream orche
 		}
 	}
 
-	// Try to recreate the original container
-	log.Printf("Rollback: re-creating original container '%s' (image %s)", originalName, originalConfig.Image)
-	resp, err := cli.ContainerCreate(ctx, originalConfig, originalHostConfig, nil, nil, originalName)
-	if err != nil {
-		log.Printf("Rollback failed: could not re-create original container: %v", err)
-		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Rollback failed: could not re-create container: %v", err))
-		return
+	// Give the original container its name back
+	if renamed {
+		log.Printf("Rollback: renaming original container %s back to '%s'", update.ContainerUID, originalName)
+		if err := cli.ContainerRename(ctx, update.ContainerUID, originalName); err != nil {
+			log.Printf("Rollback failed: could not rename original container: %v", err)
+			sendUpdateStatus(stream, newStatus(update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Rollback failed: could not rename original container: %v", err)))
+			return false
+		}
 	}
 
-	// Try to start the restored container
-	if err := cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		log.Printf("Rollback failed: could not start restored container: %v", err)
-		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Rollback failed: could not start restored container: %v", err))
-		return
+	// Start the original container again; starting a running container is a no-op
+	if err := cli.ContainerStart(ctx, update.ContainerUID, container.StartOptions{}); err != nil {
+		log.Printf("Rollback failed: could not start original container: %v", err)
+		sendUpdateStatus(stream, newStatus(update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Rollback failed: could not start original container: %v", err)))
+		return false
 	}
 
-	log.Printf("Rollback successful: restored original container (ID %s)", resp.ID)
-	sendStatus(stream, update, orchestrator.UpdateStatus_COMPLETED, "Rollback successful. Original container is running.")
+	log.Printf("Rollback successful: original container %s is running", update.ContainerUID)
+	sendStatus(stream, update, orchestrator.UpdateStatus_ROLLBACK, "Rollback successful. Original container is running.")
+	return true
+}
+
+// recordStep writes the update's journal before a destructive step. The update fails when the
+// journal can't be written, as the step couldn't be undone after a crash.
+func recordStep(stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, journal *journalEntry, step journalStep) error {
+	if err := journal.record(step); err != nil {
+		log.Printf("Update journal of %s not written: %v", journal.ContainerName, err)
+		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to write update journal: %v", err))
+		return err
+	}
+	return nil
 }
 
-// pullImage pulls the new Docker image
+// pullImage pulls the new Docker image. When the command carries a digest the image is
+// pulled by digest and tagged as update.Image so the container keeps its image name.
 func pullImage(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand) error {
 	sendStatus(stream, update, orchestrator.UpdateStatus_PULLING, "Pulling new image")
-	out, err := cli.ImagePull(ctx, update.Image, image.PullOptions{})
+	ref := update.Image
+	if update.Digest != "" {
+		ref = repository(update.Image) + "@" + update.Digest
+	}
+	out, err := cli.ImagePull(ctx, ref, image.PullOptions{})
 	if err != nil {
-		log.Printf("Pull failed for image %s: %v", update.Image, err)
+		log.Printf("Pull failed for image %s: %v", ref, err)
 		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to pull image: %v", err))
 		return err
 	}
 	defer out.Close()
 	io.Copy(io.Discard, out)
-	log.Printf("Pulled image %s", update.Image)
+	log.Printf("Pulled image %s", ref)
+
+	if ref != update.Image {
+		if err := cli.ImageTag(ctx, ref, update.Image); err != nil {
+			log.Printf("Tag %s as %s failed: %v", ref, update.Image, err)
+			sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to tag image: %v", err))
+			return err
+		}
+	}
 	return nil
 }
 
-// stopAndRemoveContainer stops and removes the specified container
-func stopAndRemoveContainer(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, containerID string) error {
+// repository strips the tag or digest from an image reference.
+func repository(ref string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	// a colon after the last slash separates the tag; earlier colons belong to a registry port
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// repoDigest returns the registry digest ("sha256:...") of a local image, preferring the one
+// that belongs to the repository of ref. It returns "" for images that were never pulled.
+func repoDigest(cli *dockerclient.Client, ctx context.Context, imageID, ref string) string {
+	img, err := cli.ImageInspect(ctx, imageID)
+	if err != nil {
+		log.Printf("Inspect image %s failed: %v", imageID, err)
+		return ""
+	}
+	digest := ""
+	for _, rd := range img.RepoDigests {
+		repo, d, ok := strings.Cut(rd, "@")
+		if !ok {
+			continue
+		}
+		if digest == "" || repo == repository(ref) {
+			digest = d
+		}
+	}
+	return digest
+}
+
+// stopAndRenameContainer stops the old container and renames it to free its name for the new one
+func stopAndRenameContainer(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, containerID, newName string, timeout int) error {
 	sendStatus(stream, update, orchestrator.UpdateStatus_STARTING, "Stopping existing container")
-	stopTimeout := 10
-	if err := cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &stopTimeout}); err != nil {
+	if err := cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout}); err != nil {
 		log.Printf("Stop failed for container %s: %v", containerID, err)
 		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to stop container: %v", err))
 		return err
 	}
 
-	log.Printf("Removing old container %s", containerID)
-	if err := cli.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: false}); err != nil {
-		log.Printf("Remove failed for container %s: %v", containerID, err)
-		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to remove container: %v", err))
+	log.Printf("Renaming old container %s to %s", containerID, newName)
+	if err := cli.ContainerRename(ctx, containerID, newName); err != nil {
+		log.Printf("Rename failed for container %s: %v", containerID, err)
+		sendStatus(stream, update, orchestrator.UpdateStatus_FAILED, fmt.Sprintf("Failed to rename container: %v", err))
 		return err
 	}
 	return nil
 }
 
+const (
+	stopTimeout  = 10 // seconds an old container gets to stop before it is replaced
+	drainTimeout = 30 // seconds an old container gets to finish its requests once its replacement runs
+)
+
+// chooseStrategy decides whether the new container can start while the old one still runs:
+// they must not compete for host ports, a network namespace, static IPs or writable volumes.
+// Otherwise the reason is returned with STOP_FIRST.
+func chooseStrategy(update *orchestrator.UpdateContainerCommand, inspect *container.InspectResponse) (orchestrator.UpdateStrategy, string) {
+	if !featureAccepted(orchestrator.Feature_FEATURE_START_FIRST) {
+		return orchestrator.UpdateStrategy_STOP_FIRST, "start-first is not accepted by the orchestrator"
+	}
+	_, hostConfig := prepareConfigs(update, inspect)
+	if hostConfig.NetworkMode.IsHost() {
+		return orchestrator.UpdateStrategy_STOP_FIRST, "uses the host network"
+	}
+	if hostConfig.NetworkMode.IsContainer() {
+		return orchestrator.UpdateStrategy_STOP_FIRST, "shares the network of another container"
+	}
+	for port, bindings := range hostConfig.PortBindings {
+		for _, b := range bindings {
+			if b.HostPort != "" {
+				return orchestrator.UpdateStrategy_STOP_FIRST, fmt.Sprintf("publishes %s on host port %s", port, b.HostPort)
+			}
+		}
+	}
+	if inspect.NetworkSettings != nil {
+		for name, settings := range inspect.NetworkSettings.Networks {
+			if ipam := settings.IPAMConfig; ipam != nil && (ipam.IPv4Address != "" || ipam.IPv6Address != "") {
+				return orchestrator.UpdateStrategy_STOP_FIRST, fmt.Sprintf("has a static IP on network %s", name)
+			}
+		}
+	}
+	for _, m := range inspect.Mounts {
+		if m.RW && (m.Type == mount.TypeBind || m.Type == mount.TypeVolume) {
+			return orchestrator.UpdateStrategy_STOP_FIRST, fmt.Sprintf("mounts %s read-write", m.Destination)
+		}
+	}
+	return orchestrator.UpdateStrategy_START_FIRST, ""
+}
+
+func strategyName(s orchestrator.UpdateStrategy) string {
+	return strings.ReplaceAll(strings.ToLower(s.String()), "_", "-")
+}
+
+const (
+	// stagingSuffix marks the name a new container runs under while the old one still has its name.
+	stagingSuffix = "-lighthouse-new-"
+	// retiredSuffix marks the name an old container is kept under while its replacement starts.
+	retiredSuffix = "-lighthouse-old-"
+)
+
+func stagingName(name string) string {
+	return fmt.Sprintf("%s%s%d", name, stagingSuffix, time.Now().Unix())
+}
+
+func retiredName(name string) string {
+	return fmt.Sprintf("%s%s%d", name, retiredSuffix, time.Now().Unix())
+}
+
+// isTransient reports whether a listed container is the old or new one of an update in
+// progress. Such containers are left out of the inventory sent to the orchestrator.
+func isTransient(names []string) bool {
+	for _, n := range names {
+		if strings.Contains(n, retiredSuffix) || strings.Contains(n, stagingSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeRetiredContainer removes the old container once its replacement is healthy.
+func removeRetiredContainer(cli *dockerclient.Client, ctx context.Context, containerID string) {
+	log.Printf("Removing old container %s", containerID)
+	if err := cli.ContainerRemove(ctx, containerID, container.RemoveOptions{}); err != nil {
+		log.Printf("Remove failed for old container %s: %v", containerID, err)
+	}
+}
+
 // createNewContainer creates a new container based on provided configurations
 func createNewContainer(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, name string, inspect *container.InspectResponse) (string, error) {
 	sendStatus(stream, update, orchestrator.UpdateStatus_RUNNING, "Creating new container")
 
 	// Prepare configurations
-	newConfig, newHostConfig := prepareConfigs(update, inspect)
-	networkConfig := prepareNetworkConfig(inspect, newHostConfig)
-
+	newConfig, newHostConfig, networkConfig := newContainerConfig(update, name, inspect)
 	resp, err := cli.ContainerCreate(ctx, newConfig, newHostConfig, networkConfig, nil, name)
 	if err != nil {
 		log.Printf("Create failed: %v", err)
@@ -161,6 +419,24 @@ func createNewContainer(cli *dockerclient.Client, ctx context.Context, stream or
 	return resp.ID, nil
 }
 
+// newContainerConfig returns the configurations of the new container, created under name.
+func newContainerConfig(update *orchestrator.UpdateContainerCommand, name string, inspect *container.InspectResponse) (*container.Config, *container.HostConfig, *network.NetworkingConfig) {
+	newConfig, newHostConfig := prepareConfigs(update, inspect)
+	networkConfig := prepareNetworkConfig(inspect, newHostConfig)
+	if update.Spec != nil {
+		networkConfig = specNetworkConfig(update.Spec, newHostConfig)
+	}
+	if original := strings.TrimPrefix(inspect.Name, "/"); name != original && networkConfig != nil {
+		// Under a staging name the container is still reachable by the original one
+		for netName, endpoint := range networkConfig.EndpointsConfig {
+			if !container.NetworkMode(netName).IsDefault() && !container.NetworkMode(netName).IsBridge() {
+				endpoint.Aliases = append(endpoint.Aliases, original)
+			}
+		}
+	}
+	return newConfig, newHostConfig, networkConfig
+}
+
 // startNewContainer starts the newly created container
 func startNewContainer(cli *dockerclient.Client, ctx context.Context, stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, containerID string) error {
 	log.Printf("Starting new container %s", containerID)
@@ -174,12 +450,19 @@ func startNewContainer(cli *dockerclient.Client, ctx context.Context, stream orc
 
 // prepareConfigs prepares container and host configurations
 func prepareConfigs(update *orchestrator.UpdateContainerCommand, inspect *container.InspectResponse) (*container.Config, *container.HostConfig) {
-	// Base configs from original container
-	newConfig := inspect.Config
-	newHostConfig := inspect.HostConfig
+	// Base configs from original container. Copy them so the originals stay intact for a rollback.
+	config := *inspect.Config
+	hostConfig := *inspect.HostConfig
+	newConfig := &config
+	newHostConfig := &hostConfig
 
-	// Apply overrides
 	newConfig.Image = update.Image
+	if update.Spec != nil {
+		applySpec(update.Spec, newConfig, newHostConfig)
+		return newConfig, newHostConfig
+	}
+
+	// Apply overrides
 	if len(update.OverrideEnvVars) > 0 {
 		newConfig.Env = update.OverrideEnvVars
 	}
@@ -245,17 +528,44 @@ func prepareNetworkConfig(inspect *container.InspectResponse, hostConfig *contai
 	return nil
 }
 
-// sendStatus is a helper to send status updates over the stream
+// sendStatus is a helper to send status updates over the stream. The failure of a step aborted
+// by a CancelUpdate is reported as the update being cancelled.
 func sendStatus(stream orchestrator.HostAgentService_ConnectAgentStreamClient, update *orchestrator.UpdateContainerCommand, stage orchestrator.UpdateStatus_Stage, logs string) {
-	status := &orchestrator.UpdateStatus{
-		ContainerUID: update.ContainerUID,
-		MacAddress:   update.MacAddress,
-		Image:        update.Image,
-		Stage:        stage,
-		Logs:         logs,
-		Timestamp:    time.Now().String(),
-	}
-	if err := stream.Send(status); err != nil {
+	if reason, ok := cancelReason(update); ok && stage == orchestrator.UpdateStatus_FAILED {
+		stage = orchestrator.UpdateStatus_CANCELLED
+		if reason != "" {
+			logs = fmt.Sprintf("Cancelled (%s): %s", reason, logs)
+		} else {
+			logs = "Cancelled: " + logs
+		}
+	}
+	sendUpdateStatus(stream, newStatus(update, stage, logs))
+}
+
+// newStatus builds the status message for a stage of an update.
+func newStatus(update *orchestrator.UpdateContainerCommand, stage orchestrator.UpdateStatus_Stage, logs string) *orchestrator.UpdateStatus {
+	return &orchestrator.UpdateStatus{
+		ContainerUID:  update.ContainerUID,
+		ContainerName: update.ContainerName,
+		AgentId:       update.AgentId,
+		Image:         update.Image,
+		Stage:         stage,
+		Logs:          logs,
+		Timestamp:     time.Now().String(),
+		TargetDigest:  update.Digest,
+		QueueDepth:    queueDepth(),
+		CommandId:     update.CommandId,
+	}
+}
+
+// sendMu serializes sends on the stream, which updates running in parallel share.
+var sendMu sync.Mutex
+
+// sendUpdateStatus sends a status message over the stream.
+func sendUpdateStatus(stream orchestrator.HostAgentService_ConnectAgentStreamClient, status *orchestrator.UpdateStatus) {
+	sendMu.Lock()
+	defer sendMu.Unlock()
+	if err := stream.Send(&orchestrator.AgentMessage{Message: &orchestrator.AgentMessage_Status{Status: status}}); err != nil {
 		log.Printf("Failed sending status update: %v", err)
 	}
 }
@@ -4,81 +4,73 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
 	dockerclient "github.com/docker/docker/client"
 )
 
-func UpdateContainerStream(cli *dockerclient.Client, ctx context.Context, gRPCClient orchestrator.HostAgentServiceClient) error {
-	// Get agent ID (MAC address)
-	agentID, err := GetMACAddress()
+// serveStream opens the command stream, registers the host and runs the commands received
+// until the stream fails. It returns when the connection was established, zero if it never
+// was, and why the stream ended.
+func serveStream(cli *dockerclient.Client, ctx context.Context, gRPCClient orchestrator.HostAgentServiceClient) (time.Time, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := gRPCClient.ConnectAgentStream(streamCtx)
 	if err != nil {
-		log.Printf("MAC address lookup failed: %v", err)
+		return time.Time{}, fmt.Errorf("open stream: %w", err)
+	}
+
+	// Step 1: handshake. An orchestrator that predates it never answers, so the stream is
+	// closed when no answer came in time.
+	timer := time.AfterFunc(helloTimeout, cancel)
+	err = hello(cli, ctx, stream)
+	if !timer.Stop() {
+		return time.Time{}, fmt.Errorf("handshake: no answer within %s, the orchestrator may predate the handshake and is not compatible with this agent", helloTimeout)
 	}
-	stream, err := gRPCClient.ConnectAgentStream(ctx)
 	if err != nil {
-		return err
+		return time.Time{}, fmt.Errorf("handshake: %w", err)
 	}
-	log.Println("Connected to orchestrator stream")
 
-	// Step 1: send dummy registration update
-	err = stream.Send(&orchestrator.UpdateStatus{
-		ContainerUID: "init-container",
-		MacAddress:   agentID,
-		Stage:        orchestrator.UpdateStatus_COMPLETED,
-		Logs:         "Agent connected",
-		Timestamp:    time.Now().String(),
-		Image:        "init-image",
-	})
+	// Step 2: register after the stream is open, so the orchestrator marking the host offline
+	// when it notices a previous stream of this agent is gone can't undo the registration.
+	regCtx, regCancel := context.WithTimeout(ctx, registerTimeout)
+	err = RegisterAgent(cli, regCtx, gRPCClient)
+	regCancel()
 	if err != nil {
-		return err
+		return time.Time{}, fmt.Errorf("register: %w", err)
 	}
+	log.Println("Connected to orchestrator stream")
+	connectedAt := time.Now()
+	setConnState(StateConnected, nil, time.Time{})
+	flushJournalReports(stream)
 
-	// Step 2: Goroutine to receive commands from orchestrator
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("Stream listener stopping")
-				return
-			default:
-				cmd, err := stream.Recv()
-				if err == io.EOF {
-					log.Println("Stream closed by server")
-					return
-				}
-				if err != nil {
-					log.Printf("Command receive error: %v", err)
-					continue
-				}
-
-				log.Printf("Received command: %+v", cmd)
-				// Process the command to update container
-				err = UpdateContainer(cli, ctx, cmd, stream)
-				if err != nil {
-					log.Printf("Update command failed: %v", err)
-				}
-			}
+	// Step 3: receive commands from orchestrator
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return connectedAt, errors.New("stream closed by orchestrator")
+		}
+		if err != nil {
+			return connectedAt, err
+		}
+		if cancel := in.GetCancel(); cancel != nil {
+			cancelUpdate(cancel)
+			continue
+		}
+		cmd := in.GetCommand()
+		if cmd == nil {
+			log.Printf("Ignoring unexpected message from orchestrator")
+			continue
 		}
-	}()
-
-	// Handle graceful shutdown
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
-	<-sigCh
-	log.Println("Shutdown signal received, cleaning up...")
 
-	// Close the send side of the stream
-	if err := stream.CloseSend(); err != nil {
-		log.Printf("Error closing stream: %v", err)
+		log.Printf("Received command: %+v", cmd)
+		// An update carries on when the stream is lost; its remaining statuses are dropped.
+		submitUpdate(cli, ctx, cmd, stream)
 	}
-
-	return nil
 }
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	dockerclient "github.com/docker/docker/client"
+)
+
+// defaultMaxParallelUpdates is how many updates run at once unless configured otherwise.
+const defaultMaxParallelUpdates = 2
+
+// updateJob is an update command with the stream its statuses are sent on. Cancelling its
+// context aborts the update.
+type updateJob struct {
+	cmd    *orchestrator.UpdateContainerCommand
+	stream orchestrator.HostAgentService_ConnectAgentStreamClient
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var (
+	workersMu sync.Mutex
+	// workerSlots limits how many updates run at once.
+	workerSlots = make(chan struct{}, defaultMaxParallelUpdates)
+	// containerJobs holds the jobs of each container, the running or next one first. A container
+	// has a key while its jobs are being worked through.
+	containerJobs = map[string][]updateJob{}
+	// queued counts the jobs accepted but not started yet.
+	queued int
+	// cancelled holds the reason of each running command a CancelUpdate was received for.
+	cancelled = map[string]string{}
+)
+
+// SetMaxParallelUpdates sets how many updates of different containers run at once. It must be
+// called before the first command is received.
+func SetMaxParallelUpdates(n int) {
+	if n < 1 {
+		n = 1
+	}
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	workerSlots = make(chan struct{}, n)
+}
+
+// queueDepth returns the number of updates waiting to start.
+func queueDepth() uint32 {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	return uint32(queued)
+}
+
+// cancelReason reports whether the update was cancelled while running, and why.
+func cancelReason(update *orchestrator.UpdateContainerCommand) (string, bool) {
+	workersMu.Lock()
+	defer workersMu.Unlock()
+	reason, ok := cancelled[update.CommandId]
+	return reason, ok
+}
+
+// submitUpdate queues a command without waiting for it to run. Updates of the same container
+// run one after another, in the order received; a command asking for the same image as one
+// already queued or running for the container is rejected.
+func submitUpdate(cli *dockerclient.Client, ctx context.Context, cmd *orchestrator.UpdateContainerCommand, stream orchestrator.HostAgentService_ConnectAgentStreamClient) {
+	key := cmd.ContainerName
+	if key == "" {
+		key = cmd.ContainerUID
+	}
+
+	workersMu.Lock()
+	jobs := containerJobs[key]
+	for i, job := range jobs {
+		if job.cmd.Image == cmd.Image && job.cmd.Digest == cmd.Digest {
+			workersMu.Unlock()
+			state := "queued"
+			if i == 0 {
+				state = "in progress"
+			}
+			log.Printf("Rejecting duplicate update of %s to %s", key, cmd.Image)
+			sendStatus(stream, cmd, orchestrator.UpdateStatus_REJECTED, fmt.Sprintf("An update of %s to %s is already %s", key, cmd.Image, state))
+			return
+		}
+	}
+	jobCtx, cancel := context.WithCancel(ctx)
+	containerJobs[key] = append(jobs, updateJob{cmd: cmd, stream: stream, ctx: jobCtx, cancel: cancel})
+	queued++
+	ahead := len(jobs)
+	slots := workerSlots
+	workersMu.Unlock()
+
+	switch {
+	case ahead > 0:
+		sendStatus(stream, cmd, orchestrator.UpdateStatus_QUEUED, fmt.Sprintf("Waiting for %d earlier update(s) of %s", ahead, key))
+		return // the goroutine working through the container's jobs picks it up
+	case len(slots) == cap(slots):
+		sendStatus(stream, cmd, orchestrator.UpdateStatus_QUEUED, fmt.Sprintf("Waiting for one of %d running updates to finish", cap(slots)))
+	}
+	go runContainerJobs(cli, ctx, key, slots)
+}
+
+// runContainerJobs runs the jobs of a container one at a time until none are left.
+func runContainerJobs(cli *dockerclient.Client, ctx context.Context, key string, slots chan struct{}) {
+	for {
+		workersMu.Lock()
+		job := containerJobs[key][0]
+		workersMu.Unlock()
+
+		started := true
+		select {
+		case slots <- struct{}{}:
+		case <-job.ctx.Done():
+			if ctx.Err() != nil {
+				workersMu.Lock()
+				queued -= len(containerJobs[key])
+				for _, j := range containerJobs[key] {
+					j.cancel()
+				}
+				delete(containerJobs, key)
+				workersMu.Unlock()
+				return
+			}
+			started = false
+		}
+		workersMu.Lock()
+		queued--
+		workersMu.Unlock()
+
+		if started {
+			if err := UpdateContainer(cli, job.ctx, job.cmd, job.stream); err != nil {
+				log.Printf("Update command failed: %v", err)
+			}
+			<-slots
+		} else {
+			log.Printf("Update of %s to %s cancelled before it started", key, job.cmd.Image)
+			sendStatus(job.stream, job.cmd, orchestrator.UpdateStatus_CANCELLED, "Update cancelled before it started")
+		}
+		job.cancel()
+
+		workersMu.Lock()
+		delete(cancelled, job.cmd.CommandId)
+		rest := containerJobs[key][1:]
+		if len(rest) == 0 {
+			delete(containerJobs, key)
+			workersMu.Unlock()
+			return
+		}
+		containerJobs[key] = rest
+		workersMu.Unlock()
+	}
+}
+
+// cancelUpdate aborts the job a CancelUpdate names, by command ID or else by container name. A
+// command ID the agent doesn't know, e.g. of a command that was rejected, falls back to the
+// container's running or next job. A queued job is dropped; a running one stops at its current
+// step and rolls back.
+func cancelUpdate(msg *orchestrator.CancelUpdate) {
+	workersMu.Lock()
+	key, i, ok := findJob(func(job updateJob) bool { return msg.CommandId != "" && job.cmd.CommandId == msg.CommandId })
+	if !ok {
+		key, i, ok = findJob(func(job updateJob) bool { return job.cmd.ContainerName == msg.ContainerName })
+	}
+	if !ok {
+		workersMu.Unlock()
+		log.Printf("No update %s of %s to cancel", msg.CommandId, msg.ContainerName)
+		return
+	}
+	jobs := containerJobs[key]
+	job := jobs[i]
+	if i == 0 {
+		// Running, or waiting for a slot: runContainerJobs reports the outcome
+		cancelled[job.cmd.CommandId] = msg.Reason
+		workersMu.Unlock()
+		log.Printf("Cancelling update of %s to %s: %s", key, job.cmd.Image, msg.Reason)
+		job.cancel()
+		return
+	}
+	containerJobs[key] = slices.Delete(jobs, i, i+1)
+	queued--
+	workersMu.Unlock()
+	job.cancel()
+	log.Printf("Dropped queued update of %s to %s: %s", key, job.cmd.Image, msg.Reason)
+	sendStatus(job.stream, job.cmd, orchestrator.UpdateStatus_CANCELLED, "Update cancelled while queued")
+}
+
+// findJob returns the container key and position of a job matching; of the jobs of a container,
+// the running or next one comes first.
+// workersMu must be held.
+func findJob(match func(updateJob) bool) (string, int, bool) {
+	for key, jobs := range containerJobs {
+		for i, job := range jobs {
+			if match(job) {
+				return key, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/MadhavKrishanGoswami/Lighthouse/services/host-agent/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 // StartClient tries to connect to the gRPC server and waits until it's available.
@@ -18,6 +19,13 @@ func StartClient(cfg config.Config) (orchestrator.HostAgentServiceClient, *grpc.
 	addr := cfg.OrchestratorAddr
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// Pings detect a dead orchestrator or network path while the command stream is idle.
+		// The orchestrator's enforcement policy must allow this interval.
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                20 * time.Second,
+			Timeout:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
 	}
 
 	var conn *grpc.ClientConn
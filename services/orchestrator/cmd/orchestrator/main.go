@@ -7,14 +7,17 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	// Internal package imports
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/admission"
 	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/config"
 	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
 	agentserver "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/agent"
 	registryclient "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/registry-monitor"
 	tuiserver "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/tui"
 	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/monitor"
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/policy"
 
 	// Proto definitions
 	agentpb "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
@@ -24,6 +27,7 @@ import (
 	// External dependencies
 	"github.com/jackc/pgx/v5"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 func main() {
@@ -66,10 +70,23 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to listen on address %s: %v", cfg.GRPCServer.OrcastraterAddr, err)
 	}
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		// Agents ping every 20s to detect lost connections; allow it and ping idle agents too.
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    30 * time.Second,
+			Timeout: 10 * time.Second,
+		}),
+	)
 
 	// --- 5. Service Registration ---
 	agentServer := agentserver.NewServer(queries)
+	if err := queries.MarkAllHostsOffline(ctx); err != nil {
+		log.Printf("Mark hosts offline failed: %v", err)
+	}
 	agentpb.RegisterHostAgentServiceServer(grpcServer, agentServer)
 	log.Println("HostAgentService registered.")
 
@@ -94,11 +111,35 @@ func main() {
 			log.Fatalf("Failed to serve gRPC server: %v", err)
 		}
 	}()
+	// -----reconciling the policy file on startup, on change and on SIGHUP-----
+	if cfg.Policies.Path != "" {
+		// Rewrites run in a transaction on their own connection
+		policyConn, err := pgx.Connect(ctx, cfg.DataBaseURL)
+		if err != nil {
+			log.Fatalf("Failed to connect policy reconciler to database: %v", err)
+		}
+		defer policyConn.Close(ctx)
+		reconciler := policy.NewReconciler(cfg.Policies, policyConn)
+		monitor.SetPolicyReconciler(reconciler)
+		go reconciler.Run(ctx)
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		go func() {
+			for range hup {
+				if err := reconciler.Reconcile(ctx, "SIGHUP"); err != nil {
+					log.Printf("[Policy] %v", err)
+				}
+			}
+		}()
+		log.Printf("Policy file %s loaded.", cfg.Policies.Path)
+	}
 	// -----starting cron job for monitoring after host agentserver is connected-----
 	go func() {
 		log.Println("Starting cron job for monitoring...")
 		// Wire dependencies so SetCronTime can restart correctly
 		monitor.SetRuntimeDeps(registryMonitorClient, queries, agentServer)
+		monitor.SetAdmissionController(admission.New(cfg.Admission.Webhooks))
+		monitor.SetUpdatePolicy(cfg.Updates)
 		monitor.SetCronTimeInHours(1) // Set to 1 hour, can be made configurable
 		monitor.StartCronJob(registryMonitorClient, queries, agentServer)
 	}()
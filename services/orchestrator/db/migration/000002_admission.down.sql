@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS admission_decisions;
+ALTER TABLE containers DROP COLUMN IF EXISTS labels;
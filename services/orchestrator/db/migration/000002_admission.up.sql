@@ -0,0 +1,20 @@
+-- Container labels are forwarded to admission webhooks
+ALTER TABLE containers ADD COLUMN labels jsonb DEFAULT '{}'::jsonb;
+
+-- Admission decisions table
+CREATE TABLE admission_decisions (
+  id SERIAL PRIMARY KEY,
+  host_id uuid NOT NULL,
+  container_uid varchar NOT NULL,
+  container_name varchar NOT NULL,
+  current_image varchar NOT NULL,
+  new_image varchar NOT NULL,
+  webhook varchar NOT NULL,
+  allowed boolean NOT NULL,
+  reason text,
+  created_at timestamptz DEFAULT now()
+);
+
+COMMENT ON COLUMN admission_decisions.host_id IS 'FK → hosts.id. Decision recorded for a container on this host.';
+
+ALTER TABLE admission_decisions ADD FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE;
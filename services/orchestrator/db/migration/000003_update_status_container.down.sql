@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS update_status_host_container_idx;
+ALTER TABLE update_status DROP COLUMN IF EXISTS container_name;
+ALTER TABLE update_status DROP COLUMN IF EXISTS container_uid;
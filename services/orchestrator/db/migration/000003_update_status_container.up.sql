@@ -0,0 +1,5 @@
+-- Track which container an update status belongs to
+ALTER TABLE update_status ADD COLUMN container_uid varchar;
+ALTER TABLE update_status ADD COLUMN container_name varchar;
+
+CREATE INDEX update_status_host_container_idx ON update_status (host_id, container_name, created_at);
@@ -0,0 +1,17 @@
+-- Image history and pins per container. Keyed by name because the container UID
+-- changes every time a container is re-created by an update.
+CREATE TABLE container_versions (
+  host_id uuid NOT NULL,
+  container_name varchar NOT NULL,
+  current_image varchar,
+  current_digest varchar,
+  previous_image varchar,
+  previous_digest varchar,
+  pinned boolean NOT NULL DEFAULT FALSE,
+  updated_at timestamptz DEFAULT now(),
+  PRIMARY KEY (host_id, container_name)
+);
+
+COMMENT ON COLUMN container_versions.host_id IS 'FK → hosts.id. Versions of a container on this host.';
+
+ALTER TABLE container_versions ADD FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE;
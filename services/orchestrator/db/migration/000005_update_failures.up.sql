@@ -0,0 +1,16 @@
+-- Failed update attempts per container and candidate digest. Used for retry
+-- backoff and to quarantine digests that keep failing.
+CREATE TABLE update_failures (
+  host_id uuid NOT NULL,
+  container_name varchar NOT NULL,
+  digest varchar NOT NULL,
+  image varchar NOT NULL,
+  failures integer NOT NULL DEFAULT 1,
+  last_error text,
+  last_failure_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (host_id, container_name, digest)
+);
+
+COMMENT ON COLUMN update_failures.host_id IS 'FK → hosts.id. Failed updates of a container on this host.';
+
+ALTER TABLE update_failures ADD FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS container_overrides;
+ALTER TABLE containers DROP COLUMN IF EXISTS image_digest;
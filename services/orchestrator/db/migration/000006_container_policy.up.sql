@@ -0,0 +1,15 @@
+-- Registry digest of the running image, used by the "digest" update policy
+ALTER TABLE containers ADD COLUMN image_digest varchar;
+
+-- Settings made by hand in the TUI. They override the policy declared in container labels.
+CREATE TABLE container_overrides (
+  host_id uuid NOT NULL,
+  container_name varchar NOT NULL,
+  watch boolean,
+  updated_at timestamptz DEFAULT now(),
+  PRIMARY KEY (host_id, container_name)
+);
+
+COMMENT ON COLUMN container_overrides.host_id IS 'FK → hosts.id. Overrides for a container on this host.';
+
+ALTER TABLE container_overrides ADD FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE;
@@ -0,0 +1,20 @@
+-- Policies assigned to containers by the declarative policy file. Rewritten on every reconcile.
+CREATE TABLE container_policies (
+  host_id uuid NOT NULL,
+  container_name varchar NOT NULL,
+  watch boolean,
+  strategy varchar,
+  tag_pattern varchar,
+  schedule varchar,
+  approval_required boolean,
+  notify_only boolean,
+  rollout_group varchar,
+  rules text NOT NULL,
+  updated_at timestamptz DEFAULT now(),
+  PRIMARY KEY (host_id, container_name)
+);
+
+COMMENT ON COLUMN container_policies.host_id IS 'FK → hosts.id. Policy of a container on this host.';
+COMMENT ON COLUMN container_policies.rules IS 'Names of the policy file rules that matched, comma separated.';
+
+ALTER TABLE container_policies ADD FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS available_updates;
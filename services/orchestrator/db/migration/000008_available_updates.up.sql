@@ -0,0 +1,18 @@
+-- Updates a check found for a container, whether or not they were applied
+CREATE TABLE available_updates (
+  host_id uuid NOT NULL,
+  container_name varchar NOT NULL,
+  current_image varchar NOT NULL,
+  image varchar NOT NULL,
+  digest varchar NOT NULL DEFAULT '',
+  description text,
+  detected_at timestamptz NOT NULL DEFAULT now(),
+  last_checked_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (host_id, container_name)
+);
+
+COMMENT ON COLUMN available_updates.host_id IS 'FK → hosts.id. Available update of a container on this host.';
+COMMENT ON COLUMN available_updates.current_image IS 'Image the container ran when the update was found.';
+COMMENT ON COLUMN available_updates.detected_at IS 'When this image and digest were first found.';
+
+ALTER TABLE available_updates ADD FOREIGN KEY (host_id) REFERENCES hosts(id) ON DELETE CASCADE;
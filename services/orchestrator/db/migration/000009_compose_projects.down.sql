@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS containers_host_project_idx;
+ALTER TABLE containers DROP COLUMN IF EXISTS depends_on;
+ALTER TABLE containers DROP COLUMN IF EXISTS compose_service;
+ALTER TABLE containers DROP COLUMN IF EXISTS compose_project;
@@ -0,0 +1,6 @@
+-- Docker Compose project membership reported by the agent
+ALTER TABLE containers ADD COLUMN compose_project varchar;
+ALTER TABLE containers ADD COLUMN compose_service varchar;
+ALTER TABLE containers ADD COLUMN depends_on text[];
+
+CREATE INDEX containers_host_project_idx ON containers (host_id, compose_project);
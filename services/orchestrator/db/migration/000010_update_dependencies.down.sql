@@ -0,0 +1 @@
+ALTER TABLE container_policies DROP COLUMN IF EXISTS depends_on;
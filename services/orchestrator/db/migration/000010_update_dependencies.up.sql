@@ -0,0 +1,2 @@
+-- Containers, possibly on other hosts, that must finish updating first ("host/container" or "container")
+ALTER TABLE container_policies ADD COLUMN depends_on text[];
@@ -0,0 +1,2 @@
+-- Enum values cannot be dropped; 'hook' stays in update_stage.
+ALTER TABLE container_policies DROP COLUMN IF EXISTS hooks;
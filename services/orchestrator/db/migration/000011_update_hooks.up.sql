@@ -0,0 +1,5 @@
+-- Output of pre- and post-update hooks
+ALTER TYPE update_stage ADD VALUE IF NOT EXISTS 'hook';
+
+-- Hooks the policy file assigns to a container, as a JSON array
+ALTER TABLE container_policies ADD COLUMN hooks jsonb;
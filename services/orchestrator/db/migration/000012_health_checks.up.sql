@@ -0,0 +1,2 @@
+-- Health check the policy file assigns to a container, as a JSON object
+ALTER TABLE container_policies ADD COLUMN health jsonb;
@@ -0,0 +1 @@
+ALTER TABLE containers DROP COLUMN IF EXISTS spec;
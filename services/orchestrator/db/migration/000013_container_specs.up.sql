@@ -0,0 +1,2 @@
+-- Full configuration of a container, as reported by its agent, to recreate it on updates
+ALTER TABLE containers ADD COLUMN spec jsonb;
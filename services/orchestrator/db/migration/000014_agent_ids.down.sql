@@ -0,0 +1,5 @@
+ALTER TABLE hosts ADD CONSTRAINT hosts_mac_address_key UNIQUE (mac_address);
+ALTER TABLE hosts ADD CONSTRAINT hosts_hostname_key UNIQUE (hostname);
+ALTER TABLE hosts ADD CONSTRAINT hosts_ip_address_key UNIQUE (ip_address);
+
+ALTER TABLE hosts DROP COLUMN IF EXISTS agent_id;
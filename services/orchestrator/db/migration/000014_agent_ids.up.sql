@@ -0,0 +1,11 @@
+-- Hosts are identified by the UUID their agent generates; MAC address, hostname and IP
+-- address are mutable attributes. Hosts registered before keep their MAC address as ID,
+-- which is what older agents keep sending.
+ALTER TABLE hosts ADD COLUMN agent_id varchar;
+UPDATE hosts SET agent_id = mac_address;
+ALTER TABLE hosts ALTER COLUMN agent_id SET NOT NULL;
+ALTER TABLE hosts ADD CONSTRAINT hosts_agent_id_key UNIQUE (agent_id);
+
+ALTER TABLE hosts DROP CONSTRAINT IF EXISTS hosts_mac_address_key;
+ALTER TABLE hosts DROP CONSTRAINT IF EXISTS hosts_hostname_key;
+ALTER TABLE hosts DROP CONSTRAINT IF EXISTS hosts_ip_address_key;
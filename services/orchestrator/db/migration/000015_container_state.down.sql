@@ -0,0 +1,2 @@
+ALTER TABLE containers DROP COLUMN IF EXISTS health;
+ALTER TABLE containers DROP COLUMN IF EXISTS state;
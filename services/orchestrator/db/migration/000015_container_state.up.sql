@@ -0,0 +1,3 @@
+-- State and health of a container as last reported by its agent
+ALTER TABLE containers ADD COLUMN state varchar;
+ALTER TABLE containers ADD COLUMN health varchar;
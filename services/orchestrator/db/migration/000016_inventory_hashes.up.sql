@@ -0,0 +1,3 @@
+-- Hash of the container inventory last reported by a host's agent; agents send only the
+-- changes to it.
+ALTER TABLE hosts ADD COLUMN inventory_hash varchar;
@@ -0,0 +1 @@
+-- Enum values cannot be dropped; 'queued' and 'rejected' stay in update_stage.
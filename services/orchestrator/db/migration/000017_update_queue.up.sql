@@ -0,0 +1,3 @@
+-- Updates waiting on the agent, and updates it refused as duplicates
+ALTER TYPE update_stage ADD VALUE IF NOT EXISTS 'queued';
+ALTER TYPE update_stage ADD VALUE IF NOT EXISTS 'rejected';
@@ -0,0 +1 @@
+-- Enum values cannot be dropped; 'cancelled' stays in update_stage.
@@ -0,0 +1,2 @@
+-- Updates aborted on request
+ALTER TYPE update_stage ADD VALUE IF NOT EXISTS 'cancelled';
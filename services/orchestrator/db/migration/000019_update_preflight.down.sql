@@ -0,0 +1 @@
+-- Enum values cannot be dropped; 'preflight' stays in update_stage.
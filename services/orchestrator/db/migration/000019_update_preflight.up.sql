@@ -0,0 +1,2 @@
+-- Pre-flight checks passed before replacing a container
+ALTER TYPE update_stage ADD VALUE IF NOT EXISTS 'preflight';
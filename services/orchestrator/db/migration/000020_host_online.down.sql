@@ -0,0 +1 @@
+ALTER TABLE hosts DROP COLUMN IF EXISTS online;
@@ -0,0 +1,3 @@
+-- Hosts are kept when their agent disconnects, so what is recorded about their containers
+-- survives a restart of the agent; they are marked offline instead.
+ALTER TABLE hosts ADD COLUMN online boolean NOT NULL DEFAULT FALSE;
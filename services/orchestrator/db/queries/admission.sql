@@ -0,0 +1,19 @@
+-- name: InsertAdmissionDecision :one
+-- Records the outcome of an admission review for a planned update.
+INSERT INTO admission_decisions (
+  host_id,
+  container_uid,
+  container_name,
+  current_image,
+  new_image,
+  webhook,
+  allowed,
+  reason
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+-- name: GetLatestAdmissionDecisionsForHost :many
+-- Retrieves the most recent admission decision for each container on a host.
+SELECT DISTINCT ON (container_name) *
+FROM admission_decisions
+WHERE host_id = $1
+ORDER BY container_name, created_at DESC;
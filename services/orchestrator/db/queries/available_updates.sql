@@ -0,0 +1,31 @@
+-- name: RecordAvailableUpdate :one
+-- Stores an update found for a container. detected_at only moves when a different image or digest is found.
+INSERT INTO available_updates (
+  host_id,
+  container_name,
+  current_image,
+  image,
+  digest,
+  description
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET
+  detected_at = CASE
+    WHEN available_updates.image <> EXCLUDED.image OR available_updates.digest <> EXCLUDED.digest
+      OR available_updates.current_image <> EXCLUDED.current_image THEN now()
+    ELSE available_updates.detected_at
+  END,
+  current_image = EXCLUDED.current_image,
+  image = EXCLUDED.image,
+  digest = EXCLUDED.digest,
+  description = EXCLUDED.description,
+  last_checked_at = now()
+RETURNING *;
+-- name: GetAvailableUpdatesForHost :many
+-- Retrieves the updates found for the containers on a host.
+SELECT * FROM available_updates
+WHERE host_id = $1;
+-- name: ClearAvailableUpdate :exec
+-- Forgets the update found for a container once it has been applied.
+DELETE FROM available_updates
+WHERE host_id = $1 AND container_name = $2;
@@ -0,0 +1,16 @@
+-- name: SetWatchOverride :exec
+-- Records a watch state set by hand for a container by its name and the agent ID of its host.
+INSERT INTO container_overrides (host_id, container_name, watch)
+SELECT id, $1, $2 FROM hosts WHERE agent_id = $3
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET watch = EXCLUDED.watch, updated_at = now();
+-- name: GetAllContainerOverrides :many
+-- Retrieves the overrides of all containers on all hosts.
+SELECT * FROM container_overrides;
+-- name: GetContainerOverridesForHost :many
+-- Retrieves the overrides of all containers on a host.
+SELECT * FROM container_overrides WHERE host_id = $1;
+-- name: ClearWatchOverride :exec
+-- Drops the watch state set by hand for a container.
+UPDATE container_overrides SET watch = NULL, updated_at = now()
+WHERE host_id = $1 AND container_name = $2;
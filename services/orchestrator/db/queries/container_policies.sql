@@ -0,0 +1,41 @@
+-- name: UpsertContainerPolicy :exec
+-- Stores the policy the policy file assigns to a container.
+INSERT INTO container_policies (
+  host_id,
+  container_name,
+  watch,
+  strategy,
+  tag_pattern,
+  schedule,
+  approval_required,
+  notify_only,
+  rollout_group,
+  rules,
+  depends_on,
+  hooks,
+  health
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET
+  watch = EXCLUDED.watch,
+  strategy = EXCLUDED.strategy,
+  tag_pattern = EXCLUDED.tag_pattern,
+  schedule = EXCLUDED.schedule,
+  approval_required = EXCLUDED.approval_required,
+  notify_only = EXCLUDED.notify_only,
+  rollout_group = EXCLUDED.rollout_group,
+  rules = EXCLUDED.rules,
+  depends_on = EXCLUDED.depends_on,
+  hooks = EXCLUDED.hooks,
+  health = EXCLUDED.health,
+  updated_at = now();
+-- name: DeleteAllContainerPolicies :exec
+-- Removes all policies assigned by the policy file before they are reconciled again.
+DELETE FROM container_policies;
+-- name: GetAllContainerPolicies :many
+-- Retrieves the policies assigned by the policy file to all containers.
+SELECT * FROM container_policies;
+-- name: GetContainerPolicy :one
+-- Retrieves the policy the policy file assigns to a container.
+SELECT * FROM container_policies
+WHERE host_id = $1 AND container_name = $2;
@@ -0,0 +1,31 @@
+-- name: RecordContainerVersion :one
+-- Records the image a container now runs and the one it replaced after a successful update.
+INSERT INTO container_versions (
+  host_id,
+  container_name,
+  current_image,
+  current_digest,
+  previous_image,
+  previous_digest
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET
+  current_image = EXCLUDED.current_image,
+  current_digest = EXCLUDED.current_digest,
+  previous_image = EXCLUDED.previous_image,
+  previous_digest = EXCLUDED.previous_digest,
+  updated_at = now()
+RETURNING *;
+-- name: GetContainerVersion :one
+-- Retrieves the recorded versions of a container on a host.
+SELECT * FROM container_versions
+WHERE host_id = $1 AND container_name = $2;
+-- name: GetContainerVersionsForHost :many
+-- Retrieves the recorded versions of all containers on a host.
+SELECT * FROM container_versions WHERE host_id = $1;
+-- name: SetContainerPinned :exec
+-- Pins or unpins a container. Pinned containers are skipped by automatic updates.
+INSERT INTO container_versions (host_id, container_name, pinned)
+VALUES ($1, $2, $3)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET pinned = EXCLUDED.pinned, updated_at = now();
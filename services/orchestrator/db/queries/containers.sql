@@ -7,8 +7,16 @@ INSERT INTO containers (
   ports,
   env_vars,
   volumes,
-  network
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+  network,
+  labels,
+  image_digest,
+  compose_project,
+  compose_service,
+  depends_on,
+  spec,
+  state,
+  health
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 ON CONFLICT (container_uid)
 DO UPDATE SET
   host_id = EXCLUDED.host_id,
@@ -17,16 +25,31 @@ DO UPDATE SET
   ports = EXCLUDED.ports,
   env_vars = EXCLUDED.env_vars,
   volumes = EXCLUDED.volumes,
-  network = EXCLUDED.network
+  network = EXCLUDED.network,
+  labels = EXCLUDED.labels,
+  image_digest = EXCLUDED.image_digest,
+  compose_project = EXCLUDED.compose_project,
+  compose_service = EXCLUDED.compose_service,
+  depends_on = EXCLUDED.depends_on,
+  spec = EXCLUDED.spec,
+  state = EXCLUDED.state,
+  health = EXCLUDED.health
 RETURNING *;
 
 -- name: DeleteStaleContainersForHost :exec
 -- Deletes containers for a given host that are not in the provided list of UIDs.
 DELETE FROM containers
 WHERE host_id = $1 AND container_uid <> ALL($2::text[]);
+-- name: DeleteContainersForHost :exec
+-- Deletes the containers of a host with the given UIDs.
+DELETE FROM containers
+WHERE host_id = $1 AND container_uid = ANY($2::text[]);
 -- name: GetallContainersWhereWatched :many
 -- Retrieves all containers where watched is true
 SELECT * FROM containers WHERE watch = TRUE;
+-- name: GetAllContainers :many
+-- Retrieves all containers on all hosts
+SELECT * FROM containers;
 -- name: GetHostbyContainerUID :one
 -- Retrieves the host associated with a given container UID 
 SELECT h.*
@@ -37,11 +60,15 @@ WHERE c.container_uid = $1;
 -- Retrieves a container by its UID
 SELECT * FROM containers WHERE container_uid = $1;
 -- name: SetWatchStatus :exec
--- Updates the watch status of a container by its name and macid on the host
+-- Updates the watch status of a container by its name and the agent ID of its host
 UPDATE containers
 SET watch = $1
-WHERE name = $2 AND host_id = (SELECT id FROM hosts WHERE  mac_address = $3);
+WHERE name = $2 AND host_id = (SELECT id FROM hosts WHERE agent_id = $3);
 -- name: GetAllContainersonHost :many
 -- Retrieves all containers associated with a given host ID 
 SELECT * FROM containers WHERE host_id = $1;
-
+-- name: GetProjectContainers :many
+-- Retrieves the containers of a Docker Compose project on a host.
+SELECT * FROM containers
+WHERE host_id = $1 AND compose_project = $2
+ORDER BY name;
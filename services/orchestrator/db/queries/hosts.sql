@@ -1,28 +1,46 @@
 -- name: InsertHost :one
--- Inserts a new host or updates an existing one based on the MAC address.
+-- Inserts a new host or updates the attributes of an existing one based on the agent ID.
+-- A registering host is online.
 INSERT INTO hosts (
+  agent_id,
   mac_address,
   hostname,
-  ip_address
+  ip_address,
+  online
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, TRUE
 )
-ON CONFLICT (mac_address)
+ON CONFLICT (agent_id)
 DO UPDATE SET
+  mac_address = EXCLUDED.mac_address,
   hostname = EXCLUDED.hostname,
-  ip_address = EXCLUDED.ip_address
+  ip_address = EXCLUDED.ip_address,
+  online = TRUE
 RETURNING *;
 
--- name: GetHostByMacAddress :one
--- Retrieves a host by its mac_address.
-SELECT * FROM hosts WHERE mac_address = $1;
+-- name: GetHostByAgentID :one
+-- Retrieves a host by the ID of its agent.
+SELECT * FROM hosts WHERE agent_id = $1;
 
 -- name: UpdateHostLastHeartbeat :one
 -- Updates the last heartbeat timestamp for a host identified by id.
 UPDATE hosts SET last_heartbeat = NOW() WHERE id = $1 RETURNING *;
+-- name: SetHostInventoryHash :exec
+-- Records the hash of the inventory an agent reported for a host.
+UPDATE hosts SET inventory_hash = $2 WHERE id = $1;
 -- name: GetAllHosts :many
 -- Retrieves all hosts from the database.
 SELECT * FROM hosts;
--- name: DeleteHostByMacAddress :exec
-DELETE FROM hosts
-WHERE mac_address = $1;
+-- name: SetHostOffline :exec
+-- Marks a host offline when the command stream of its agent closes.
+UPDATE hosts SET online = FALSE WHERE agent_id = $1;
+-- name: MarkAllHostsOffline :exec
+-- Marks every host offline; no agent is connected when the orchestrator starts.
+UPDATE hosts SET online = FALSE;
+-- name: AdoptLegacyHost :execrows
+-- Hands the row of a host registered before agent IDs existed, which kept its MAC address as
+-- agent ID, to the generated ID its upgraded agent registers with.
+UPDATE hosts SET agent_id = sqlc.arg(agent_id)
+WHERE hosts.agent_id = sqlc.arg(mac_address)
+  AND hosts.mac_address = sqlc.arg(mac_address)
+  AND NOT EXISTS (SELECT 1 FROM hosts h WHERE h.agent_id = sqlc.arg(agent_id));
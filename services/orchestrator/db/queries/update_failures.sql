@@ -0,0 +1,29 @@
+-- name: RecordUpdateFailure :one
+-- Counts a failed attempt to update a container to a candidate digest.
+INSERT INTO update_failures (
+  host_id,
+  container_name,
+  digest,
+  image,
+  last_error
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (host_id, container_name, digest)
+DO UPDATE SET
+  failures = update_failures.failures + 1,
+  image = EXCLUDED.image,
+  last_error = EXCLUDED.last_error,
+  last_failure_at = now()
+RETURNING *;
+-- name: GetUpdateFailure :one
+-- Retrieves the failed attempts to update a container to a candidate digest.
+SELECT * FROM update_failures
+WHERE host_id = $1 AND container_name = $2 AND digest = $3;
+-- name: GetUpdateFailuresForHost :many
+-- Retrieves the failed update attempts of all containers on a host, most recent first.
+SELECT * FROM update_failures
+WHERE host_id = $1
+ORDER BY last_failure_at DESC;
+-- name: ClearUpdateFailures :exec
+-- Forgets the failed update attempts of a container, lifting any quarantine.
+DELETE FROM update_failures
+WHERE host_id = $1 AND container_name = $2;
@@ -4,8 +4,22 @@ INSERT INTO update_status (
   host_id,
   stage,
   logs,
-  image
+  image,
+  container_uid,
+  container_name
   ) 
 VALUES (
-  $1, $2, $3, $4
+  $1, $2, $3, $4, $5, $6
 ) RETURNING *;
+-- name: GetLastCompletedUpdate :one
+-- Retrieves the most recent completed update of a container on a host.
+SELECT * FROM update_status
+WHERE host_id = $1 AND container_name = $2 AND stage = 'completed'
+ORDER BY created_at DESC
+LIMIT 1;
+-- name: GetLatestUpdateStatusesForHost :many
+-- Retrieves the most recent update status reported for each container on a host.
+SELECT DISTINCT ON (container_name) *
+FROM update_status
+WHERE host_id = $1 AND container_name IS NOT NULL
+ORDER BY container_name, created_at DESC, id DESC;
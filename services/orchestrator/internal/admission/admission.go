@@ -0,0 +1,145 @@
+// Package admission consults external HTTP webhooks before an update is dispatched.
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/config"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+
+	// FailOpen allows the update when the webhook cannot be reached.
+	FailOpen = "fail-open"
+	// FailClosed denies the update when the webhook cannot be reached.
+	FailClosed = "fail-closed"
+)
+
+// HostRef identifies the host a planned update targets.
+type HostRef struct {
+	AgentID    string `json:"agentId"`
+	MacAddress string `json:"macAddress"`
+	Hostname   string `json:"hostname"`
+	IPAddress  string `json:"ipAddress"`
+}
+
+// ContainerRef identifies the container a planned update targets.
+type ContainerRef struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+}
+
+// Review is the JSON document POSTed to every admission webhook.
+type Review struct {
+	Host         HostRef           `json:"host"`
+	Container    ContainerRef      `json:"container"`
+	CurrentImage string            `json:"currentImage"`
+	NewImage     string            `json:"newImage"`
+	Labels       map[string]string `json:"labels"`
+	Metadata     map[string]string `json:"metadata"`
+}
+
+// response is the body a webhook is expected to answer with.
+type response struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason"`
+}
+
+// Decision is the combined verdict of all configured webhooks.
+type Decision struct {
+	Allowed bool
+	Webhook string // webhook that produced the verdict, empty when none are configured
+	Reason  string
+}
+
+// Controller sends reviews to the configured webhooks in order.
+type Controller struct {
+	webhooks   []config.AdmissionWebhook
+	httpClient *http.Client
+}
+
+// New creates a Controller for the given webhooks. A nil or empty list admits every update.
+func New(webhooks []config.AdmissionWebhook) *Controller {
+	return &Controller{
+		webhooks:   webhooks,
+		httpClient: &http.Client{},
+	}
+}
+
+// Enabled reports whether any webhook is configured.
+func (c *Controller) Enabled() bool {
+	return c != nil && len(c.webhooks) > 0
+}
+
+// Review asks every webhook about the planned update. The first denial wins.
+func (c *Controller) Review(ctx context.Context, review Review) Decision {
+	if !c.Enabled() {
+		return Decision{Allowed: true}
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		return Decision{Allowed: false, Reason: fmt.Sprintf("failed to encode review: %v", err)}
+	}
+
+	last := Decision{Allowed: true}
+	for _, wh := range c.webhooks {
+		name := wh.Name
+		if name == "" {
+			name = wh.URL
+		}
+		resp, err := c.call(ctx, wh, body)
+		if err != nil {
+			if wh.FailurePolicy == FailOpen {
+				log.Printf("[Admission] webhook %s unreachable, failing open: %v", name, err)
+				last = Decision{Allowed: true, Webhook: name, Reason: fmt.Sprintf("webhook unreachable (fail-open): %v", err)}
+				continue
+			}
+			log.Printf("[Admission] webhook %s unreachable, failing closed: %v", name, err)
+			return Decision{Allowed: false, Webhook: name, Reason: fmt.Sprintf("webhook unreachable: %v", err)}
+		}
+		if !resp.Allowed {
+			log.Printf("[Admission] webhook %s denied update of %s to %s: %s", name, review.Container.Name, review.NewImage, resp.Reason)
+			return Decision{Allowed: false, Webhook: name, Reason: resp.Reason}
+		}
+		last = Decision{Allowed: true, Webhook: name, Reason: resp.Reason}
+	}
+	return last
+}
+
+// call POSTs the review to a single webhook and decodes its verdict.
+func (c *Controller) call(ctx context.Context, wh config.AdmissionWebhook, body []byte) (response, error) {
+	timeout := wh.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		return response{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return response{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return response{}, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	var out response
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return response{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return out, nil
+}
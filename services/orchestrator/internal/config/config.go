@@ -5,6 +5,7 @@ import (
 	"flag"
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -15,11 +16,47 @@ type GRPCServer struct {
 	RegistryMonitorAddr string `yaml:"registerAddress"`
 }
 
+// AdmissionWebhook configures an external endpoint that approves or denies updates.
+type AdmissionWebhook struct {
+	Name          string        `yaml:"name"`
+	URL           string        `yaml:"url"`
+	Timeout       time.Duration `yaml:"timeout"`       // defaults to 10s
+	FailurePolicy string        `yaml:"failurePolicy"` // "fail-open" or "fail-closed" (default)
+}
+
+// Admission configures the webhooks consulted before an update is dispatched.
+type Admission struct {
+	Webhooks []AdmissionWebhook `yaml:"webhooks"`
+}
+
+// Updates configures when planned updates may be dispatched.
+type Updates struct {
+	Window   string        `yaml:"window"`   // local time window such as "02:00-05:00"; empty allows any time
+	Cooldown time.Duration `yaml:"cooldown"` // minimum time between two updates of the same container
+	// PinOnRollback pins a container after a manual rollback so automatic updates do not re-apply the bad version.
+	PinOnRollback bool `yaml:"pin_on_rollback"`
+	// FailureBackoff is the wait after the first failed update to a digest; it doubles per failure up to MaxBackoff.
+	FailureBackoff time.Duration `yaml:"failure_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	// QuarantineAfter is the number of failures after which a digest is skipped until cleared.
+	QuarantineAfter int `yaml:"quarantine_after"`
+}
+
+// Policies configures the declarative policy file reconciled into the database.
+type Policies struct {
+	Path         string        `yaml:"path"`          // YAML file or directory of *.yaml files; empty disables it
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the file is checked for changes, defaults to 10s
+	Enforce      bool          `yaml:"enforce"`       // drop watch states set in the TUI that contradict the file
+}
+
 // Config holds all configuration for the application.
 type Config struct {
 	Env         string `yaml:"env" env:"ENV" env-required:"true"`
 	DataBaseURL string `yaml:"DataBaseURL" env-required:"true"`
 	GRPCServer  `yaml:"gRPCServer"`
+	Admission   Admission `yaml:"admission"`
+	Updates     Updates   `yaml:"updates"`
+	Policies    Policies  `yaml:"policies"`
 }
 
 // MustLoad loads the configuration from environment variables and panics if it fails.
@@ -0,0 +1,104 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: admission.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getLatestAdmissionDecisionsForHost = `-- name: GetLatestAdmissionDecisionsForHost :many
+SELECT DISTINCT ON (container_name) id, host_id, container_uid, container_name, current_image, new_image, webhook, allowed, reason, created_at
+FROM admission_decisions
+WHERE host_id = $1
+ORDER BY container_name, created_at DESC
+`
+
+// Retrieves the most recent admission decision for each container on a host.
+func (q *Queries) GetLatestAdmissionDecisionsForHost(ctx context.Context, hostID pgtype.UUID) ([]AdmissionDecision, error) {
+	rows, err := q.db.Query(ctx, getLatestAdmissionDecisionsForHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AdmissionDecision
+	for rows.Next() {
+		var i AdmissionDecision
+		if err := rows.Scan(
+			&i.ID,
+			&i.HostID,
+			&i.ContainerUid,
+			&i.ContainerName,
+			&i.CurrentImage,
+			&i.NewImage,
+			&i.Webhook,
+			&i.Allowed,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAdmissionDecision = `-- name: InsertAdmissionDecision :one
+INSERT INTO admission_decisions (
+  host_id,
+  container_uid,
+  container_name,
+  current_image,
+  new_image,
+  webhook,
+  allowed,
+  reason
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, host_id, container_uid, container_name, current_image, new_image, webhook, allowed, reason, created_at
+`
+
+type InsertAdmissionDecisionParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerUid  string      `json:"container_uid"`
+	ContainerName string      `json:"container_name"`
+	CurrentImage  string      `json:"current_image"`
+	NewImage      string      `json:"new_image"`
+	Webhook       string      `json:"webhook"`
+	Allowed       bool        `json:"allowed"`
+	Reason        pgtype.Text `json:"reason"`
+}
+
+// Records the outcome of an admission review for a planned update.
+func (q *Queries) InsertAdmissionDecision(ctx context.Context, arg InsertAdmissionDecisionParams) (AdmissionDecision, error) {
+	row := q.db.QueryRow(ctx, insertAdmissionDecision,
+		arg.HostID,
+		arg.ContainerUid,
+		arg.ContainerName,
+		arg.CurrentImage,
+		arg.NewImage,
+		arg.Webhook,
+		arg.Allowed,
+		arg.Reason,
+	)
+	var i AdmissionDecision
+	err := row.Scan(
+		&i.ID,
+		&i.HostID,
+		&i.ContainerUid,
+		&i.ContainerName,
+		&i.CurrentImage,
+		&i.NewImage,
+		&i.Webhook,
+		&i.Allowed,
+		&i.Reason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,120 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: available_updates.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearAvailableUpdate = `-- name: ClearAvailableUpdate :exec
+DELETE FROM available_updates
+WHERE host_id = $1 AND container_name = $2
+`
+
+type ClearAvailableUpdateParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+}
+
+// Forgets the update found for a container once it has been applied.
+func (q *Queries) ClearAvailableUpdate(ctx context.Context, arg ClearAvailableUpdateParams) error {
+	_, err := q.db.Exec(ctx, clearAvailableUpdate, arg.HostID, arg.ContainerName)
+	return err
+}
+
+const getAvailableUpdatesForHost = `-- name: GetAvailableUpdatesForHost :many
+SELECT host_id, container_name, current_image, image, digest, description, detected_at, last_checked_at FROM available_updates
+WHERE host_id = $1
+`
+
+// Retrieves the updates found for the containers on a host.
+func (q *Queries) GetAvailableUpdatesForHost(ctx context.Context, hostID pgtype.UUID) ([]AvailableUpdate, error) {
+	rows, err := q.db.Query(ctx, getAvailableUpdatesForHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AvailableUpdate
+	for rows.Next() {
+		var i AvailableUpdate
+		if err := rows.Scan(
+			&i.HostID,
+			&i.ContainerName,
+			&i.CurrentImage,
+			&i.Image,
+			&i.Digest,
+			&i.Description,
+			&i.DetectedAt,
+			&i.LastCheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordAvailableUpdate = `-- name: RecordAvailableUpdate :one
+INSERT INTO available_updates (
+  host_id,
+  container_name,
+  current_image,
+  image,
+  digest,
+  description
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET
+  detected_at = CASE
+    WHEN available_updates.image <> EXCLUDED.image OR available_updates.digest <> EXCLUDED.digest
+      OR available_updates.current_image <> EXCLUDED.current_image THEN now()
+    ELSE available_updates.detected_at
+  END,
+  current_image = EXCLUDED.current_image,
+  image = EXCLUDED.image,
+  digest = EXCLUDED.digest,
+  description = EXCLUDED.description,
+  last_checked_at = now()
+RETURNING host_id, container_name, current_image, image, digest, description, detected_at, last_checked_at
+`
+
+type RecordAvailableUpdateParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+	CurrentImage  string      `json:"current_image"`
+	Image         string      `json:"image"`
+	Digest        string      `json:"digest"`
+	Description   pgtype.Text `json:"description"`
+}
+
+// Stores an update found for a container. detected_at only moves when a different image or digest is found.
+func (q *Queries) RecordAvailableUpdate(ctx context.Context, arg RecordAvailableUpdateParams) (AvailableUpdate, error) {
+	row := q.db.QueryRow(ctx, recordAvailableUpdate,
+		arg.HostID,
+		arg.ContainerName,
+		arg.CurrentImage,
+		arg.Image,
+		arg.Digest,
+		arg.Description,
+	)
+	var i AvailableUpdate
+	err := row.Scan(
+		&i.HostID,
+		&i.ContainerName,
+		&i.CurrentImage,
+		&i.Image,
+		&i.Digest,
+		&i.Description,
+		&i.DetectedAt,
+		&i.LastCheckedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: container_overrides.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearWatchOverride = `-- name: ClearWatchOverride :exec
+UPDATE container_overrides SET watch = NULL, updated_at = now()
+WHERE host_id = $1 AND container_name = $2
+`
+
+type ClearWatchOverrideParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+}
+
+// Drops the watch state set by hand for a container.
+func (q *Queries) ClearWatchOverride(ctx context.Context, arg ClearWatchOverrideParams) error {
+	_, err := q.db.Exec(ctx, clearWatchOverride, arg.HostID, arg.ContainerName)
+	return err
+}
+
+const getAllContainerOverrides = `-- name: GetAllContainerOverrides :many
+SELECT host_id, container_name, watch, updated_at FROM container_overrides
+`
+
+// Retrieves the overrides of all containers on all hosts.
+func (q *Queries) GetAllContainerOverrides(ctx context.Context) ([]ContainerOverride, error) {
+	rows, err := q.db.Query(ctx, getAllContainerOverrides)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContainerOverride
+	for rows.Next() {
+		var i ContainerOverride
+		if err := rows.Scan(
+			&i.HostID,
+			&i.ContainerName,
+			&i.Watch,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContainerOverridesForHost = `-- name: GetContainerOverridesForHost :many
+SELECT host_id, container_name, watch, updated_at FROM container_overrides WHERE host_id = $1
+`
+
+// Retrieves the overrides of all containers on a host.
+func (q *Queries) GetContainerOverridesForHost(ctx context.Context, hostID pgtype.UUID) ([]ContainerOverride, error) {
+	rows, err := q.db.Query(ctx, getContainerOverridesForHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContainerOverride
+	for rows.Next() {
+		var i ContainerOverride
+		if err := rows.Scan(
+			&i.HostID,
+			&i.ContainerName,
+			&i.Watch,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setWatchOverride = `-- name: SetWatchOverride :exec
+INSERT INTO container_overrides (host_id, container_name, watch)
+SELECT id, $1, $2 FROM hosts WHERE agent_id = $3
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET watch = EXCLUDED.watch, updated_at = now()
+`
+
+type SetWatchOverrideParams struct {
+	ContainerName string      `json:"container_name"`
+	Watch         pgtype.Bool `json:"watch"`
+	AgentID       string      `json:"agent_id"`
+}
+
+// Records a watch state set by hand for a container by its name and the agent ID of its host.
+func (q *Queries) SetWatchOverride(ctx context.Context, arg SetWatchOverrideParams) error {
+	_, err := q.db.Exec(ctx, setWatchOverride, arg.ContainerName, arg.Watch, arg.AgentID)
+	return err
+}
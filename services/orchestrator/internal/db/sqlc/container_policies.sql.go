@@ -0,0 +1,163 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: container_policies.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const deleteAllContainerPolicies = `-- name: DeleteAllContainerPolicies :exec
+DELETE FROM container_policies
+`
+
+// Removes all policies assigned by the policy file before they are reconciled again.
+func (q *Queries) DeleteAllContainerPolicies(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteAllContainerPolicies)
+	return err
+}
+
+const getAllContainerPolicies = `-- name: GetAllContainerPolicies :many
+SELECT host_id, container_name, watch, strategy, tag_pattern, schedule, approval_required, notify_only, rollout_group, rules, updated_at, depends_on, hooks, health FROM container_policies
+`
+
+// Retrieves the policies assigned by the policy file to all containers.
+func (q *Queries) GetAllContainerPolicies(ctx context.Context) ([]ContainerPolicy, error) {
+	rows, err := q.db.Query(ctx, getAllContainerPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContainerPolicy
+	for rows.Next() {
+		var i ContainerPolicy
+		if err := rows.Scan(
+			&i.HostID,
+			&i.ContainerName,
+			&i.Watch,
+			&i.Strategy,
+			&i.TagPattern,
+			&i.Schedule,
+			&i.ApprovalRequired,
+			&i.NotifyOnly,
+			&i.RolloutGroup,
+			&i.Rules,
+			&i.UpdatedAt,
+			&i.DependsOn,
+			&i.Hooks,
+			&i.Health,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getContainerPolicy = `-- name: GetContainerPolicy :one
+SELECT host_id, container_name, watch, strategy, tag_pattern, schedule, approval_required, notify_only, rollout_group, rules, updated_at, depends_on, hooks, health FROM container_policies
+WHERE host_id = $1 AND container_name = $2
+`
+
+type GetContainerPolicyParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+}
+
+// Retrieves the policy the policy file assigns to a container.
+func (q *Queries) GetContainerPolicy(ctx context.Context, arg GetContainerPolicyParams) (ContainerPolicy, error) {
+	row := q.db.QueryRow(ctx, getContainerPolicy, arg.HostID, arg.ContainerName)
+	var i ContainerPolicy
+	err := row.Scan(
+		&i.HostID,
+		&i.ContainerName,
+		&i.Watch,
+		&i.Strategy,
+		&i.TagPattern,
+		&i.Schedule,
+		&i.ApprovalRequired,
+		&i.NotifyOnly,
+		&i.RolloutGroup,
+		&i.Rules,
+		&i.UpdatedAt,
+		&i.DependsOn,
+		&i.Hooks,
+		&i.Health,
+	)
+	return i, err
+}
+
+const upsertContainerPolicy = `-- name: UpsertContainerPolicy :exec
+INSERT INTO container_policies (
+  host_id,
+  container_name,
+  watch,
+  strategy,
+  tag_pattern,
+  schedule,
+  approval_required,
+  notify_only,
+  rollout_group,
+  rules,
+  depends_on,
+  hooks,
+  health
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET
+  watch = EXCLUDED.watch,
+  strategy = EXCLUDED.strategy,
+  tag_pattern = EXCLUDED.tag_pattern,
+  schedule = EXCLUDED.schedule,
+  approval_required = EXCLUDED.approval_required,
+  notify_only = EXCLUDED.notify_only,
+  rollout_group = EXCLUDED.rollout_group,
+  rules = EXCLUDED.rules,
+  depends_on = EXCLUDED.depends_on,
+  hooks = EXCLUDED.hooks,
+  health = EXCLUDED.health,
+  updated_at = now()
+`
+
+type UpsertContainerPolicyParams struct {
+	HostID           pgtype.UUID `json:"host_id"`
+	ContainerName    string      `json:"container_name"`
+	Watch            pgtype.Bool `json:"watch"`
+	Strategy         pgtype.Text `json:"strategy"`
+	TagPattern       pgtype.Text `json:"tag_pattern"`
+	Schedule         pgtype.Text `json:"schedule"`
+	ApprovalRequired pgtype.Bool `json:"approval_required"`
+	NotifyOnly       pgtype.Bool `json:"notify_only"`
+	RolloutGroup     pgtype.Text `json:"rollout_group"`
+	Rules            string      `json:"rules"`
+	DependsOn        []string    `json:"depends_on"`
+	Hooks            []byte      `json:"hooks"`
+	Health           []byte      `json:"health"`
+}
+
+// Stores the policy the policy file assigns to a container.
+func (q *Queries) UpsertContainerPolicy(ctx context.Context, arg UpsertContainerPolicyParams) error {
+	_, err := q.db.Exec(ctx, upsertContainerPolicy,
+		arg.HostID,
+		arg.ContainerName,
+		arg.Watch,
+		arg.Strategy,
+		arg.TagPattern,
+		arg.Schedule,
+		arg.ApprovalRequired,
+		arg.NotifyOnly,
+		arg.RolloutGroup,
+		arg.Rules,
+		arg.DependsOn,
+		arg.Hooks,
+		arg.Health,
+	)
+	return err
+}
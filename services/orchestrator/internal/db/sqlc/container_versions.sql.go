@@ -0,0 +1,144 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: container_versions.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const getContainerVersion = `-- name: GetContainerVersion :one
+SELECT host_id, container_name, current_image, current_digest, previous_image, previous_digest, pinned, updated_at FROM container_versions
+WHERE host_id = $1 AND container_name = $2
+`
+
+type GetContainerVersionParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+}
+
+// Retrieves the recorded versions of a container on a host.
+func (q *Queries) GetContainerVersion(ctx context.Context, arg GetContainerVersionParams) (ContainerVersion, error) {
+	row := q.db.QueryRow(ctx, getContainerVersion, arg.HostID, arg.ContainerName)
+	var i ContainerVersion
+	err := row.Scan(
+		&i.HostID,
+		&i.ContainerName,
+		&i.CurrentImage,
+		&i.CurrentDigest,
+		&i.PreviousImage,
+		&i.PreviousDigest,
+		&i.Pinned,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getContainerVersionsForHost = `-- name: GetContainerVersionsForHost :many
+SELECT host_id, container_name, current_image, current_digest, previous_image, previous_digest, pinned, updated_at FROM container_versions WHERE host_id = $1
+`
+
+// Retrieves the recorded versions of all containers on a host.
+func (q *Queries) GetContainerVersionsForHost(ctx context.Context, hostID pgtype.UUID) ([]ContainerVersion, error) {
+	rows, err := q.db.Query(ctx, getContainerVersionsForHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ContainerVersion
+	for rows.Next() {
+		var i ContainerVersion
+		if err := rows.Scan(
+			&i.HostID,
+			&i.ContainerName,
+			&i.CurrentImage,
+			&i.CurrentDigest,
+			&i.PreviousImage,
+			&i.PreviousDigest,
+			&i.Pinned,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordContainerVersion = `-- name: RecordContainerVersion :one
+INSERT INTO container_versions (
+  host_id,
+  container_name,
+  current_image,
+  current_digest,
+  previous_image,
+  previous_digest
+) VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET
+  current_image = EXCLUDED.current_image,
+  current_digest = EXCLUDED.current_digest,
+  previous_image = EXCLUDED.previous_image,
+  previous_digest = EXCLUDED.previous_digest,
+  updated_at = now()
+RETURNING host_id, container_name, current_image, current_digest, previous_image, previous_digest, pinned, updated_at
+`
+
+type RecordContainerVersionParams struct {
+	HostID         pgtype.UUID `json:"host_id"`
+	ContainerName  string      `json:"container_name"`
+	CurrentImage   pgtype.Text `json:"current_image"`
+	CurrentDigest  pgtype.Text `json:"current_digest"`
+	PreviousImage  pgtype.Text `json:"previous_image"`
+	PreviousDigest pgtype.Text `json:"previous_digest"`
+}
+
+// Records the image a container now runs and the one it replaced after a successful update.
+func (q *Queries) RecordContainerVersion(ctx context.Context, arg RecordContainerVersionParams) (ContainerVersion, error) {
+	row := q.db.QueryRow(ctx, recordContainerVersion,
+		arg.HostID,
+		arg.ContainerName,
+		arg.CurrentImage,
+		arg.CurrentDigest,
+		arg.PreviousImage,
+		arg.PreviousDigest,
+	)
+	var i ContainerVersion
+	err := row.Scan(
+		&i.HostID,
+		&i.ContainerName,
+		&i.CurrentImage,
+		&i.CurrentDigest,
+		&i.PreviousImage,
+		&i.PreviousDigest,
+		&i.Pinned,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const setContainerPinned = `-- name: SetContainerPinned :exec
+INSERT INTO container_versions (host_id, container_name, pinned)
+VALUES ($1, $2, $3)
+ON CONFLICT (host_id, container_name)
+DO UPDATE SET pinned = EXCLUDED.pinned, updated_at = now()
+`
+
+type SetContainerPinnedParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+	Pinned        bool        `json:"pinned"`
+}
+
+// Pins or unpins a container. Pinned containers are skipped by automatic updates.
+func (q *Queries) SetContainerPinned(ctx context.Context, arg SetContainerPinnedParams) error {
+	_, err := q.db.Exec(ctx, setContainerPinned, arg.HostID, arg.ContainerName, arg.Pinned)
+	return err
+}
@@ -11,6 +11,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteContainersForHost = `-- name: DeleteContainersForHost :exec
+DELETE FROM containers
+WHERE host_id = $1 AND container_uid = ANY($2::text[])
+`
+
+type DeleteContainersForHostParams struct {
+	HostID  pgtype.UUID `json:"host_id"`
+	Column2 []string    `json:"column_2"`
+}
+
+// Deletes the containers of a host with the given UIDs.
+func (q *Queries) DeleteContainersForHost(ctx context.Context, arg DeleteContainersForHostParams) error {
+	_, err := q.db.Exec(ctx, deleteContainersForHost, arg.HostID, arg.Column2)
+	return err
+}
+
 const deleteStaleContainersForHost = `-- name: DeleteStaleContainersForHost :exec
 DELETE FROM containers
 WHERE host_id = $1 AND container_uid <> ALL($2::text[])
@@ -27,8 +43,53 @@ func (q *Queries) DeleteStaleContainersForHost(ctx context.Context, arg DeleteSt
 	return err
 }
 
+const getAllContainers = `-- name: GetAllContainers :many
+SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at, labels, image_digest, compose_project, compose_service, depends_on, spec, state, health FROM containers
+`
+
+// Retrieves all containers on all hosts
+func (q *Queries) GetAllContainers(ctx context.Context) ([]Container, error) {
+	rows, err := q.db.Query(ctx, getAllContainers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Container
+	for rows.Next() {
+		var i Container
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContainerUid,
+			&i.HostID,
+			&i.Name,
+			&i.Image,
+			&i.Ports,
+			&i.EnvVars,
+			&i.Volumes,
+			&i.Network,
+			&i.Watch,
+			&i.CreatedAt,
+			&i.Labels,
+			&i.ImageDigest,
+			&i.ComposeProject,
+			&i.ComposeService,
+			&i.DependsOn,
+			&i.Spec,
+			&i.State,
+			&i.Health,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllContainersonHost = `-- name: GetAllContainersonHost :many
-SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at FROM containers WHERE host_id = $1
+SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at, labels, image_digest, compose_project, compose_service, depends_on, spec, state, health FROM containers WHERE host_id = $1
 `
 
 // Retrieves all containers associated with a given host ID
@@ -53,6 +114,14 @@ func (q *Queries) GetAllContainersonHost(ctx context.Context, hostID pgtype.UUID
 			&i.Network,
 			&i.Watch,
 			&i.CreatedAt,
+			&i.Labels,
+			&i.ImageDigest,
+			&i.ComposeProject,
+			&i.ComposeService,
+			&i.DependsOn,
+			&i.Spec,
+			&i.State,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -65,7 +134,7 @@ func (q *Queries) GetAllContainersonHost(ctx context.Context, hostID pgtype.UUID
 }
 
 const getContainerbyContainerUID = `-- name: GetContainerbyContainerUID :one
-SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at FROM containers WHERE container_uid = $1
+SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at, labels, image_digest, compose_project, compose_service, depends_on, spec, state, health FROM containers WHERE container_uid = $1
 `
 
 // Retrieves a container by its UID
@@ -84,12 +153,20 @@ func (q *Queries) GetContainerbyContainerUID(ctx context.Context, containerUid s
 		&i.Network,
 		&i.Watch,
 		&i.CreatedAt,
+		&i.Labels,
+		&i.ImageDigest,
+		&i.ComposeProject,
+		&i.ComposeService,
+		&i.DependsOn,
+		&i.Spec,
+		&i.State,
+		&i.Health,
 	)
 	return i, err
 }
 
 const getHostbyContainerUID = `-- name: GetHostbyContainerUID :one
-SELECT h.id, h.mac_address, h.hostname, h.ip_address, h.last_heartbeat, h.created_at
+SELECT h.id, h.mac_address, h.hostname, h.ip_address, h.last_heartbeat, h.created_at, h.agent_id, h.inventory_hash, h.online
 FROM hosts h
 JOIN containers c ON h.id = c.host_id
 WHERE c.container_uid = $1
@@ -106,12 +183,67 @@ func (q *Queries) GetHostbyContainerUID(ctx context.Context, containerUid string
 		&i.IpAddress,
 		&i.LastHeartbeat,
 		&i.CreatedAt,
+		&i.AgentID,
+		&i.InventoryHash,
+		&i.Online,
 	)
 	return i, err
 }
 
+const getProjectContainers = `-- name: GetProjectContainers :many
+SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at, labels, image_digest, compose_project, compose_service, depends_on, spec, state, health FROM containers
+WHERE host_id = $1 AND compose_project = $2
+ORDER BY name
+`
+
+type GetProjectContainersParams struct {
+	HostID         pgtype.UUID `json:"host_id"`
+	ComposeProject pgtype.Text `json:"compose_project"`
+}
+
+// Retrieves the containers of a Docker Compose project on a host.
+func (q *Queries) GetProjectContainers(ctx context.Context, arg GetProjectContainersParams) ([]Container, error) {
+	rows, err := q.db.Query(ctx, getProjectContainers, arg.HostID, arg.ComposeProject)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Container
+	for rows.Next() {
+		var i Container
+		if err := rows.Scan(
+			&i.ID,
+			&i.ContainerUid,
+			&i.HostID,
+			&i.Name,
+			&i.Image,
+			&i.Ports,
+			&i.EnvVars,
+			&i.Volumes,
+			&i.Network,
+			&i.Watch,
+			&i.CreatedAt,
+			&i.Labels,
+			&i.ImageDigest,
+			&i.ComposeProject,
+			&i.ComposeService,
+			&i.DependsOn,
+			&i.Spec,
+			&i.State,
+			&i.Health,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getallContainersWhereWatched = `-- name: GetallContainersWhereWatched :many
-SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at FROM containers WHERE watch = TRUE
+SELECT id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at, labels, image_digest, compose_project, compose_service, depends_on, spec, state, health FROM containers WHERE watch = TRUE
 `
 
 // Retrieves all containers where watched is true
@@ -136,6 +268,14 @@ func (q *Queries) GetallContainersWhereWatched(ctx context.Context) ([]Container
 			&i.Network,
 			&i.Watch,
 			&i.CreatedAt,
+			&i.Labels,
+			&i.ImageDigest,
+			&i.ComposeProject,
+			&i.ComposeService,
+			&i.DependsOn,
+			&i.Spec,
+			&i.State,
+			&i.Health,
 		); err != nil {
 			return nil, err
 		}
@@ -156,8 +296,16 @@ INSERT INTO containers (
   ports,
   env_vars,
   volumes,
-  network
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+  network,
+  labels,
+  image_digest,
+  compose_project,
+  compose_service,
+  depends_on,
+  spec,
+  state,
+  health
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 ON CONFLICT (container_uid)
 DO UPDATE SET
   host_id = EXCLUDED.host_id,
@@ -166,19 +314,35 @@ DO UPDATE SET
   ports = EXCLUDED.ports,
   env_vars = EXCLUDED.env_vars,
   volumes = EXCLUDED.volumes,
-  network = EXCLUDED.network
-RETURNING id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at
+  network = EXCLUDED.network,
+  labels = EXCLUDED.labels,
+  image_digest = EXCLUDED.image_digest,
+  compose_project = EXCLUDED.compose_project,
+  compose_service = EXCLUDED.compose_service,
+  depends_on = EXCLUDED.depends_on,
+  spec = EXCLUDED.spec,
+  state = EXCLUDED.state,
+  health = EXCLUDED.health
+RETURNING id, container_uid, host_id, name, image, ports, env_vars, volumes, network, watch, created_at, labels, image_digest, compose_project, compose_service, depends_on, spec, state, health
 `
 
 type InsertContainerParams struct {
-	ContainerUid string      `json:"container_uid"`
-	HostID       pgtype.UUID `json:"host_id"`
-	Name         string      `json:"name"`
-	Image        string      `json:"image"`
-	Ports        []byte      `json:"ports"`
-	EnvVars      []string    `json:"env_vars"`
-	Volumes      []string    `json:"volumes"`
-	Network      pgtype.Text `json:"network"`
+	ContainerUid   string      `json:"container_uid"`
+	HostID         pgtype.UUID `json:"host_id"`
+	Name           string      `json:"name"`
+	Image          string      `json:"image"`
+	Ports          []byte      `json:"ports"`
+	EnvVars        []string    `json:"env_vars"`
+	Volumes        []string    `json:"volumes"`
+	Network        pgtype.Text `json:"network"`
+	Labels         []byte      `json:"labels"`
+	ImageDigest    pgtype.Text `json:"image_digest"`
+	ComposeProject pgtype.Text `json:"compose_project"`
+	ComposeService pgtype.Text `json:"compose_service"`
+	DependsOn      []string    `json:"depends_on"`
+	Spec           []byte      `json:"spec"`
+	State          pgtype.Text `json:"state"`
+	Health         pgtype.Text `json:"health"`
 }
 
 func (q *Queries) InsertContainer(ctx context.Context, arg InsertContainerParams) (Container, error) {
@@ -191,6 +355,14 @@ func (q *Queries) InsertContainer(ctx context.Context, arg InsertContainerParams
 		arg.EnvVars,
 		arg.Volumes,
 		arg.Network,
+		arg.Labels,
+		arg.ImageDigest,
+		arg.ComposeProject,
+		arg.ComposeService,
+		arg.DependsOn,
+		arg.Spec,
+		arg.State,
+		arg.Health,
 	)
 	var i Container
 	err := row.Scan(
@@ -205,6 +377,14 @@ func (q *Queries) InsertContainer(ctx context.Context, arg InsertContainerParams
 		&i.Network,
 		&i.Watch,
 		&i.CreatedAt,
+		&i.Labels,
+		&i.ImageDigest,
+		&i.ComposeProject,
+		&i.ComposeService,
+		&i.DependsOn,
+		&i.Spec,
+		&i.State,
+		&i.Health,
 	)
 	return i, err
 }
@@ -212,17 +392,17 @@ func (q *Queries) InsertContainer(ctx context.Context, arg InsertContainerParams
 const setWatchStatus = `-- name: SetWatchStatus :exec
 UPDATE containers
 SET watch = $1
-WHERE name = $2 AND host_id = (SELECT id FROM hosts WHERE  mac_address = $3)
+WHERE name = $2 AND host_id = (SELECT id FROM hosts WHERE agent_id = $3)
 `
 
 type SetWatchStatusParams struct {
-	Watch      pgtype.Bool `json:"watch"`
-	Name       string      `json:"name"`
-	MacAddress string      `json:"mac_address"`
+	Watch   pgtype.Bool `json:"watch"`
+	Name    string      `json:"name"`
+	AgentID string      `json:"agent_id"`
 }
 
-// Updates the watch status of a container by its name and macid on the host
+// Updates the watch status of a container by its name and the agent ID of its host
 func (q *Queries) SetWatchStatus(ctx context.Context, arg SetWatchStatusParams) error {
-	_, err := q.db.Exec(ctx, setWatchStatus, arg.Watch, arg.Name, arg.MacAddress)
+	_, err := q.db.Exec(ctx, setWatchStatus, arg.Watch, arg.Name, arg.AgentID)
 	return err
 }
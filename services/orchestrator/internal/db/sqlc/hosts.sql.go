@@ -11,18 +11,30 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const deleteHostByMacAddress = `-- name: DeleteHostByMacAddress :exec
-DELETE FROM hosts
-WHERE mac_address = $1
+const adoptLegacyHost = `-- name: AdoptLegacyHost :execrows
+UPDATE hosts SET agent_id = $1
+WHERE hosts.agent_id = $2
+  AND hosts.mac_address = $2
+  AND NOT EXISTS (SELECT 1 FROM hosts h WHERE h.agent_id = $1)
 `
 
-func (q *Queries) DeleteHostByMacAddress(ctx context.Context, macAddress string) error {
-	_, err := q.db.Exec(ctx, deleteHostByMacAddress, macAddress)
-	return err
+type AdoptLegacyHostParams struct {
+	AgentID    string `json:"agent_id"`
+	MacAddress string `json:"mac_address"`
+}
+
+// Hands the row of a host registered before agent IDs existed, which kept its MAC address as
+// agent ID, to the generated ID its upgraded agent registers with.
+func (q *Queries) AdoptLegacyHost(ctx context.Context, arg AdoptLegacyHostParams) (int64, error) {
+	result, err := q.db.Exec(ctx, adoptLegacyHost, arg.AgentID, arg.MacAddress)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getAllHosts = `-- name: GetAllHosts :many
-SELECT id, mac_address, hostname, ip_address, last_heartbeat, created_at FROM hosts
+SELECT id, mac_address, hostname, ip_address, last_heartbeat, created_at, agent_id, inventory_hash, online FROM hosts
 `
 
 // Retrieves all hosts from the database.
@@ -42,6 +54,9 @@ func (q *Queries) GetAllHosts(ctx context.Context) ([]Host, error) {
 			&i.IpAddress,
 			&i.LastHeartbeat,
 			&i.CreatedAt,
+			&i.AgentID,
+			&i.InventoryHash,
+			&i.Online,
 		); err != nil {
 			return nil, err
 		}
@@ -53,13 +68,13 @@ func (q *Queries) GetAllHosts(ctx context.Context) ([]Host, error) {
 	return items, nil
 }
 
-const getHostByMacAddress = `-- name: GetHostByMacAddress :one
-SELECT id, mac_address, hostname, ip_address, last_heartbeat, created_at FROM hosts WHERE mac_address = $1
+const getHostByAgentID = `-- name: GetHostByAgentID :one
+SELECT id, mac_address, hostname, ip_address, last_heartbeat, created_at, agent_id, inventory_hash, online FROM hosts WHERE agent_id = $1
 `
 
-// Retrieves a host by its mac_address.
-func (q *Queries) GetHostByMacAddress(ctx context.Context, macAddress string) (Host, error) {
-	row := q.db.QueryRow(ctx, getHostByMacAddress, macAddress)
+// Retrieves a host by the ID of its agent.
+func (q *Queries) GetHostByAgentID(ctx context.Context, agentID string) (Host, error) {
+	row := q.db.QueryRow(ctx, getHostByAgentID, agentID)
 	var i Host
 	err := row.Scan(
 		&i.ID,
@@ -68,34 +83,48 @@ func (q *Queries) GetHostByMacAddress(ctx context.Context, macAddress string) (H
 		&i.IpAddress,
 		&i.LastHeartbeat,
 		&i.CreatedAt,
+		&i.AgentID,
+		&i.InventoryHash,
+		&i.Online,
 	)
 	return i, err
 }
 
 const insertHost = `-- name: InsertHost :one
 INSERT INTO hosts (
+  agent_id,
   mac_address,
   hostname,
-  ip_address
+  ip_address,
+  online
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, TRUE
 )
-ON CONFLICT (mac_address)
+ON CONFLICT (agent_id)
 DO UPDATE SET
+  mac_address = EXCLUDED.mac_address,
   hostname = EXCLUDED.hostname,
-  ip_address = EXCLUDED.ip_address
-RETURNING id, mac_address, hostname, ip_address, last_heartbeat, created_at
+  ip_address = EXCLUDED.ip_address,
+  online = TRUE
+RETURNING id, mac_address, hostname, ip_address, last_heartbeat, created_at, agent_id, inventory_hash, online
 `
 
 type InsertHostParams struct {
+	AgentID    string `json:"agent_id"`
 	MacAddress string `json:"mac_address"`
 	Hostname   string `json:"hostname"`
 	IpAddress  string `json:"ip_address"`
 }
 
-// Inserts a new host or updates an existing one based on the MAC address.
+// Inserts a new host or updates the attributes of an existing one based on the agent ID.
+// A registering host is online.
 func (q *Queries) InsertHost(ctx context.Context, arg InsertHostParams) (Host, error) {
-	row := q.db.QueryRow(ctx, insertHost, arg.MacAddress, arg.Hostname, arg.IpAddress)
+	row := q.db.QueryRow(ctx, insertHost,
+		arg.AgentID,
+		arg.MacAddress,
+		arg.Hostname,
+		arg.IpAddress,
+	)
 	var i Host
 	err := row.Scan(
 		&i.ID,
@@ -104,12 +133,50 @@ func (q *Queries) InsertHost(ctx context.Context, arg InsertHostParams) (Host, e
 		&i.IpAddress,
 		&i.LastHeartbeat,
 		&i.CreatedAt,
+		&i.AgentID,
+		&i.InventoryHash,
+		&i.Online,
 	)
 	return i, err
 }
 
+const markAllHostsOffline = `-- name: MarkAllHostsOffline :exec
+UPDATE hosts SET online = FALSE
+`
+
+// Marks every host offline; no agent is connected when the orchestrator starts.
+func (q *Queries) MarkAllHostsOffline(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, markAllHostsOffline)
+	return err
+}
+
+const setHostInventoryHash = `-- name: SetHostInventoryHash :exec
+UPDATE hosts SET inventory_hash = $2 WHERE id = $1
+`
+
+type SetHostInventoryHashParams struct {
+	ID            pgtype.UUID `json:"id"`
+	InventoryHash pgtype.Text `json:"inventory_hash"`
+}
+
+// Records the hash of the inventory an agent reported for a host.
+func (q *Queries) SetHostInventoryHash(ctx context.Context, arg SetHostInventoryHashParams) error {
+	_, err := q.db.Exec(ctx, setHostInventoryHash, arg.ID, arg.InventoryHash)
+	return err
+}
+
+const setHostOffline = `-- name: SetHostOffline :exec
+UPDATE hosts SET online = FALSE WHERE agent_id = $1
+`
+
+// Marks a host offline when the command stream of its agent closes.
+func (q *Queries) SetHostOffline(ctx context.Context, agentID string) error {
+	_, err := q.db.Exec(ctx, setHostOffline, agentID)
+	return err
+}
+
 const updateHostLastHeartbeat = `-- name: UpdateHostLastHeartbeat :one
-UPDATE hosts SET last_heartbeat = NOW() WHERE id = $1 RETURNING id, mac_address, hostname, ip_address, last_heartbeat, created_at
+UPDATE hosts SET last_heartbeat = NOW() WHERE id = $1 RETURNING id, mac_address, hostname, ip_address, last_heartbeat, created_at, agent_id, inventory_hash, online
 `
 
 // Updates the last heartbeat timestamp for a host identified by id.
@@ -123,6 +190,9 @@ func (q *Queries) UpdateHostLastHeartbeat(ctx context.Context, id pgtype.UUID) (
 		&i.IpAddress,
 		&i.LastHeartbeat,
 		&i.CreatedAt,
+		&i.AgentID,
+		&i.InventoryHash,
+		&i.Online,
 	)
 	return i, err
 }
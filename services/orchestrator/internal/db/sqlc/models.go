@@ -21,6 +21,11 @@ const (
 	UpdateStageCompleted   UpdateStage = "completed"
 	UpdateStageRollback    UpdateStage = "rollback"
 	UpdateStageFailed      UpdateStage = "failed"
+	UpdateStageHook        UpdateStage = "hook"
+	UpdateStageQueued      UpdateStage = "queued"
+	UpdateStageRejected    UpdateStage = "rejected"
+	UpdateStageCancelled   UpdateStage = "cancelled"
+	UpdateStagePreflight   UpdateStage = "preflight"
 )
 
 func (e *UpdateStage) Scan(src interface{}) error {
@@ -58,19 +63,94 @@ func (ns NullUpdateStage) Value() (driver.Value, error) {
 	return string(ns.UpdateStage), nil
 }
 
+type AdmissionDecision struct {
+	ID int32 `json:"id"`
+	// FK → hosts.id. Decision recorded for a container on this host.
+	HostID        pgtype.UUID        `json:"host_id"`
+	ContainerUid  string             `json:"container_uid"`
+	ContainerName string             `json:"container_name"`
+	CurrentImage  string             `json:"current_image"`
+	NewImage      string             `json:"new_image"`
+	Webhook       string             `json:"webhook"`
+	Allowed       bool               `json:"allowed"`
+	Reason        pgtype.Text        `json:"reason"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type AvailableUpdate struct {
+	// FK → hosts.id. Available update of a container on this host.
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+	// Image the container ran when the update was found.
+	CurrentImage string      `json:"current_image"`
+	Image        string      `json:"image"`
+	Digest       string      `json:"digest"`
+	Description  pgtype.Text `json:"description"`
+	// When this image and digest were first found.
+	DetectedAt    pgtype.Timestamptz `json:"detected_at"`
+	LastCheckedAt pgtype.Timestamptz `json:"last_checked_at"`
+}
+
 type Container struct {
 	ID           pgtype.UUID `json:"id"`
 	ContainerUid string      `json:"container_uid"`
 	// FK → hosts.id. A container belongs to a host.
-	HostID    pgtype.UUID        `json:"host_id"`
-	Name      string             `json:"name"`
-	Image     string             `json:"image"`
-	Ports     []byte             `json:"ports"`
-	EnvVars   []string           `json:"env_vars"`
-	Volumes   []string           `json:"volumes"`
-	Network   pgtype.Text        `json:"network"`
-	Watch     pgtype.Bool        `json:"watch"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	HostID         pgtype.UUID        `json:"host_id"`
+	Name           string             `json:"name"`
+	Image          string             `json:"image"`
+	Ports          []byte             `json:"ports"`
+	EnvVars        []string           `json:"env_vars"`
+	Volumes        []string           `json:"volumes"`
+	Network        pgtype.Text        `json:"network"`
+	Watch          pgtype.Bool        `json:"watch"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Labels         []byte             `json:"labels"`
+	ImageDigest    pgtype.Text        `json:"image_digest"`
+	ComposeProject pgtype.Text        `json:"compose_project"`
+	ComposeService pgtype.Text        `json:"compose_service"`
+	DependsOn      []string           `json:"depends_on"`
+	Spec           []byte             `json:"spec"`
+	State          pgtype.Text        `json:"state"`
+	Health         pgtype.Text        `json:"health"`
+}
+
+type ContainerOverride struct {
+	// FK → hosts.id. Overrides for a container on this host.
+	HostID        pgtype.UUID        `json:"host_id"`
+	ContainerName string             `json:"container_name"`
+	Watch         pgtype.Bool        `json:"watch"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ContainerPolicy struct {
+	// FK → hosts.id. Policy of a container on this host.
+	HostID           pgtype.UUID `json:"host_id"`
+	ContainerName    string      `json:"container_name"`
+	Watch            pgtype.Bool `json:"watch"`
+	Strategy         pgtype.Text `json:"strategy"`
+	TagPattern       pgtype.Text `json:"tag_pattern"`
+	Schedule         pgtype.Text `json:"schedule"`
+	ApprovalRequired pgtype.Bool `json:"approval_required"`
+	NotifyOnly       pgtype.Bool `json:"notify_only"`
+	RolloutGroup     pgtype.Text `json:"rollout_group"`
+	// Names of the policy file rules that matched, comma separated.
+	Rules     string             `json:"rules"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	DependsOn []string           `json:"depends_on"`
+	Hooks     []byte             `json:"hooks"`
+	Health    []byte             `json:"health"`
+}
+
+type ContainerVersion struct {
+	// FK → hosts.id. Versions of a container on this host.
+	HostID         pgtype.UUID        `json:"host_id"`
+	ContainerName  string             `json:"container_name"`
+	CurrentImage   pgtype.Text        `json:"current_image"`
+	CurrentDigest  pgtype.Text        `json:"current_digest"`
+	PreviousImage  pgtype.Text        `json:"previous_image"`
+	PreviousDigest pgtype.Text        `json:"previous_digest"`
+	Pinned         bool               `json:"pinned"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
 }
 
 type Host struct {
@@ -81,14 +161,30 @@ type Host struct {
 	IpAddress     string             `json:"ip_address"`
 	LastHeartbeat pgtype.Timestamptz `json:"last_heartbeat"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	AgentID       string             `json:"agent_id"`
+	InventoryHash pgtype.Text        `json:"inventory_hash"`
+	Online        bool               `json:"online"`
+}
+
+type UpdateFailure struct {
+	// FK → hosts.id. Failed updates of a container on this host.
+	HostID        pgtype.UUID        `json:"host_id"`
+	ContainerName string             `json:"container_name"`
+	Digest        string             `json:"digest"`
+	Image         string             `json:"image"`
+	Failures      int32              `json:"failures"`
+	LastError     pgtype.Text        `json:"last_error"`
+	LastFailureAt pgtype.Timestamptz `json:"last_failure_at"`
 }
 
 type UpdateStatus struct {
 	ID    int32  `json:"id"`
 	Image string `json:"image"`
 	// FK → hosts.id. Update status directly tied to a host.
-	HostID    pgtype.UUID        `json:"host_id"`
-	Stage     UpdateStage        `json:"stage"`
-	Logs      pgtype.Text        `json:"logs"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	HostID        pgtype.UUID        `json:"host_id"`
+	Stage         UpdateStage        `json:"stage"`
+	Logs          pgtype.Text        `json:"logs"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ContainerUid  pgtype.Text        `json:"container_uid"`
+	ContainerName pgtype.Text        `json:"container_name"`
 }
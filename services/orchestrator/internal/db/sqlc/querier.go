@@ -11,30 +11,91 @@ import (
 )
 
 type Querier interface {
-	DeleteHostByMacAddress(ctx context.Context, macAddress string) error
+	// Hands the row of a host registered before agent IDs existed, which kept its MAC address as
+	// agent ID, to the generated ID its upgraded agent registers with.
+	AdoptLegacyHost(ctx context.Context, arg AdoptLegacyHostParams) (int64, error)
+	// Forgets the update found for a container once it has been applied.
+	ClearAvailableUpdate(ctx context.Context, arg ClearAvailableUpdateParams) error
+	// Forgets the failed update attempts of a container, lifting any quarantine.
+	ClearUpdateFailures(ctx context.Context, arg ClearUpdateFailuresParams) error
+	// Drops the watch state set by hand for a container.
+	ClearWatchOverride(ctx context.Context, arg ClearWatchOverrideParams) error
+	// Removes all policies assigned by the policy file before they are reconciled again.
+	DeleteAllContainerPolicies(ctx context.Context) error
+	// Deletes the containers of a host with the given UIDs.
+	DeleteContainersForHost(ctx context.Context, arg DeleteContainersForHostParams) error
 	// Deletes containers for a given host that are not in the provided list of UIDs.
 	DeleteStaleContainersForHost(ctx context.Context, arg DeleteStaleContainersForHostParams) error
+	// Retrieves the overrides of all containers on all hosts.
+	GetAllContainerOverrides(ctx context.Context) ([]ContainerOverride, error)
+	// Retrieves the policies assigned by the policy file to all containers.
+	GetAllContainerPolicies(ctx context.Context) ([]ContainerPolicy, error)
+	// Retrieves all containers on all hosts
+	GetAllContainers(ctx context.Context) ([]Container, error)
 	// Retrieves all containers associated with a given host ID
 	GetAllContainersonHost(ctx context.Context, hostID pgtype.UUID) ([]Container, error)
 	// Retrieves all hosts from the database.
 	GetAllHosts(ctx context.Context) ([]Host, error)
+	// Retrieves the updates found for the containers on a host.
+	GetAvailableUpdatesForHost(ctx context.Context, hostID pgtype.UUID) ([]AvailableUpdate, error)
+	// Retrieves the overrides of all containers on a host.
+	GetContainerOverridesForHost(ctx context.Context, hostID pgtype.UUID) ([]ContainerOverride, error)
+	// Retrieves the policy the policy file assigns to a container.
+	GetContainerPolicy(ctx context.Context, arg GetContainerPolicyParams) (ContainerPolicy, error)
+	// Retrieves the recorded versions of a container on a host.
+	GetContainerVersion(ctx context.Context, arg GetContainerVersionParams) (ContainerVersion, error)
+	// Retrieves the recorded versions of all containers on a host.
+	GetContainerVersionsForHost(ctx context.Context, hostID pgtype.UUID) ([]ContainerVersion, error)
 	// Retrieves a container by its UID
 	GetContainerbyContainerUID(ctx context.Context, containerUid string) (Container, error)
-	// Retrieves a host by its mac_address.
-	GetHostByMacAddress(ctx context.Context, macAddress string) (Host, error)
+	// Retrieves a host by the ID of its agent.
+	GetHostByAgentID(ctx context.Context, agentID string) (Host, error)
 	// Retrieves the host associated with a given container UID
 	GetHostbyContainerUID(ctx context.Context, containerUid string) (Host, error)
+	// Retrieves the most recent completed update of a container on a host.
+	GetLastCompletedUpdate(ctx context.Context, arg GetLastCompletedUpdateParams) (UpdateStatus, error)
+	// Retrieves the most recent admission decision for each container on a host.
+	GetLatestAdmissionDecisionsForHost(ctx context.Context, hostID pgtype.UUID) ([]AdmissionDecision, error)
+	// Retrieves the most recent update status reported for each container on a host.
+	GetLatestUpdateStatusesForHost(ctx context.Context, hostID pgtype.UUID) ([]UpdateStatus, error)
+	// Retrieves the containers of a Docker Compose project on a host.
+	GetProjectContainers(ctx context.Context, arg GetProjectContainersParams) ([]Container, error)
+	// Retrieves the failed attempts to update a container to a candidate digest.
+	GetUpdateFailure(ctx context.Context, arg GetUpdateFailureParams) (UpdateFailure, error)
+	// Retrieves the failed update attempts of all containers on a host, most recent first.
+	GetUpdateFailuresForHost(ctx context.Context, hostID pgtype.UUID) ([]UpdateFailure, error)
 	// Retrieves all containers where watched is true
 	GetallContainersWhereWatched(ctx context.Context) ([]Container, error)
+	// Records the outcome of an admission review for a planned update.
+	InsertAdmissionDecision(ctx context.Context, arg InsertAdmissionDecisionParams) (AdmissionDecision, error)
 	InsertContainer(ctx context.Context, arg InsertContainerParams) (Container, error)
-	// Inserts a new host or updates an existing one based on the MAC address.
+	// Inserts a new host or updates the attributes of an existing one based on the agent ID.
+	// A registering host is online.
 	InsertHost(ctx context.Context, arg InsertHostParams) (Host, error)
 	// Updates the status of a deployment.
 	InsertUpdateStatus(ctx context.Context, arg InsertUpdateStatusParams) (UpdateStatus, error)
-	// Updates the watch status of a container by its name and macid on the host
+	// Marks every host offline; no agent is connected when the orchestrator starts.
+	MarkAllHostsOffline(ctx context.Context) error
+	// Stores an update found for a container. detected_at only moves when a different image or digest is found.
+	RecordAvailableUpdate(ctx context.Context, arg RecordAvailableUpdateParams) (AvailableUpdate, error)
+	// Records the image a container now runs and the one it replaced after a successful update.
+	RecordContainerVersion(ctx context.Context, arg RecordContainerVersionParams) (ContainerVersion, error)
+	// Counts a failed attempt to update a container to a candidate digest.
+	RecordUpdateFailure(ctx context.Context, arg RecordUpdateFailureParams) (UpdateFailure, error)
+	// Pins or unpins a container. Pinned containers are skipped by automatic updates.
+	SetContainerPinned(ctx context.Context, arg SetContainerPinnedParams) error
+	// Records the hash of the inventory an agent reported for a host.
+	SetHostInventoryHash(ctx context.Context, arg SetHostInventoryHashParams) error
+	// Marks a host offline when the command stream of its agent closes.
+	SetHostOffline(ctx context.Context, agentID string) error
+	// Records a watch state set by hand for a container by its name and the agent ID of its host.
+	SetWatchOverride(ctx context.Context, arg SetWatchOverrideParams) error
+	// Updates the watch status of a container by its name and the agent ID of its host
 	SetWatchStatus(ctx context.Context, arg SetWatchStatusParams) error
 	// Updates the last heartbeat timestamp for a host identified by id.
 	UpdateHostLastHeartbeat(ctx context.Context, id pgtype.UUID) (Host, error)
+	// Stores the policy the policy file assigns to a container.
+	UpsertContainerPolicy(ctx context.Context, arg UpsertContainerPolicyParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,137 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: update_failures.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const clearUpdateFailures = `-- name: ClearUpdateFailures :exec
+DELETE FROM update_failures
+WHERE host_id = $1 AND container_name = $2
+`
+
+type ClearUpdateFailuresParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+}
+
+// Forgets the failed update attempts of a container, lifting any quarantine.
+func (q *Queries) ClearUpdateFailures(ctx context.Context, arg ClearUpdateFailuresParams) error {
+	_, err := q.db.Exec(ctx, clearUpdateFailures, arg.HostID, arg.ContainerName)
+	return err
+}
+
+const getUpdateFailure = `-- name: GetUpdateFailure :one
+SELECT host_id, container_name, digest, image, failures, last_error, last_failure_at FROM update_failures
+WHERE host_id = $1 AND container_name = $2 AND digest = $3
+`
+
+type GetUpdateFailureParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+	Digest        string      `json:"digest"`
+}
+
+// Retrieves the failed attempts to update a container to a candidate digest.
+func (q *Queries) GetUpdateFailure(ctx context.Context, arg GetUpdateFailureParams) (UpdateFailure, error) {
+	row := q.db.QueryRow(ctx, getUpdateFailure, arg.HostID, arg.ContainerName, arg.Digest)
+	var i UpdateFailure
+	err := row.Scan(
+		&i.HostID,
+		&i.ContainerName,
+		&i.Digest,
+		&i.Image,
+		&i.Failures,
+		&i.LastError,
+		&i.LastFailureAt,
+	)
+	return i, err
+}
+
+const getUpdateFailuresForHost = `-- name: GetUpdateFailuresForHost :many
+SELECT host_id, container_name, digest, image, failures, last_error, last_failure_at FROM update_failures
+WHERE host_id = $1
+ORDER BY last_failure_at DESC
+`
+
+// Retrieves the failed update attempts of all containers on a host, most recent first.
+func (q *Queries) GetUpdateFailuresForHost(ctx context.Context, hostID pgtype.UUID) ([]UpdateFailure, error) {
+	rows, err := q.db.Query(ctx, getUpdateFailuresForHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UpdateFailure
+	for rows.Next() {
+		var i UpdateFailure
+		if err := rows.Scan(
+			&i.HostID,
+			&i.ContainerName,
+			&i.Digest,
+			&i.Image,
+			&i.Failures,
+			&i.LastError,
+			&i.LastFailureAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordUpdateFailure = `-- name: RecordUpdateFailure :one
+INSERT INTO update_failures (
+  host_id,
+  container_name,
+  digest,
+  image,
+  last_error
+) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (host_id, container_name, digest)
+DO UPDATE SET
+  failures = update_failures.failures + 1,
+  image = EXCLUDED.image,
+  last_error = EXCLUDED.last_error,
+  last_failure_at = now()
+RETURNING host_id, container_name, digest, image, failures, last_error, last_failure_at
+`
+
+type RecordUpdateFailureParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName string      `json:"container_name"`
+	Digest        string      `json:"digest"`
+	Image         string      `json:"image"`
+	LastError     pgtype.Text `json:"last_error"`
+}
+
+// Counts a failed attempt to update a container to a candidate digest.
+func (q *Queries) RecordUpdateFailure(ctx context.Context, arg RecordUpdateFailureParams) (UpdateFailure, error) {
+	row := q.db.QueryRow(ctx, recordUpdateFailure,
+		arg.HostID,
+		arg.ContainerName,
+		arg.Digest,
+		arg.Image,
+		arg.LastError,
+	)
+	var i UpdateFailure
+	err := row.Scan(
+		&i.HostID,
+		&i.ContainerName,
+		&i.Digest,
+		&i.Image,
+		&i.Failures,
+		&i.LastError,
+		&i.LastFailureAt,
+	)
+	return i, err
+}
@@ -11,23 +11,93 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const getLastCompletedUpdate = `-- name: GetLastCompletedUpdate :one
+SELECT id, image, host_id, stage, logs, created_at, container_uid, container_name FROM update_status
+WHERE host_id = $1 AND container_name = $2 AND stage = 'completed'
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetLastCompletedUpdateParams struct {
+	HostID        pgtype.UUID `json:"host_id"`
+	ContainerName pgtype.Text `json:"container_name"`
+}
+
+// Retrieves the most recent completed update of a container on a host.
+func (q *Queries) GetLastCompletedUpdate(ctx context.Context, arg GetLastCompletedUpdateParams) (UpdateStatus, error) {
+	row := q.db.QueryRow(ctx, getLastCompletedUpdate, arg.HostID, arg.ContainerName)
+	var i UpdateStatus
+	err := row.Scan(
+		&i.ID,
+		&i.Image,
+		&i.HostID,
+		&i.Stage,
+		&i.Logs,
+		&i.CreatedAt,
+		&i.ContainerUid,
+		&i.ContainerName,
+	)
+	return i, err
+}
+
+const getLatestUpdateStatusesForHost = `-- name: GetLatestUpdateStatusesForHost :many
+SELECT DISTINCT ON (container_name) id, image, host_id, stage, logs, created_at, container_uid, container_name
+FROM update_status
+WHERE host_id = $1 AND container_name IS NOT NULL
+ORDER BY container_name, created_at DESC, id DESC
+`
+
+// Retrieves the most recent update status reported for each container on a host.
+func (q *Queries) GetLatestUpdateStatusesForHost(ctx context.Context, hostID pgtype.UUID) ([]UpdateStatus, error) {
+	rows, err := q.db.Query(ctx, getLatestUpdateStatusesForHost, hostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UpdateStatus
+	for rows.Next() {
+		var i UpdateStatus
+		if err := rows.Scan(
+			&i.ID,
+			&i.Image,
+			&i.HostID,
+			&i.Stage,
+			&i.Logs,
+			&i.CreatedAt,
+			&i.ContainerUid,
+			&i.ContainerName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertUpdateStatus = `-- name: InsertUpdateStatus :one
 INSERT INTO update_status (
   host_id,
   stage,
   logs,
-  image
+  image,
+  container_uid,
+  container_name
   ) 
 VALUES (
-  $1, $2, $3, $4
-) RETURNING id, image, host_id, stage, logs, created_at
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, image, host_id, stage, logs, created_at, container_uid, container_name
 `
 
 type InsertUpdateStatusParams struct {
-	HostID pgtype.UUID `json:"host_id"`
-	Stage  UpdateStage `json:"stage"`
-	Logs   pgtype.Text `json:"logs"`
-	Image  string      `json:"image"`
+	HostID        pgtype.UUID `json:"host_id"`
+	Stage         UpdateStage `json:"stage"`
+	Logs          pgtype.Text `json:"logs"`
+	Image         string      `json:"image"`
+	ContainerUid  pgtype.Text `json:"container_uid"`
+	ContainerName pgtype.Text `json:"container_name"`
 }
 
 // Updates the status of a deployment.
@@ -37,6 +107,8 @@ func (q *Queries) InsertUpdateStatus(ctx context.Context, arg InsertUpdateStatus
 		arg.Stage,
 		arg.Logs,
 		arg.Image,
+		arg.ContainerUid,
+		arg.ContainerName,
 	)
 	var i UpdateStatus
 	err := row.Scan(
@@ -46,6 +118,8 @@ func (q *Queries) InsertUpdateStatus(ctx context.Context, arg InsertUpdateStatus
 		&i.Stage,
 		&i.Logs,
 		&i.CreatedAt,
+		&i.ContainerUid,
+		&i.ContainerName,
 	)
 	return i, err
 }
@@ -0,0 +1,122 @@
+package agentserver
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ProtocolVersion is the newest command stream protocol the orchestrator speaks.
+	ProtocolVersion = 1
+	// MinProtocolVersion is the oldest protocol an agent may speak to be accepted.
+	MinProtocolVersion = 1
+)
+
+// Version is the orchestrator version reported to agents, set at build time with
+// -ldflags "-X .../internal/grpc/agent.Version=v1.2.3".
+var Version = "dev"
+
+// supportedFeatures are the features the orchestrator knows how to use.
+var supportedFeatures = []orchestrator.Feature{
+	orchestrator.Feature_FEATURE_HEALTH_CHECKS,
+	orchestrator.Feature_FEATURE_HOOKS,
+	orchestrator.Feature_FEATURE_COMPOSE,
+	orchestrator.Feature_FEATURE_START_FIRST,
+	orchestrator.Feature_FEATURE_CANCEL,
+}
+
+// handshake reads the AgentHello that opens a command stream and answers it. It returns the
+// hello and the features both sides support, or an error once an incompatible agent was told
+// why it is refused.
+func handshake(stream orchestrator.HostAgentService_ConnectAgentStreamServer) (*orchestrator.AgentHello, []orchestrator.Feature, error) {
+	first, err := stream.Recv()
+	if err != nil {
+		return nil, nil, err
+	}
+	hello := first.GetHello()
+	if hello == nil {
+		// Agents that predate the handshake open with a bare UpdateStatus.
+		msg := "agent predates the stream handshake, upgrade it to connect"
+		refuse(stream, msg)
+		return nil, nil, status.Error(codes.FailedPrecondition, msg)
+	}
+	if hello.AgentId == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "empty agent ID")
+	}
+	if hello.ProtocolVersion < MinProtocolVersion {
+		msg := fmt.Sprintf("agent protocol %d is older than the oldest supported protocol %d, upgrade the agent", hello.ProtocolVersion, MinProtocolVersion)
+		log.Printf("Refusing agent %s (version %s): %s", hello.AgentId, hello.AgentVersion, msg)
+		refuse(stream, msg)
+		return nil, nil, status.Error(codes.FailedPrecondition, msg)
+	}
+
+	var features []orchestrator.Feature
+	var missing []string
+	for _, f := range supportedFeatures {
+		if slices.Contains(hello.Features, f) {
+			features = append(features, f)
+		} else {
+			missing = append(missing, featureName(f))
+		}
+	}
+	reply := &orchestrator.OrchestratorHello{
+		Accepted:            true,
+		OrchestratorVersion: Version,
+		ProtocolVersion:     min(hello.ProtocolVersion, ProtocolVersion),
+		Features:            features,
+	}
+	if hello.ProtocolVersion > ProtocolVersion {
+		reply.Message = fmt.Sprintf("using protocol %d of orchestrator %s", ProtocolVersion, Version)
+	}
+	if len(missing) > 0 {
+		if reply.Message != "" {
+			reply.Message += "; "
+		}
+		reply.Message += "not supported by the agent: " + strings.Join(missing, ", ")
+	}
+	if err := stream.Send(&orchestrator.OrchestratorMessage{Message: &orchestrator.OrchestratorMessage_Hello{Hello: reply}}); err != nil {
+		return nil, nil, err
+	}
+	log.Printf("Agent %s version %s (protocol %d, %s/%s, Docker %s) accepted", hello.AgentId, hello.AgentVersion, reply.ProtocolVersion, hello.Os, hello.Arch, hello.DockerVersion)
+	if reply.Message != "" {
+		log.Printf("Agent %s degraded: %s", hello.AgentId, reply.Message)
+	}
+	return hello, features, nil
+}
+
+// refuse tells an agent why its stream is closed.
+func refuse(stream orchestrator.HostAgentService_ConnectAgentStreamServer, msg string) {
+	err := stream.Send(&orchestrator.OrchestratorMessage{Message: &orchestrator.OrchestratorMessage_Hello{Hello: &orchestrator.OrchestratorHello{
+		Accepted:            false,
+		Message:             msg,
+		OrchestratorVersion: Version,
+		ProtocolVersion:     ProtocolVersion,
+	}}})
+	if err != nil {
+		log.Printf("Send refusal to agent failed: %v", err)
+	}
+}
+
+// degrade removes the parts of a command the agent can't carry out. It fails when the command
+// depends on a feature that can't safely be dropped.
+func degrade(conn *AgentConnection, cmd *orchestrator.UpdateContainerCommand) error {
+	if len(cmd.GetHooks()) > 0 && !conn.supports(orchestrator.Feature_FEATURE_HOOKS) {
+		// Pre-update hooks such as backups must not be skipped silently.
+		return fmt.Errorf("agent version %s does not support update hooks", conn.Hello.GetAgentVersion())
+	}
+	if cmd.GetHealthCheck() != nil && !conn.supports(orchestrator.Feature_FEATURE_HEALTH_CHECKS) {
+		log.Printf("Agent version %s does not support health checks; updating %s without one", conn.Hello.GetAgentVersion(), cmd.GetContainerName())
+		cmd.HealthCheck = nil
+	}
+	return nil
+}
+
+func featureName(f orchestrator.Feature) string {
+	return strings.ToLower(strings.TrimPrefix(f.String(), "FEATURE_"))
+}
@@ -6,19 +6,33 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
 	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 // AgentConnection holds the stream and a dedicated channel for safely sending commands.
 type AgentConnection struct {
 	Stream      orchestrator.HostAgentService_ConnectAgentStreamServer
-	CommandChan chan *orchestrator.UpdateContainerCommand
-	done        chan struct{}
+	CommandChan chan *orchestrator.OrchestratorMessage
+	// Hello is the handshake the agent opened the stream with.
+	Hello    *orchestrator.AgentHello
+	features []orchestrator.Feature // negotiated in the handshake
+	// queueDepth is the number of updates waiting on the agent, as of its latest status.
+	queueDepth atomic.Uint32
+	done       chan struct{}
+}
+
+// supports reports whether a feature was negotiated with the agent.
+func (c *AgentConnection) supports(f orchestrator.Feature) bool {
+	return slices.Contains(c.features, f)
 }
 
 // Server is the main gRPC server structure.
@@ -27,34 +41,152 @@ type Server struct {
 	DB    *db.Queries
 	Mu    sync.RWMutex
 	Hosts map[string]*AgentConnection
+
+	inflightMu sync.Mutex
+	inflight   map[string]*inflightCommand                // command ID -> command awaiting its outcome
+	containers map[string][]string                        // agentID/container name -> IDs of its in-flight commands, oldest first
+	waiters    map[string]chan *orchestrator.UpdateStatus // command ID -> caller of WaitOutcome
+}
+
+// inflightCommand is a command sent to an agent whose outcome has not arrived yet.
+type inflightCommand struct {
+	cmd      *orchestrator.UpdateContainerCommand
+	key      string // agentID/container name
+	rollback bool   // restores a previous image
 }
 
 // NewServer creates a new instance of the gRPC server.
 func NewServer(queries *db.Queries) *Server {
 	return &Server{
-		DB:    queries,
-		Hosts: make(map[string]*AgentConnection),
+		DB:         queries,
+		Hosts:      make(map[string]*AgentConnection),
+		inflight:   make(map[string]*inflightCommand),
+		containers: make(map[string][]string),
+		waiters:    make(map[string]chan *orchestrator.UpdateStatus),
 	}
 }
 
 // SendCommand sends a command to a connected agent safely.
 func (s *Server) SendCommand(agentID string, cmd *orchestrator.UpdateContainerCommand) error {
+	return s.send(agentID, cmd, false)
+}
+
+// SendRollback sends a command that restores a previous image. Its outcome leaves the failure
+// history of the container alone: the digest it restores is not a candidate that can fail.
+func (s *Server) SendRollback(agentID string, cmd *orchestrator.UpdateContainerCommand) error {
+	return s.send(agentID, cmd, true)
+}
+
+func (s *Server) send(agentID string, cmd *orchestrator.UpdateContainerCommand, rollback bool) error {
 	s.Mu.RLock()
 	conn, ok := s.Hosts[agentID]
 	s.Mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("agent %s not connected or disconnected", agentID)
 	}
+	if err := degrade(conn, cmd); err != nil {
+		return err
+	}
+	if cmd.CommandId == "" {
+		cmd.CommandId = uuid.NewString()
+	}
 
+	// Tracked before it is sent, as its first status may arrive before the send returns
+	s.track(&inflightCommand{cmd: cmd, key: agentID + "/" + cmd.GetContainerName(), rollback: rollback})
 	select {
-	case conn.CommandChan <- cmd:
-		log.Printf("Queued update command for agent %s image %s", agentID, cmd.Image)
+	case conn.CommandChan <- &orchestrator.OrchestratorMessage{Message: &orchestrator.OrchestratorMessage_Command{Command: cmd}}:
+		log.Printf("Queued update command for agent %s image %s (%d update(s) waiting on the agent)", agentID, cmd.Image, conn.queueDepth.Load())
 		return nil
 	case <-conn.done:
+		s.settle(cmd.CommandId)
 		return fmt.Errorf("agent %s disconnected, cannot send command", agentID)
 	}
 }
 
+// track records a command as in flight.
+func (s *Server) track(c *inflightCommand) {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	s.inflight[c.cmd.CommandId] = c
+	s.containers[c.key] = append(s.containers[c.key], c.cmd.CommandId)
+}
+
+// settle stops tracking a command and returns it with the caller waiting for its outcome, if any.
+func (s *Server) settle(commandID string) (*inflightCommand, chan *orchestrator.UpdateStatus) {
+	s.inflightMu.Lock()
+	defer s.inflightMu.Unlock()
+	c, ok := s.inflight[commandID]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.inflight, commandID)
+	ids := slices.DeleteFunc(s.containers[c.key], func(id string) bool { return id == commandID })
+	if len(ids) == 0 {
+		delete(s.containers, c.key)
+	} else {
+		s.containers[c.key] = ids
+	}
+	waiter := s.waiters[commandID]
+	delete(s.waiters, commandID)
+	return c, waiter
+}
+
+// CancelUpdate asks an agent to abort the active update of a container: the oldest command sent
+// for it whose outcome is still open, which the agent runs before any later one. The outcome
+// arrives as a CANCELLED status, or as the update's own outcome when it was too late to stop.
+func (s *Server) CancelUpdate(agentID, containerName, reason string) error {
+	s.Mu.RLock()
+	conn, ok := s.Hosts[agentID]
+	s.Mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("agent %s not connected or disconnected", agentID)
+	}
+	if !conn.supports(orchestrator.Feature_FEATURE_CANCEL) {
+		return fmt.Errorf("agent version %s does not support cancelling updates", conn.Hello.GetAgentVersion())
+	}
+	s.inflightMu.Lock()
+	var cmd *orchestrator.UpdateContainerCommand
+	if ids := s.containers[agentID+"/"+containerName]; len(ids) > 0 {
+		cmd = s.inflight[ids[0]].cmd
+	}
+	s.inflightMu.Unlock()
+	if cmd == nil {
+		return fmt.Errorf("no update of %s in progress", containerName)
+	}
+
+	cancel := &orchestrator.CancelUpdate{CommandId: cmd.GetCommandId(), ContainerName: containerName, Reason: reason}
+	select {
+	case conn.CommandChan <- &orchestrator.OrchestratorMessage{Message: &orchestrator.OrchestratorMessage_Cancel{Cancel: cancel}}:
+		log.Printf("Queued cancel of update %s of %s for agent %s", cmd.GetCommandId(), containerName, agentID)
+		return nil
+	case <-conn.done:
+		return fmt.Errorf("agent %s disconnected, cannot cancel update", agentID)
+	}
+}
+
+// WaitOutcome returns a channel that receives the first terminal status (COMPLETED, FAILED,
+// ROLLBACK, CANCELLED or REJECTED) of the command with the given ID. Call it before sending the
+// command, and call the returned func once done waiting, also when the command wasn't sent.
+func (s *Server) WaitOutcome(commandID string) (<-chan *orchestrator.UpdateStatus, func()) {
+	ch := make(chan *orchestrator.UpdateStatus, 1)
+	s.inflightMu.Lock()
+	s.waiters[commandID] = ch
+	s.inflightMu.Unlock()
+	return ch, func() {
+		s.inflightMu.Lock()
+		delete(s.waiters, commandID)
+		s.inflightMu.Unlock()
+	}
+}
+
+// IsConnected reports whether the agent currently has an open command stream.
+func (s *Server) IsConnected(agentID string) bool {
+	s.Mu.RLock()
+	defer s.Mu.RUnlock()
+	_, ok := s.Hosts[agentID]
+	return ok
+}
+
 // RegisterHost handles initial registration of a host and its containers.
 func (s *Server) RegisterHost(ctx context.Context, req *orchestrator.RegisterHostRequest) (*orchestrator.RegisterHostResponse, error) {
 	if req == nil || req.Host == nil {
@@ -63,7 +195,19 @@ func (s *Server) RegisterHost(ctx context.Context, req *orchestrator.RegisterHos
 
 	log.Printf("Host registration: %s (%s)", req.Host.Hostname, req.Host.IpAddress)
 
+	agentID := agentIDOf(req.Host.AgentId, req.Host.MacAddress)
+	if agentID != req.Host.MacAddress && req.Host.MacAddress != "" {
+		// Hosts stored before agent IDs existed are keyed by MAC address; an upgraded agent takes
+		// its row over instead of starting a new one without its history.
+		adopted, err := s.DB.AdoptLegacyHost(ctx, db.AdoptLegacyHostParams{AgentID: agentID, MacAddress: req.Host.MacAddress})
+		if err != nil {
+			log.Printf("Adopt host %s for agent %s failed: %v", req.Host.MacAddress, agentID, err)
+		} else if adopted > 0 {
+			log.Printf("Host %s registered before agent IDs now belongs to agent %s", req.Host.MacAddress, agentID)
+		}
+	}
 	params := db.InsertHostParams{
+		AgentID:    agentID,
 		MacAddress: req.Host.MacAddress,
 		Hostname:   req.Host.Hostname,
 		IpAddress:  req.Host.IpAddress,
@@ -74,26 +218,23 @@ func (s *Server) RegisterHost(ctx context.Context, req *orchestrator.RegisterHos
 		return &orchestrator.RegisterHostResponse{Success: false, Message: err.Error()}, nil
 	}
 
+	hash := req.Host.InventoryHash
+	uids := make([]string, 0, len(req.Host.Containers))
 	for _, container := range req.Host.Containers {
-		portsBytes, err := json.Marshal(convertPortsToDBFormat(container.Ports))
-		if err != nil {
-			log.Printf("Failed to marshal ports for container %s: %v", container.Name, err)
-			portsBytes = []byte("[]") // Use empty JSON array as fallback
-		}
-		containerParams := db.InsertContainerParams{
-			ContainerUid: container.ContainerID,
-			HostID:       host.ID,
-			Name:         container.Name,
-			Image:        container.Image,
-			Ports:        portsBytes,
-			EnvVars:      container.EnvVars,
-			Volumes:      container.Volumes,
-			Network:      pgtype.Text{String: container.Network, Valid: true},
-		}
-		if _, err := s.DB.InsertContainer(ctx, containerParams); err != nil {
+		uids = append(uids, container.ContainerID)
+		if _, err := s.DB.InsertContainer(ctx, containerParams(host.ID, container)); err != nil {
 			log.Printf("Register container %s failed: %v", container.Name, err)
+			hash = ""
+		}
+	}
+	if hash != "" {
+		// The host may still be stored from an earlier connection; drop what it no longer runs.
+		if err := s.DB.DeleteStaleContainersForHost(ctx, db.DeleteStaleContainersForHostParams{HostID: host.ID, Column2: uids}); err != nil {
+			log.Printf("Delete stale containers failed: %v", err)
+			hash = ""
 		}
 	}
+	s.ackInventory(ctx, host, hash)
 	return &orchestrator.RegisterHostResponse{
 		Success: true,
 		Message: "Host registered successfully",
@@ -102,8 +243,9 @@ func (s *Server) RegisterHost(ctx context.Context, req *orchestrator.RegisterHos
 
 // Heartbeat processes periodic updates from agents.
 func (s *Server) Heartbeat(ctx context.Context, req *orchestrator.HeartbeatRequest) (*orchestrator.HeartbeatResponse, error) {
-	log.Printf("Heartbeat from host %s", req.MacAddress)
-	host, err := s.DB.GetHostByMacAddress(ctx, req.MacAddress)
+	agentID := agentIDOf(req.AgentId, req.MacAddress)
+	log.Printf("Heartbeat from host %s", agentID)
+	host, err := s.DB.GetHostByAgentID(ctx, agentID)
 	if err != nil {
 		return &orchestrator.HeartbeatResponse{Success: false, Message: err.Error()}, nil
 	}
@@ -112,31 +254,45 @@ func (s *Server) Heartbeat(ctx context.Context, req *orchestrator.HeartbeatReque
 		log.Printf("Update heartbeat failed: %v", err)
 	}
 
+	if req.Partial && req.BaseHash != host.InventoryHash.String {
+		// The changes apply to an inventory other than the one stored, e.g. after a lost heartbeat.
+		log.Printf("Inventory of host %s out of sync, requesting a full resync", agentID)
+		return &orchestrator.HeartbeatResponse{
+			Success:        true,
+			Message:        "inventory hash mismatch",
+			InventoryHash:  host.InventoryHash.String,
+			ResyncRequired: true,
+		}, nil
+	}
+	if req.Partial && len(req.Containers) == 0 && len(req.RemovedContainerIds) == 0 {
+		return &orchestrator.HeartbeatResponse{Success: true, Message: "Heartbeat processed successfully", InventoryHash: host.InventoryHash.String}, nil
+	}
+
+	hash := req.InventoryHash
 	activeContainerUIDs := make([]string, 0, len(req.Containers))
 	for _, c := range req.Containers {
-		portsBytes, err := json.Marshal(convertPortsToDBFormat(c.Ports))
-		if err != nil {
-			log.Printf("Failed to marshal ports for container %s: %v", c.Name, err)
-			portsBytes = []byte("[]") // Use empty JSON array as fallback
-		}
 		activeContainerUIDs = append(activeContainerUIDs, c.ContainerID)
-		containerParams := db.InsertContainerParams{
-			ContainerUid: c.ContainerID,
-			HostID:       host.ID,
-			Name:         c.Name,
-			Image:        c.Image,
-			Ports:        portsBytes,
-			EnvVars:      c.EnvVars,
-			Volumes:      c.Volumes,
-			Network:      pgtype.Text{String: c.Network, Valid: true},
-		}
-		if _, err := s.DB.InsertContainer(ctx, containerParams); err != nil {
+		if _, err := s.DB.InsertContainer(ctx, containerParams(host.ID, c)); err != nil {
 			log.Printf("Upsert container %s failed: %v", c.Name, err)
+			hash = "" // what is stored no longer matches the agent's inventory
 		}
 	}
 
-	// Delete stale containers
-	if len(activeContainerUIDs) > 0 {
+	if req.Partial {
+		// Only changes were sent: remove what the agent reported gone and keep the rest.
+		if len(req.RemovedContainerIds) > 0 {
+			params := db.DeleteContainersForHostParams{HostID: host.ID, Column2: req.RemovedContainerIds}
+			if err := s.DB.DeleteContainersForHost(ctx, params); err != nil {
+				log.Printf("Delete removed containers failed: %v", err)
+				return &orchestrator.HeartbeatResponse{Success: false, Message: err.Error()}, nil
+			}
+		}
+		log.Printf("Synced %d changed and %d removed containers host %s", len(req.Containers), len(req.RemovedContainerIds), agentID)
+		return s.ackInventory(ctx, host, hash), nil
+	}
+
+	// Delete stale containers; an empty list from agents that hash their inventory is complete too
+	if len(activeContainerUIDs) > 0 || req.InventoryHash != "" {
 		params := db.DeleteStaleContainersForHostParams{
 			HostID:  host.ID,
 			Column2: activeContainerUIDs,
@@ -147,25 +303,34 @@ func (s *Server) Heartbeat(ctx context.Context, req *orchestrator.HeartbeatReque
 		}
 	}
 
-	log.Printf("Synced %d containers host %s", len(activeContainerUIDs), req.MacAddress)
-	return &orchestrator.HeartbeatResponse{Success: true, Message: "Heartbeat processed successfully"}, nil
+	log.Printf("Synced %d containers host %s", len(activeContainerUIDs), agentID)
+	return s.ackInventory(ctx, host, hash), nil
+}
+
+// ackInventory records the inventory hash of a host once its containers were stored.
+func (s *Server) ackInventory(ctx context.Context, host db.Host, hash string) *orchestrator.HeartbeatResponse {
+	err := s.DB.SetHostInventoryHash(ctx, db.SetHostInventoryHashParams{ID: host.ID, InventoryHash: pgtype.Text{String: hash, Valid: hash != ""}})
+	if err != nil {
+		log.Printf("Record inventory hash of host %s failed: %v", host.AgentID, err)
+		hash = "" // the next changes will be refused, forcing a resync
+	}
+	return &orchestrator.HeartbeatResponse{Success: true, Message: "Heartbeat processed successfully", InventoryHash: hash}
 }
 
 // ConnectAgentStream handles bidirectional agent streams.
 func (s *Server) ConnectAgentStream(stream orchestrator.HostAgentService_ConnectAgentStreamServer) error {
-	firstMsg, err := stream.Recv()
+	hello, features, err := handshake(stream)
 	if err != nil {
 		return err
 	}
-	agentID := firstMsg.GetMacAddress()
-	if agentID == "" {
-		return fmt.Errorf("empty agent ID")
-	}
+	agentID := hello.AgentId
 
 	log.Printf("Agent stream connected %s", agentID)
 	conn := &AgentConnection{
 		Stream:      stream,
-		CommandChan: make(chan *orchestrator.UpdateContainerCommand, 10),
+		CommandChan: make(chan *orchestrator.OrchestratorMessage, 10),
+		Hello:       hello,
+		features:    features,
 		done:        make(chan struct{}),
 	}
 
@@ -176,32 +341,31 @@ func (s *Server) ConnectAgentStream(stream orchestrator.HostAgentService_Connect
 	defer func() {
 		// Clean up in-memory connection map first
 		s.Mu.Lock()
-		delete(s.Hosts, agentID)
+		current := s.Hosts[agentID] == conn
+		if current {
+			delete(s.Hosts, agentID)
+		}
 		s.Mu.Unlock()
 		close(conn.done)
 		log.Printf("Agent stream disconnected %s", agentID)
+		if !current {
+			// The agent reconnected before this stream was noticed to be gone.
+			return
+		}
 
-		// --- MODIFICATION START ---
-		// Delete all data for the disconnected host from the database
-		log.Printf("Attempting to delete all data for disconnected host %s", agentID)
-		ctx := context.Background() // Use a background context for cleanup
-
-		// This requires a `DeleteHostByMacAddress` query in your SQLc setup.
-		// Your DB schema should use `ON DELETE CASCADE` for this to be effective.
-		if err := s.DB.DeleteHostByMacAddress(ctx, agentID); err != nil {
-			log.Printf("Failed to delete data for host %s: %v", agentID, err)
-		} else {
-			log.Printf("Successfully deleted all data for host %s", agentID)
+		// Keep the host and what is recorded about its containers (failure history, versions,
+		// pins, overrides); it is only marked offline until the agent registers again.
+		if err := s.DB.SetHostOffline(context.Background(), agentID); err != nil {
+			log.Printf("Mark host %s offline failed: %v", agentID, err)
 		}
-		// --- MODIFICATION END ---
 	}()
 
 	// Write loop
 	go func() {
 		for {
 			select {
-			case cmd := <-conn.CommandChan:
-				if err := stream.Send(cmd); err != nil {
+			case out := <-conn.CommandChan:
+				if err := stream.Send(out); err != nil {
 					log.Printf("Send command to agent %s failed: %v", agentID, err)
 					return
 				}
@@ -213,7 +377,7 @@ func (s *Server) ConnectAgentStream(stream orchestrator.HostAgentService_Connect
 
 	// Read loop
 	for {
-		msg, err := stream.Recv()
+		in, err := stream.Recv()
 		if err == io.EOF {
 			log.Printf("Agent stream closed by %s", agentID)
 			return nil
@@ -221,19 +385,142 @@ func (s *Server) ConnectAgentStream(stream orchestrator.HostAgentService_Connect
 		if err != nil {
 			return fmt.Errorf("agent stream recv failed: %w", err)
 		}
+		msg := in.GetStatus()
+		if msg == nil {
+			log.Printf("Ignoring unexpected message from agent %s", agentID)
+			continue
+		}
 
 		status := msg.GetStage()
-		host, _ := s.DB.GetHostByMacAddress(context.Background(), agentID)
+		conn.queueDepth.Store(msg.GetQueueDepth())
+		host, _ := s.DB.GetHostByAgentID(context.Background(), agentID)
 
 		_, err = s.DB.InsertUpdateStatus(context.Background(), db.InsertUpdateStatusParams{
-			HostID: host.ID,
-			Stage:  grpcenumtodbstatus(status),
-			Logs:   pgtype.Text{String: strings.TrimSpace(msg.GetLogs()), Valid: true},
-			Image:  msg.Image,
+			HostID:        host.ID,
+			Stage:         grpcenumtodbstatus(status),
+			Logs:          pgtype.Text{String: strings.TrimSpace(msg.GetLogs()), Valid: true},
+			Image:         msg.Image,
+			ContainerUid:  pgtype.Text{String: msg.GetContainerUID(), Valid: msg.GetContainerUID() != ""},
+			ContainerName: pgtype.Text{String: msg.GetContainerName(), Valid: msg.GetContainerName() != ""},
 		})
 		if err != nil {
 			log.Printf("Insert update status failed: %v", err)
 		}
+		if status == orchestrator.UpdateStatus_COMPLETED && msg.GetPreviousImage() != "" && msg.GetContainerName() != "" {
+			s.recordVersion(host, msg)
+		}
+		s.recordOutcome(agentID, host, msg)
+	}
+}
+
+// recordOutcome settles an in-flight command once its first terminal status arrives. A failure
+// counts against the command's candidate digest; a success clears the container's failure
+// history; a cancellation, a rejection or a rollback does neither. Later statuses of the same
+// attempt (e.g. rollback) are ignored. Statuses of agents that send no command ID settle the
+// container's oldest command.
+func (s *Server) recordOutcome(agentID string, host db.Host, msg *orchestrator.UpdateStatus) {
+	stage := msg.GetStage()
+	failed := stage == orchestrator.UpdateStatus_FAILED || stage == orchestrator.UpdateStatus_ROLLBACK
+	cancelled := stage == orchestrator.UpdateStatus_CANCELLED
+	rejected := stage == orchestrator.UpdateStatus_REJECTED
+	if stage != orchestrator.UpdateStatus_COMPLETED && !failed && !cancelled && !rejected {
+		return
+	}
+	commandID := msg.GetCommandId()
+	if commandID == "" {
+		s.inflightMu.Lock()
+		if ids := s.containers[agentID+"/"+msg.GetContainerName()]; len(ids) > 0 {
+			commandID = ids[0]
+		}
+		s.inflightMu.Unlock()
+	}
+	c, waiter := s.settle(commandID)
+	if c == nil {
+		return
+	}
+	cmd := c.cmd
+	if waiter != nil {
+		waiter <- msg
+	}
+	switch {
+	case cancelled:
+		log.Printf("Update of %s to %s cancelled", cmd.GetContainerName(), cmd.GetImage())
+		return
+	case rejected:
+		log.Printf("Update of %s to %s rejected: %s", cmd.GetContainerName(), cmd.GetImage(), strings.TrimSpace(msg.GetLogs()))
+		return
+	case c.rollback:
+		log.Printf("Rollback of %s to %s finished: %s", cmd.GetContainerName(), cmd.GetImage(), stage)
+		return
+	}
+
+	ctx := context.Background()
+	if !failed {
+		if err := s.DB.ClearUpdateFailures(ctx, db.ClearUpdateFailuresParams{HostID: host.ID, ContainerName: cmd.GetContainerName()}); err != nil {
+			log.Printf("Clear update failures of %s failed: %v", cmd.GetContainerName(), err)
+		}
+		if err := s.DB.ClearAvailableUpdate(ctx, db.ClearAvailableUpdateParams{HostID: host.ID, ContainerName: cmd.GetContainerName()}); err != nil {
+			log.Printf("Clear available update of %s failed: %v", cmd.GetContainerName(), err)
+		}
+		return
+	}
+	if cmd.GetDigest() == "" {
+		return
+	}
+	f, err := s.DB.RecordUpdateFailure(ctx, db.RecordUpdateFailureParams{
+		HostID:        host.ID,
+		ContainerName: cmd.GetContainerName(),
+		Digest:        cmd.GetDigest(),
+		Image:         cmd.GetImage(),
+		LastError:     pgtype.Text{String: strings.TrimSpace(msg.GetLogs()), Valid: msg.GetLogs() != ""},
+	})
+	if err != nil {
+		log.Printf("Record update failure of %s failed: %v", cmd.GetContainerName(), err)
+		return
+	}
+	log.Printf("Update of %s to %s failed (%d failure(s) for this digest)", cmd.GetContainerName(), cmd.GetImage(), f.Failures)
+}
+
+// recordVersion remembers the image a container ran before a successful update so it can be rolled back.
+func (s *Server) recordVersion(host db.Host, msg *orchestrator.UpdateStatus) {
+	_, err := s.DB.RecordContainerVersion(context.Background(), db.RecordContainerVersionParams{
+		HostID:         host.ID,
+		ContainerName:  msg.GetContainerName(),
+		CurrentImage:   pgtype.Text{String: msg.GetImage(), Valid: msg.GetImage() != ""},
+		CurrentDigest:  pgtype.Text{String: msg.GetImageDigest(), Valid: msg.GetImageDigest() != ""},
+		PreviousImage:  pgtype.Text{String: msg.GetPreviousImage(), Valid: true},
+		PreviousDigest: pgtype.Text{String: msg.GetPreviousDigest(), Valid: msg.GetPreviousDigest() != ""},
+	})
+	if err != nil {
+		log.Printf("Record versions of %s failed: %v", msg.GetContainerName(), err)
+	}
+}
+
+// containerParams maps a container reported by an agent to its row.
+func containerParams(hostID pgtype.UUID, c *orchestrator.ContainerInfo) db.InsertContainerParams {
+	portsBytes, err := json.Marshal(convertPortsToDBFormat(c.Ports))
+	if err != nil {
+		log.Printf("Failed to marshal ports for container %s: %v", c.Name, err)
+		portsBytes = []byte("[]") // Use empty JSON array as fallback
+	}
+	return db.InsertContainerParams{
+		ContainerUid: c.ContainerID,
+		HostID:       hostID,
+		Name:         c.Name,
+		Image:        c.Image,
+		Ports:        portsBytes,
+		EnvVars:      c.EnvVars,
+		Volumes:      c.Volumes,
+		Network:      pgtype.Text{String: c.Network, Valid: true},
+		Labels:       labelsToDBFormat(c.Labels),
+		ImageDigest:  pgtype.Text{String: c.ImageDigest, Valid: c.ImageDigest != ""},
+
+		ComposeProject: pgtype.Text{String: c.ComposeProject, Valid: c.ComposeProject != ""},
+		ComposeService: pgtype.Text{String: c.ComposeService, Valid: c.ComposeService != ""},
+		DependsOn:      c.DependsOn,
+		Spec:           specToDBFormat(c.Spec),
+		State:          pgtype.Text{String: c.State, Valid: c.State != ""},
+		Health:         pgtype.Text{String: c.Health, Valid: c.Health != ""},
 	}
 }
 
@@ -246,6 +533,41 @@ func convertPortsToDBFormat(ports []*orchestrator.PortMapping) []string {
 	return out
 }
 
+// labelsToDBFormat converts container labels to DB-storable JSON.
+func labelsToDBFormat(labels map[string]string) []byte {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	out, err := json.Marshal(labels)
+	if err != nil {
+		log.Printf("Failed to marshal labels: %v", err)
+		return []byte("{}")
+	}
+	return out
+}
+
+// agentIDOf returns the ID an agent identifies itself with. Agents that predate agent IDs
+// are identified by their MAC address.
+func agentIDOf(agentID, macAddress string) string {
+	if agentID != "" {
+		return agentID
+	}
+	return macAddress
+}
+
+// specToDBFormat stores a container spec as JSON; agents that send none store NULL.
+func specToDBFormat(spec *orchestrator.ContainerSpec) []byte {
+	if spec == nil {
+		return nil
+	}
+	out, err := protojson.Marshal(spec)
+	if err != nil {
+		log.Printf("Failed to marshal container spec: %v", err)
+		return nil
+	}
+	return out
+}
+
 // grpcenumtodbstatus maps gRPC status to DB enum
 func grpcenumtodbstatus(status orchestrator.UpdateStatus_Stage) db.UpdateStage {
 	switch status {
@@ -259,6 +581,18 @@ func grpcenumtodbstatus(status orchestrator.UpdateStatus_Stage) db.UpdateStage {
 		return db.UpdateStageRollback
 	case orchestrator.UpdateStatus_COMPLETED:
 		return db.UpdateStageCompleted
+	case orchestrator.UpdateStatus_HEALTH_CHECK:
+		return db.UpdateStageHealthCheck
+	case orchestrator.UpdateStatus_HOOK:
+		return db.UpdateStageHook
+	case orchestrator.UpdateStatus_QUEUED:
+		return db.UpdateStageQueued
+	case orchestrator.UpdateStatus_REJECTED:
+		return db.UpdateStageRejected
+	case orchestrator.UpdateStatus_CANCELLED:
+		return db.UpdateStageCancelled
+	case orchestrator.UpdateStatus_PREFLIGHT:
+		return db.UpdateStagePreflight
 	case orchestrator.UpdateStatus_FAILED, orchestrator.UpdateStatus_UNKNOWN:
 		return db.UpdateStageFailed
 	default:
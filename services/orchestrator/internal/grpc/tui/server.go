@@ -295,18 +295,43 @@ func (s *Server) SendDatastream(stream tui.TUIService_SendDatastreamServer) erro
 		for _, h := range hostRows {
 			rows, err := s.DB.GetAllContainersonHost(ctx, h.ID)
 			if err != nil {
-				log.Printf("[TUI Service] fetch containers for host %s: %v", h.MacAddress, err)
+				log.Printf("[TUI Service] fetch containers for host %s: %v", h.AgentID, err)
 				continue
 			}
-			containerRows[h.MacAddress] = rows
+			containerRows[h.AgentID] = rows
 		}
-		// group containers by host mac
+		// group containers by host
 		contByHost := make(map[string][]*tui.ContainerInfo)
+		policies := monitor.LoadPolicies(ctx, s.DB)
 		for _, h := range hostRows {
-			rows := containerRows[h.MacAddress]
+			denials := s.latestDenials(ctx, h)
+			progress := s.latestProgress(ctx, h)
+			versions := s.containerVersions(ctx, h)
+			quarantines := s.quarantines(ctx, h)
+			available := s.availableUpdates(ctx, h)
+			rows := containerRows[h.AgentID]
 			for _, c := range rows {
-				ci := &tui.ContainerInfo{Name: c.Name, Image: c.Image, Status: 0 /* no status col yet */, Watch: c.Watch.Bool}
-				contByHost[h.MacAddress] = append(contByHost[h.MacAddress], ci)
+				ci := &tui.ContainerInfo{Name: c.Name, Image: c.Image, Status: containerStatus(c.State.String), Health: c.Health.String, Watch: c.Watch.Bool, ContainerUid: c.ContainerUid}
+				ci.LastDenial = denials[c.Name]
+				ci.Update = progress[c.Name]
+				if v, ok := versions[c.Name]; ok {
+					ci.PreviousImage = v.PreviousImage.String
+					ci.Pinned = v.Pinned
+				}
+				ci.Quarantine = quarantines[c.Name]
+				ci.ComposeProject = c.ComposeProject.String
+				if u, ok := available[c.Name]; ok && u.CurrentImage == c.Image {
+					// rows recorded for an image the container no longer runs are stale
+					ci.Available = &tui.AvailableUpdate{
+						Image:       u.Image,
+						Digest:      u.Digest,
+						Description: u.Description.String,
+						DetectedAt:  u.DetectedAt.Time.Format(time.RFC3339),
+					}
+				}
+				policy := policies.For(c)
+				ci.Watch, ci.WatchSource, ci.Policy, ci.Drift = policy.Watch, policy.WatchSource, policy.String(), policy.Drift
+				contByHost[h.AgentID] = append(contByHost[h.AgentID], ci)
 			}
 		}
 		hostInfos := make([]*tui.HostInfo, 0, len(hostRows))
@@ -320,7 +345,9 @@ func (s *Server) SendDatastream(stream tui.TUIService_SendDatastreamServer) erro
 				Hostname:      h.Hostname,
 				IpAddress:     h.IpAddress,
 				LastHeartbeat: lastHB,
-				Containers:    contByHost[h.MacAddress],
+				Containers:    contByHost[h.AgentID],
+				AgentId:       h.AgentID,
+				Online:        h.Online,
 			})
 		}
 		orchestratorUp, dbUp, registryUp := servicestatus.GetServiceStatus()
@@ -378,12 +405,12 @@ func (s *Server) SendDatastream(stream tui.TUIService_SendDatastreamServer) erro
 
 func (s *Server) SetWatch(ctx context.Context, req *tui.SetWatchlistRequest) (*tui.SetWatchlistResponse, error) {
 	log.Printf("[TUI Service] SetWatch request: container=%s, host=%s, watch=%v",
-		req.GetContainerName(), req.GetHostMac(), req.GetWatch())
+		req.GetContainerName(), req.GetHostId(), req.GetWatch())
 
 	prams := db.SetWatchStatusParams{
-		Name:       req.GetContainerName(),
-		MacAddress: req.GetHostMac(),
-		Watch:      boolToPgtype(req.GetWatch()),
+		Name:    req.GetContainerName(),
+		AgentID: req.GetHostId(),
+		Watch:   boolToPgtype(req.GetWatch()),
 	}
 
 	if err := s.DB.SetWatchStatus(ctx, prams); err != nil {
@@ -393,6 +420,14 @@ func (s *Server) SetWatch(ctx context.Context, req *tui.SetWatchlistRequest) (*t
 			Message: fmt.Sprintf("Failed to set watch: %v", err),
 		}, err
 	}
+	// A watch set by hand overrides the lighthouse.enable label of the container.
+	if err := s.DB.SetWatchOverride(ctx, db.SetWatchOverrideParams{
+		ContainerName: req.GetContainerName(),
+		Watch:         boolToPgtype(req.GetWatch()),
+		AgentID:       req.GetHostId(),
+	}); err != nil {
+		log.Printf("[TUI Service] Error recording watch override: %v", err)
+	}
 
 	return &tui.SetWatchlistResponse{
 		Success: true,
@@ -410,6 +445,272 @@ func (s *Server) SetCronTime(ctx context.Context, req *tui.SetCronTimeRequest) (
 	}, nil
 }
 
+// CheckNow runs an update check immediately for the fleet, one host, or one container.
+func (s *Server) CheckNow(ctx context.Context, req *tui.CheckNowRequest) (*tui.CheckNowResponse, error) {
+	log.Printf("[TUI Service] CheckNow request: host=%s container=%s dryRun=%v",
+		req.GetHostId(), req.GetContainerUid(), req.GetDryRun())
+
+	scope := monitor.Scope{HostID: req.GetHostId(), ContainerUID: req.GetContainerUid()}
+	plan, err := monitor.CheckNow(ctx, scope, req.GetDryRun())
+	if err != nil {
+		log.Printf("[TUI Service] CheckNow failed: %v", err)
+		return &tui.CheckNowResponse{
+			Success: false,
+			Message: fmt.Sprintf("Check failed: %v", err),
+		}, nil
+	}
+
+	out := make([]*tui.PlannedUpdate, 0, len(plan))
+	for _, item := range plan {
+		out = append(out, &tui.PlannedUpdate{
+			HostId:        item.Host.AgentID,
+			Hostname:      item.Host.Hostname,
+			ContainerUid:  item.Container.ContainerUid,
+			ContainerName: item.Container.Name,
+			CurrentImage:  item.Container.Image,
+			NewImage:      item.Update.NewTag,
+			Description:   item.Update.Description,
+			Dispatched:    item.Dispatched,
+			SkipReason:    item.SkipReason,
+		})
+	}
+	return &tui.CheckNowResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d update(s) found", len(out)),
+		Plan:    out,
+	}, nil
+}
+
+// UpdateContainer forces an update of one container, optionally to a specific tag or digest.
+func (s *Server) UpdateContainer(ctx context.Context, req *tui.UpdateContainerRequest) (*tui.UpdateContainerResponse, error) {
+	log.Printf("[TUI Service] UpdateContainer request: host=%s container=%s tag=%s digest=%s",
+		req.GetHostId(), req.GetContainerUid(), req.GetTag(), req.GetDigest())
+
+	image, err := monitor.UpdateContainer(ctx, req.GetHostId(), req.GetContainerUid(), req.GetTag(), req.GetDigest())
+	if err != nil {
+		log.Printf("[TUI Service] UpdateContainer failed: %v", err)
+		return &tui.UpdateContainerResponse{
+			Success: false,
+			Message: fmt.Sprintf("Update failed: %v", err),
+		}, nil
+	}
+	return &tui.UpdateContainerResponse{
+		Success: true,
+		Message: fmt.Sprintf("Update to %s sent", image),
+		Image:   image,
+	}, nil
+}
+
+// UpdateProject updates the services of a compose project in dependency order.
+func (s *Server) UpdateProject(ctx context.Context, req *tui.UpdateProjectRequest) (*tui.UpdateProjectResponse, error) {
+	log.Printf("[TUI Service] UpdateProject request: host=%s project=%s", req.GetHostId(), req.GetProject())
+
+	services, err := monitor.UpdateProject(ctx, req.GetHostId(), req.GetProject())
+	if err != nil {
+		log.Printf("[TUI Service] UpdateProject failed: %v", err)
+		return &tui.UpdateProjectResponse{
+			Success: false,
+			Message: fmt.Sprintf("Project update failed: %v", err),
+		}, nil
+	}
+	return &tui.UpdateProjectResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("Updating %s: %s", req.GetProject(), strings.Join(services, " -> ")),
+		Services: services,
+	}, nil
+}
+
+// RollbackContainer restores the image a container ran before its last successful update.
+func (s *Server) RollbackContainer(ctx context.Context, req *tui.RollbackContainerRequest) (*tui.RollbackContainerResponse, error) {
+	log.Printf("[TUI Service] RollbackContainer request: host=%s container=%s pin=%v",
+		req.GetHostId(), req.GetContainerUid(), req.GetPin())
+
+	image, err := monitor.RollbackContainer(ctx, req.GetHostId(), req.GetContainerUid(), req.GetPin())
+	if err != nil {
+		log.Printf("[TUI Service] RollbackContainer failed: %v", err)
+		return &tui.RollbackContainerResponse{
+			Success: false,
+			Message: fmt.Sprintf("Rollback failed: %v", err),
+		}, nil
+	}
+	return &tui.RollbackContainerResponse{
+		Success: true,
+		Message: fmt.Sprintf("Rollback to %s sent", image),
+		Image:   image,
+	}, nil
+}
+
+// SetPin pins or unpins a container so automatic updates skip it.
+func (s *Server) SetPin(ctx context.Context, req *tui.SetPinRequest) (*tui.SetPinResponse, error) {
+	log.Printf("[TUI Service] SetPin request: host=%s container=%s pinned=%v",
+		req.GetHostId(), req.GetContainerUid(), req.GetPinned())
+
+	if err := monitor.SetPinned(ctx, req.GetHostId(), req.GetContainerUid(), req.GetPinned()); err != nil {
+		log.Printf("[TUI Service] SetPin failed: %v", err)
+		return &tui.SetPinResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to set pin: %v", err),
+		}, nil
+	}
+	return &tui.SetPinResponse{
+		Success: true,
+		Message: fmt.Sprintf("Pinned set to %v", req.GetPinned()),
+	}, nil
+}
+
+// ClearQuarantine lifts the quarantine of a container's failed candidate images.
+func (s *Server) ClearQuarantine(ctx context.Context, req *tui.ClearQuarantineRequest) (*tui.ClearQuarantineResponse, error) {
+	log.Printf("[TUI Service] ClearQuarantine request: host=%s container=%s", req.GetHostId(), req.GetContainerUid())
+
+	if err := monitor.ClearQuarantine(ctx, req.GetHostId(), req.GetContainerUid()); err != nil {
+		log.Printf("[TUI Service] ClearQuarantine failed: %v", err)
+		return &tui.ClearQuarantineResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to clear quarantine: %v", err),
+		}, nil
+	}
+	return &tui.ClearQuarantineResponse{
+		Success: true,
+		Message: "Quarantine cleared",
+	}, nil
+}
+
+// CancelUpdate aborts the queued or running update of a container.
+func (s *Server) CancelUpdate(ctx context.Context, req *tui.CancelUpdateRequest) (*tui.CancelUpdateResponse, error) {
+	log.Printf("[TUI Service] CancelUpdate request: host=%s container=%s", req.GetHostId(), req.GetContainerUid())
+
+	if err := monitor.CancelUpdate(ctx, req.GetHostId(), req.GetContainerUid()); err != nil {
+		log.Printf("[TUI Service] CancelUpdate failed: %v", err)
+		return &tui.CancelUpdateResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to cancel update: %v", err),
+		}, nil
+	}
+	return &tui.CancelUpdateResponse{
+		Success: true,
+		Message: "Cancel requested",
+	}, nil
+}
+
+// quarantines returns, per container name, the most recently failed quarantined digest on a host.
+func (s *Server) quarantines(ctx context.Context, h db.Host) map[string]*tui.Quarantine {
+	out := make(map[string]*tui.Quarantine)
+	rows, err := s.DB.GetUpdateFailuresForHost(ctx, h.ID)
+	if err != nil {
+		log.Printf("[TUI Service] fetch update failures for host %s: %v", h.AgentID, err)
+		return out
+	}
+	for _, f := range rows {
+		if _, seen := out[f.ContainerName]; seen || !monitor.Quarantined(f) {
+			continue
+		}
+		out[f.ContainerName] = &tui.Quarantine{
+			Image:         f.Image,
+			Digest:        f.Digest,
+			Failures:      f.Failures,
+			LastError:     f.LastError.String,
+			LastFailureAt: f.LastFailureAt.Time.Format(time.RFC3339),
+		}
+	}
+	return out
+}
+
+// availableUpdates returns the updates found for the containers on a host by name.
+func (s *Server) availableUpdates(ctx context.Context, h db.Host) map[string]db.AvailableUpdate {
+	out := make(map[string]db.AvailableUpdate)
+	rows, err := s.DB.GetAvailableUpdatesForHost(ctx, h.ID)
+	if err != nil {
+		log.Printf("[TUI Service] fetch available updates for host %s: %v", h.AgentID, err)
+		return out
+	}
+	for _, u := range rows {
+		out[u.ContainerName] = u
+	}
+	return out
+}
+
+// containerStatus maps the Docker state reported by an agent to its TUI status.
+func containerStatus(state string) tui.ContainerInfo_Status {
+	switch state {
+	case "running":
+		return tui.ContainerInfo_RUNNING
+	case "created":
+		return tui.ContainerInfo_STOPPED
+	case "paused":
+		return tui.ContainerInfo_PAUSED
+	case "restarting":
+		return tui.ContainerInfo_RESTARTING
+	case "exited":
+		return tui.ContainerInfo_EXITED
+	case "dead":
+		return tui.ContainerInfo_DEAD
+	}
+	return tui.ContainerInfo_UNKNOWN
+}
+
+// containerVersions returns the recorded versions and pins of the containers on a host by name.
+func (s *Server) containerVersions(ctx context.Context, h db.Host) map[string]db.ContainerVersion {
+	out := make(map[string]db.ContainerVersion)
+	rows, err := s.DB.GetContainerVersionsForHost(ctx, h.ID)
+	if err != nil {
+		log.Printf("[TUI Service] fetch container versions for host %s: %v", h.AgentID, err)
+		return out
+	}
+	for _, v := range rows {
+		out[v.ContainerName] = v
+	}
+	return out
+}
+
+// latestProgress returns, per container name, the latest update status reported on a host.
+func (s *Server) latestProgress(ctx context.Context, h db.Host) map[string]*tui.UpdateProgress {
+	out := make(map[string]*tui.UpdateProgress)
+	rows, err := s.DB.GetLatestUpdateStatusesForHost(ctx, h.ID)
+	if err != nil {
+		log.Printf("[TUI Service] fetch update status for host %s: %v", h.AgentID, err)
+		return out
+	}
+	for _, u := range rows {
+		updatedAt := ""
+		if u.CreatedAt.Valid {
+			updatedAt = u.CreatedAt.Time.Format(time.RFC3339)
+		}
+		out[u.ContainerName.String] = &tui.UpdateProgress{
+			Stage:     strings.ToUpper(string(u.Stage)),
+			Image:     u.Image,
+			Logs:      u.Logs.String,
+			UpdatedAt: updatedAt,
+		}
+	}
+	return out
+}
+
+// latestDenials returns, per container name, the latest admission decision on a host if it was a denial.
+func (s *Server) latestDenials(ctx context.Context, h db.Host) map[string]*tui.AdmissionDenial {
+	out := make(map[string]*tui.AdmissionDenial)
+	rows, err := s.DB.GetLatestAdmissionDecisionsForHost(ctx, h.ID)
+	if err != nil {
+		log.Printf("[TUI Service] fetch admission decisions for host %s: %v", h.AgentID, err)
+		return out
+	}
+	for _, d := range rows {
+		if d.Allowed {
+			continue
+		}
+		deniedAt := ""
+		if d.CreatedAt.Valid {
+			deniedAt = d.CreatedAt.Time.Format(time.RFC3339)
+		}
+		out[d.ContainerName] = &tui.AdmissionDenial{
+			Webhook:  d.Webhook,
+			Reason:   d.Reason.String,
+			NewImage: d.NewImage,
+			DeniedAt: deniedAt,
+		}
+	}
+	return out
+}
+
 // Helper to convert bool to pgtype.Bool
 func boolToPgtype(b bool) pgtype.Bool {
 	return pgtype.Bool{Bool: b, Valid: true}
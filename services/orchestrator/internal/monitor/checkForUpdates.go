@@ -8,13 +8,14 @@ import (
 
 	registry_monitor "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/registry-monitor"
 	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// CheckForUpdates queries the database for watched containers and asks the
-// registry-monitor service to check if updates are available for them.
-func CheckForUpdates(ctx context.Context, grpcClient registry_monitor.RegistryMonitorServiceClient, queries *db.Queries) (registry_monitor.CheckUpdatesResponse, error) {
+// CheckForUpdates queries the database for watched containers in scope and asks the
+// registry-monitor service to check if updates are available for them, as their policies allow.
+func CheckForUpdates(ctx context.Context, grpcClient registry_monitor.RegistryMonitorServiceClient, queries *db.Queries, policies Policies, scope Scope) (registry_monitor.CheckUpdatesResponse, error) {
 	// Get containers from the database where watchlist is true.
-	containers, err := queries.GetallContainersWhereWatched(ctx)
+	containers, err := watchedContainers(ctx, queries, policies, scope)
 	if err != nil {
 		log.Printf("Failed to get containers from database: %v", err)
 		// Return the error to the caller instead of continuing with a nil slice.
@@ -30,6 +31,7 @@ func CheckForUpdates(ctx context.Context, grpcClient registry_monitor.RegistryMo
 	// Prepare the request for the registry monitor service.
 	var containerInfos []*registry_monitor.ImageInfo
 	for _, c := range containers {
+		policy := policies.For(c)
 		// Correctly parse the repository and tag from the image string.
 		// An image string can be in formats like:
 		// - "ubuntu" (implies "docker.io/library/ubuntu:latest")
@@ -57,9 +59,12 @@ func CheckForUpdates(ctx context.Context, grpcClient registry_monitor.RegistryMo
 		log.Printf("Check image repository=%s tag=%s", repository, tag)
 		// removed logstream
 		imageInfo := &registry_monitor.ImageInfo{
-			ContainerUid: c.ContainerUid,
-			Repository:   repository,
-			Tag:          tag,
+			ContainerUid:  c.ContainerUid,
+			Repository:    repository,
+			Tag:           tag,
+			Policy:        policy.Strategy,
+			TagPattern:    policy.TagPattern,
+			CurrentDigest: c.ImageDigest.String,
 		}
 		containerInfos = append(containerInfos, imageInfo)
 	}
@@ -82,3 +87,35 @@ func CheckForUpdates(ctx context.Context, grpcClient registry_monitor.RegistryMo
 	// Avoid copying entire proto (contains sync primitives); construct lightweight response
 	return registry_monitor.CheckUpdatesResponse{ImagestoUpdate: resp.ImagestoUpdate}, nil
 }
+
+// watchedContainers returns the watched containers that fall within the scope. A container is
+// watched when its TUI override, its lighthouse.enable label, or its default watch flag says so.
+func watchedContainers(ctx context.Context, queries *db.Queries, policies Policies, scope Scope) ([]db.Container, error) {
+	containers, err := queries.GetAllContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hostID pgtype.UUID
+	if scope.HostID != "" {
+		host, err := queries.GetHostByAgentID(ctx, scope.HostID)
+		if err != nil {
+			return nil, err
+		}
+		hostID = host.ID
+	}
+	filtered := containers[:0]
+	for _, c := range containers {
+		if !policies.For(c).Watch {
+			continue
+		}
+		if scope.HostID != "" && c.HostID != hostID {
+			continue
+		}
+		if scope.ContainerUID != "" && c.ContainerUid != scope.ContainerUID {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered, nil
+}
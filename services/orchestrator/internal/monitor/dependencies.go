@@ -0,0 +1,178 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	agentserver "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/agent"
+)
+
+// graphNode is a planned update that waits for, or is waited on by, other updates of the same run.
+type graphNode struct {
+	item *PlanItem
+	deps []string      // keys of the upstream nodes
+	done chan struct{} // closed once the update finished or was skipped
+	ok   bool          // the update completed; only read after done is closed
+}
+
+// buildGraph links the plan items that declare dependencies on each other, possibly across
+// hosts, and orders the linked ones so upstream updates come first. Skipped items wait for
+// nothing, but hold back what depends on them. Dependencies without an update in this run are
+// already satisfied. On a cycle it returns the linked keys with the error.
+func buildGraph(plan []PlanItem) (map[string]*graphNode, []string, error) {
+	nodes := make(map[string]*graphNode, len(plan))
+	for i := range plan {
+		item := &plan[i]
+		nodes[nodeKey(item.Host, item.Container.Name)] = &graphNode{item: item, done: make(chan struct{})}
+	}
+	linked := make(map[string]bool)
+	for key, n := range nodes {
+		if n.item.SkipReason != "" {
+			continue
+		}
+		for _, ref := range n.item.Policy.DependsOn {
+			for _, dep := range resolveDependency(nodes, n.item.Host, ref) {
+				if dep == key {
+					continue
+				}
+				n.deps = append(n.deps, dep)
+				linked[key], linked[dep] = true, true
+			}
+		}
+	}
+	deps := make(map[string][]string, len(linked))
+	for key := range linked {
+		deps[key] = nodes[key].deps
+	}
+	order, err := topoSort(deps)
+	if err != nil {
+		keys := make([]string, 0, len(linked))
+		for key := range linked {
+			keys = append(keys, key)
+		}
+		return nodes, keys, err
+	}
+	return nodes, order, nil
+}
+
+// planGraph skips the updates that can't run as their dependencies require: those in a
+// dependency cycle, and those depending on a skipped update. It returns the updates the
+// dependency graph dispatches.
+func planGraph(plan []PlanItem) map[*PlanItem]bool {
+	nodes, order, err := buildGraph(plan)
+	if err != nil {
+		log.Printf("Skipping dependent updates: %v", err)
+		for _, key := range order {
+			if nodes[key].item.SkipReason == "" {
+				nodes[key].item.SkipReason = err.Error()
+			}
+		}
+		return nil
+	}
+	// Upstream updates come first in the order, so a skip reaches all dependents in one pass
+	graph := make(map[*PlanItem]bool)
+	for _, key := range order {
+		n := nodes[key]
+		if n.item.SkipReason != "" {
+			continue
+		}
+		for _, dep := range n.deps {
+			if upstream := nodes[dep]; upstream.item.SkipReason != "" {
+				n.item.SkipReason = fmt.Sprintf("upstream %s skipped", upstream.name())
+				break
+			}
+		}
+		if n.item.SkipReason == "" {
+			graph[n.item] = true
+		}
+	}
+	return graph
+}
+
+// dispatchGraph runs the updates of the dependency graph planned by planGraph. Each update is
+// sent once all its upstream updates completed; when an upstream fails, its dependents are
+// skipped. Items that are not part of any dependency are left untouched.
+func dispatchGraph(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, plan []PlanItem, trigger string) {
+	nodes, order, err := buildGraph(plan)
+	if err != nil {
+		log.Printf("Skipping dependent updates: %v", err)
+		return
+	}
+	var names, dispatch []string
+	for _, key := range order {
+		if nodes[key].item.SkipReason == "" {
+			names = append(names, nodes[key].name())
+			dispatch = append(dispatch, key)
+		}
+	}
+	if len(dispatch) == 0 {
+		return
+	}
+	log.Printf("Dispatching dependent update(s) in order %s", strings.Join(names, ", "))
+	for _, key := range dispatch {
+		nodes[key].item.Dispatched = true
+		go runGraphNode(context.Background(), queries, agentServer, nodes, key, trigger)
+	}
+}
+
+// resolveDependency returns the keys of the nodes a "host/container" or "container" reference
+// of a container on host points to. The host is matched by agent ID or hostname; a hostname
+// shared by several hosts matches all of them.
+func resolveDependency(nodes map[string]*graphNode, host db.Host, ref string) []string {
+	hostRef, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		key := nodeKey(host, ref)
+		if _, found := nodes[key]; found {
+			return []string{key}
+		}
+		return nil
+	}
+	var keys []string
+	for key, n := range nodes {
+		h := n.item.Host
+		if n.item.Container.Name == name && (h.AgentID == hostRef || h.Hostname == hostRef) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 1 {
+		log.Printf("Dependency %s matches %d hosts, waiting for all of them", ref, len(keys))
+	}
+	return keys
+}
+
+// runGraphNode waits for the upstream updates of a node and then updates it.
+func runGraphNode(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, nodes map[string]*graphNode, key, trigger string) {
+	n := nodes[key]
+	defer close(n.done)
+	for _, dep := range n.deps {
+		upstream := nodes[dep]
+		<-upstream.done
+		if !upstream.ok {
+			log.Printf("Skipping update of %s: upstream %s did not complete", n.name(), upstream.name())
+			return
+		}
+	}
+	item := n.item
+	status, err := dispatchAndWait(ctx, queries, agentServer, item.Host, item.Container, item.Update, item.Update.Digest, trigger)
+	if err == nil && status.GetStage() != orchestrator.UpdateStatus_COMPLETED {
+		err = fmt.Errorf("%s: %s", status.GetStage(), strings.TrimSpace(status.GetLogs()))
+	}
+	if err != nil {
+		log.Printf("Update of %s failed, its dependents are skipped: %v", n.name(), err)
+		return
+	}
+	n.ok = true
+}
+
+// nodeKey identifies a container by the agent ID of its host, as hostnames need not be unique.
+func nodeKey(host db.Host, container string) string {
+	return host.AgentID + "/" + container
+}
+
+func (n *graphNode) name() string {
+	return n.item.Host.Hostname + "/" + n.item.Container.Name
+}
@@ -0,0 +1,200 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	registry_monitor "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/registry-monitor"
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/admission"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	agentserver "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/agent"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var (
+	admissionMu         sync.RWMutex
+	admissionController *admission.Controller
+)
+
+// SetAdmissionController wires the webhooks consulted before every dispatch.
+func SetAdmissionController(c *admission.Controller) {
+	admissionMu.Lock()
+	defer admissionMu.Unlock()
+	admissionController = c
+}
+
+func getAdmissionController() *admission.Controller {
+	admissionMu.RLock()
+	defer admissionMu.RUnlock()
+	return admissionController
+}
+
+// dispatchUpdate builds the update command for a container and sends it to its host agent
+// once the admission webhooks have approved it. A non-empty digest makes the agent pull the
+// image by digest. trigger describes why the update was planned. Rollbacks of a failed project
+// update skip admission, as a denied one would leave the project half updated.
+func dispatchUpdate(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, host db.Host, container db.Container, image *registry_monitor.ImagetoUpdate, digest, trigger string) error {
+	return dispatchCommand(ctx, queries, agentServer, host, container, image, digest, trigger, "")
+}
+
+// dispatchCommand is dispatchUpdate for a command with a given ID; an empty one is generated.
+func dispatchCommand(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, host db.Host, container db.Container, image *registry_monitor.ImagetoUpdate, digest, trigger, commandID string) error {
+	if trigger != triggerProjectRollback {
+		decision := reviewUpdate(ctx, queries, host, container, image, trigger)
+		if !decision.Allowed {
+			return fmt.Errorf("update of %s denied by %s: %s", container.Name, decision.Webhook, decision.Reason)
+		}
+	}
+
+	log.Printf("Sending update command host %s container %s", host.ID, image.ContainerUid)
+	p := updatePolicy(ctx, queries, host, container)
+
+	// Build update command
+	cmd := &orchestrator.UpdateContainerCommand{
+		CommandId:       commandID,
+		ContainerUID:    image.ContainerUid,
+		Image:           image.NewTag,
+		OverrideEnvVars: container.EnvVars,
+		OverridePorts:   portsFromDB(container.Ports),
+		OverrideVolumes: container.Volumes,
+		OverrideNetwork: container.Network.String,
+		AgentId:         host.AgentID, // target agent
+		ContainerName:   container.Name,
+		Digest:          digest,
+		Hooks:           updateHooks(p, trigger),
+		HealthCheck:     healthToProto(p.Health),
+		Spec:            specFromDB(container),
+	}
+
+	send := agentServer.SendCommand
+	if isRollback(trigger) {
+		send = agentServer.SendRollback
+	}
+	if err := send(host.AgentID, cmd); err != nil {
+		return err
+	}
+	log.Printf("Update command sent host %s container %s", host.ID, image.ContainerUid)
+	return nil
+}
+
+// reviewUpdate runs the admission webhooks for a planned update and records their verdict.
+func reviewUpdate(ctx context.Context, queries *db.Queries, host db.Host, container db.Container, image *registry_monitor.ImagetoUpdate, trigger string) admission.Decision {
+	ctrl := getAdmissionController()
+	if !ctrl.Enabled() {
+		return admission.Decision{Allowed: true}
+	}
+
+	review := admission.Review{
+		Host: admission.HostRef{
+			AgentID:    host.AgentID,
+			MacAddress: host.MacAddress,
+			Hostname:   host.Hostname,
+			IPAddress:  host.IpAddress,
+		},
+		Container: admission.ContainerRef{
+			UID:  container.ContainerUid,
+			Name: container.Name,
+		},
+		CurrentImage: container.Image,
+		NewImage:     image.NewTag,
+		Labels:       containerLabels(container),
+		Metadata: map[string]string{
+			"trigger":     trigger,
+			"description": image.Description,
+			"detectedAt":  time.Unix(image.Timestamp, 0).UTC().Format(time.RFC3339),
+		},
+	}
+	decision := ctrl.Review(ctx, review)
+
+	if _, err := queries.InsertAdmissionDecision(ctx, db.InsertAdmissionDecisionParams{
+		HostID:        host.ID,
+		ContainerUid:  container.ContainerUid,
+		ContainerName: container.Name,
+		CurrentImage:  container.Image,
+		NewImage:      image.NewTag,
+		Webhook:       decision.Webhook,
+		Allowed:       decision.Allowed,
+		Reason:        pgtype.Text{String: decision.Reason, Valid: decision.Reason != ""},
+	}); err != nil {
+		log.Printf("Record admission decision for %s failed: %v", container.Name, err)
+	}
+	return decision
+}
+
+// portsFromDB converts DB stored ports ([]byte JSON) into []*orchestrator.PortMapping.
+func portsFromDB(raw []byte) []*orchestrator.PortMapping {
+	var overridePorts []*orchestrator.PortMapping
+	var portStrings []string
+	if err := json.Unmarshal(raw, &portStrings); err != nil {
+		log.Printf("Could not unmarshal ports: %v", err)
+		return nil
+	}
+	for _, p := range portStrings {
+		// expected format from DB: "hostIP:hostPort->containerPort/protocol"
+		parts := strings.Split(p, "->")
+		if len(parts) != 2 {
+			continue
+		}
+		// Left side = hostIP:hostPort
+		hostParts := strings.Split(parts[0], ":")
+		if len(hostParts) != 2 {
+			continue
+		}
+		hostIP := hostParts[0]
+		hostPort, _ := strconv.Atoi(hostParts[1])
+
+		// Right side = containerPort/protocol
+		containerParts := strings.Split(parts[1], "/")
+		if len(containerParts) != 2 {
+			continue
+		}
+		containerPort, _ := strconv.Atoi(containerParts[0])
+		protocol := containerParts[1]
+
+		overridePorts = append(overridePorts, &orchestrator.PortMapping{
+			HostIp:        hostIP,
+			HostPort:      uint32(hostPort),
+			ContainerPort: uint32(containerPort),
+			Protocol:      protocol,
+		})
+	}
+	return overridePorts
+}
+
+// updatePolicy returns the policy of a container from its labels and the policy file,
+// for the settings the agent applies to an update.
+func updatePolicy(ctx context.Context, queries *db.Queries, host db.Host, container db.Container) Policy {
+	var file *db.ContainerPolicy
+	row, err := queries.GetContainerPolicy(ctx, db.GetContainerPolicyParams{HostID: host.ID, ContainerName: container.Name})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Get policy of %s failed: %v", container.Name, err)
+	}
+	if err == nil {
+		file = &row
+	}
+	return PolicyFor(container, file, nil)
+}
+
+// specFromDB decodes the spec an agent reported for a container. Containers reported by older
+// agents have none and are recreated from the override fields.
+func specFromDB(container db.Container) *orchestrator.ContainerSpec {
+	if len(container.Spec) == 0 {
+		return nil
+	}
+	spec := &orchestrator.ContainerSpec{}
+	if err := protojson.Unmarshal(container.Spec, spec); err != nil {
+		log.Printf("Could not unmarshal spec of container %s: %v", container.Name, err)
+		return nil
+	}
+	return spec
+}
@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/policy"
+)
+
+// LabelHealth holds the target of the health check gating an update: a URL, host:port or
+// command depending on ".type". The ".timeout" and ".grace" suffixes set its durations.
+const LabelHealth = "lighthouse.health"
+
+// labelHealth returns the health check declared in the labels of a container, or nil.
+func labelHealth(c db.Container, labels map[string]string) *policy.HealthCheck {
+	h := policy.HealthCheck{
+		Type:    strings.ToLower(labels[LabelHealth+".type"]),
+		Target:  strings.TrimSpace(labels[LabelHealth]),
+		Timeout: labels[LabelHealth+".timeout"],
+		Grace:   labels[LabelHealth+".grace"],
+	}
+	if h == (policy.HealthCheck{}) {
+		return nil
+	}
+	if err := h.Validate(); err != nil {
+		log.Printf("Ignoring %s on container %s: %v", LabelHealth, c.Name, err)
+		return nil
+	}
+	return &h
+}
+
+// fileHealth decodes the health check stored by the policy file reconciler.
+func fileHealth(c db.Container, raw []byte) *policy.HealthCheck {
+	var h policy.HealthCheck
+	if err := json.Unmarshal(raw, &h); err != nil {
+		log.Printf("Could not unmarshal health check of container %s: %v", c.Name, err)
+		return nil
+	}
+	return &h
+}
+
+func healthToProto(h *policy.HealthCheck) *orchestrator.HealthCheck {
+	if h == nil {
+		return nil
+	}
+	out := &orchestrator.HealthCheck{Target: h.Target}
+	switch h.Type {
+	case "http":
+		out.Kind = orchestrator.HealthCheck_HTTP
+	case "tcp":
+		out.Kind = orchestrator.HealthCheck_TCP
+	case "exec":
+		out.Kind = orchestrator.HealthCheck_EXEC
+	case "none":
+		out.Kind = orchestrator.HealthCheck_NONE
+	}
+	if d, err := time.ParseDuration(h.Timeout); err == nil {
+		out.TimeoutSeconds = int32(d.Seconds())
+	}
+	if d, err := time.ParseDuration(h.Grace); err == nil {
+		out.GraceSeconds = int32(d.Seconds())
+	}
+	return out
+}
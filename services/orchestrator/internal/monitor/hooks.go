@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/policy"
+)
+
+// LabelHookPrefix declares a hook per phase: "lighthouse.hook.pre" or "lighthouse.hook.post" hold
+// the command (or URL for http hooks), and the ".type", ".timeout", ".on-failure" and ".method"
+// suffixes configure it.
+const LabelHookPrefix = "lighthouse.hook."
+
+const defaultHookTimeout = 60 * time.Second
+
+// labelHooks returns the hooks declared in the labels of a container.
+func labelHooks(c db.Container, labels map[string]string) []policy.Hook {
+	var hooks []policy.Hook
+	for _, phase := range []string{"pre", "post"} {
+		key := LabelHookPrefix + phase
+		target := strings.TrimSpace(labels[key])
+		if target == "" {
+			continue
+		}
+		h := policy.Hook{
+			Name:      phase + "-label",
+			Phase:     phase,
+			Type:      strings.ToLower(labels[key+".type"]),
+			Timeout:   labels[key+".timeout"],
+			OnFailure: strings.ToLower(labels[key+".on-failure"]),
+			Method:    labels[key+".method"],
+		}
+		if h.Type == "http" {
+			h.URL = target
+		} else {
+			h.Command = target
+		}
+		if err := h.Validate(); err != nil {
+			log.Printf("Ignoring %s on container %s: %v", key, c.Name, err)
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks
+}
+
+// fileHooks decodes the hooks stored by the policy file reconciler.
+func fileHooks(c db.Container, raw []byte) []policy.Hook {
+	var hooks []policy.Hook
+	if err := json.Unmarshal(raw, &hooks); err != nil {
+		log.Printf("Could not unmarshal hooks of container %s: %v", c.Name, err)
+		return nil
+	}
+	return hooks
+}
+
+// updateHooks returns the hooks to send with an update. Rollbacks run none.
+func updateHooks(p Policy, trigger string) []*orchestrator.Hook {
+	if isRollback(trigger) {
+		return nil
+	}
+	return hooksToProto(p.Hooks)
+}
+
+// triggerProjectRollback is the trigger of the rollbacks that undo a failed compose project update.
+const triggerProjectRollback = "project-rollback"
+
+// isRollback reports whether an update restores a previous image rather than installing a candidate.
+func isRollback(trigger string) bool {
+	return strings.Contains(trigger, "rollback")
+}
+
+func hooksToProto(hooks []policy.Hook) []*orchestrator.Hook {
+	out := make([]*orchestrator.Hook, 0, len(hooks))
+	for _, h := range hooks {
+		timeout := defaultHookTimeout
+		if d, err := time.ParseDuration(h.Timeout); err == nil && d > 0 {
+			timeout = d
+		}
+		ph := &orchestrator.Hook{
+			Name:           h.Name,
+			Command:        h.Command,
+			Url:            h.URL,
+			Method:         h.Method,
+			TimeoutSeconds: int32(timeout.Seconds()),
+		}
+		if h.Phase == "post" {
+			ph.Phase = orchestrator.Hook_POST
+		}
+		switch h.Type {
+		case "host":
+			ph.Kind = orchestrator.Hook_HOST
+		case "http":
+			ph.Kind = orchestrator.Hook_HTTP
+		}
+		switch h.OnFailure {
+		case "rollback":
+			ph.OnFailure = orchestrator.Hook_ROLLBACK
+		case "ignore":
+			ph.OnFailure = orchestrator.Hook_IGNORE
+		}
+		out = append(out, ph)
+	}
+	return out
+}
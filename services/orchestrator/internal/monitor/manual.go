@@ -0,0 +1,201 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	registry_monitor "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/registry-monitor"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/jackc/pgx/v5"
+)
+
+// UpdateContainer forces an update of one container, bypassing the update window and cooldown.
+// tag or digest select the target image; with neither set the current image is pulled again.
+// It returns the image reference that was sent to the agent.
+func UpdateContainer(ctx context.Context, hostID, containerUID, tag, digest string) (string, error) {
+	cronMu.Lock()
+	queries := cronArgs.queries
+	agentServer := cronArgs.agentServer
+	cronMu.Unlock()
+	if queries == nil || agentServer == nil {
+		return "", errors.New("monitor dependencies not set")
+	}
+
+	host, container, err := lookupContainer(ctx, queries, hostID, containerUID)
+	if err != nil {
+		return "", err
+	}
+	if !agentServer.IsConnected(host.AgentID) {
+		return "", fmt.Errorf("host %s is offline", host.Hostname)
+	}
+
+	target := imageRef(container.Image, tag, digest)
+	update := &registry_monitor.ImagetoUpdate{
+		ContainerUid: container.ContainerUid,
+		NewTag:       target,
+		Description:  "manual update",
+		Timestamp:    time.Now().Unix(),
+	}
+	if err := dispatchUpdate(ctx, queries, agentServer, host, container, update, "", "manual"); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// RollbackContainer restores the image a container ran before its last successful update.
+// The container is pinned afterwards when pin is set or pin_on_rollback is configured.
+// It returns the image the container is rolled back to.
+func RollbackContainer(ctx context.Context, hostID, containerUID string, pin bool) (string, error) {
+	cronMu.Lock()
+	queries := cronArgs.queries
+	agentServer := cronArgs.agentServer
+	cronMu.Unlock()
+	if queries == nil || agentServer == nil {
+		return "", errors.New("monitor dependencies not set")
+	}
+
+	host, container, err := lookupContainer(ctx, queries, hostID, containerUID)
+	if err != nil {
+		return "", err
+	}
+	if !agentServer.IsConnected(host.AgentID) {
+		return "", fmt.Errorf("host %s is offline", host.Hostname)
+	}
+	version, err := queries.GetContainerVersion(ctx, db.GetContainerVersionParams{HostID: host.ID, ContainerName: container.Name})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("get versions of %s: %w", container.Name, err)
+	}
+	if err != nil || !version.PreviousImage.Valid {
+		return "", fmt.Errorf("no previous version recorded for %s", container.Name)
+	}
+
+	target := version.PreviousImage.String
+	update := &registry_monitor.ImagetoUpdate{
+		ContainerUid: container.ContainerUid,
+		NewTag:       target,
+		Description:  "rollback to previous version",
+		Timestamp:    time.Now().Unix(),
+	}
+	if err := dispatchUpdate(ctx, queries, agentServer, host, container, update, version.PreviousDigest.String, "rollback"); err != nil {
+		return "", err
+	}
+
+	updatesMu.RLock()
+	pin = pin || pinOnRollback
+	updatesMu.RUnlock()
+	if pin {
+		if err := setPinned(ctx, queries, host, container, true); err != nil {
+			log.Printf("Pin %s after rollback failed: %v", container.Name, err)
+		}
+	}
+	return target, nil
+}
+
+// SetPinned pins or unpins a container. Pinned containers are skipped by automatic updates.
+func SetPinned(ctx context.Context, hostID, containerUID string, pinned bool) error {
+	cronMu.Lock()
+	queries := cronArgs.queries
+	cronMu.Unlock()
+	if queries == nil {
+		return errors.New("monitor dependencies not set")
+	}
+	host, container, err := lookupContainer(ctx, queries, hostID, containerUID)
+	if err != nil {
+		return err
+	}
+	return setPinned(ctx, queries, host, container, pinned)
+}
+
+// ClearQuarantine forgets the failed update attempts of a container so quarantined digests are tried again.
+func ClearQuarantine(ctx context.Context, hostID, containerUID string) error {
+	cronMu.Lock()
+	queries := cronArgs.queries
+	cronMu.Unlock()
+	if queries == nil {
+		return errors.New("monitor dependencies not set")
+	}
+	host, container, err := lookupContainer(ctx, queries, hostID, containerUID)
+	if err != nil {
+		return err
+	}
+	if err := queries.ClearUpdateFailures(ctx, db.ClearUpdateFailuresParams{HostID: host.ID, ContainerName: container.Name}); err != nil {
+		return err
+	}
+	log.Printf("Cleared update failures of %s on %s", container.Name, host.Hostname)
+	return nil
+}
+
+// CancelUpdate aborts the update of a container that is queued or running on its agent.
+func CancelUpdate(ctx context.Context, hostID, containerUID string) error {
+	cronMu.Lock()
+	queries := cronArgs.queries
+	agentServer := cronArgs.agentServer
+	cronMu.Unlock()
+	if queries == nil || agentServer == nil {
+		return errors.New("monitor dependencies not set")
+	}
+	host, container, err := lookupContainer(ctx, queries, hostID, containerUID)
+	if err != nil {
+		return err
+	}
+	if err := agentServer.CancelUpdate(host.AgentID, container.Name, "cancelled from the TUI"); err != nil {
+		return err
+	}
+	log.Printf("Requested cancel of the update of %s on %s", container.Name, host.Hostname)
+	return nil
+}
+
+func setPinned(ctx context.Context, queries *db.Queries, host db.Host, container db.Container, pinned bool) error {
+	if err := queries.SetContainerPinned(ctx, db.SetContainerPinnedParams{
+		HostID:        host.ID,
+		ContainerName: container.Name,
+		Pinned:        pinned,
+	}); err != nil {
+		return err
+	}
+	log.Printf("Container %s on %s pinned=%v", container.Name, host.Hostname, pinned)
+	return nil
+}
+
+// lookupContainer loads a container and its host, checking that it runs on hostID when one is given.
+func lookupContainer(ctx context.Context, queries *db.Queries, hostID, containerUID string) (db.Host, db.Container, error) {
+	container, err := queries.GetContainerbyContainerUID(ctx, containerUID)
+	if err != nil {
+		return db.Host{}, db.Container{}, fmt.Errorf("get container %s: %w", containerUID, err)
+	}
+	host, err := queries.GetHostbyContainerUID(ctx, containerUID)
+	if err != nil {
+		return db.Host{}, db.Container{}, fmt.Errorf("get host for container %s: %w", containerUID, err)
+	}
+	if hostID != "" && hostID != host.AgentID {
+		return db.Host{}, db.Container{}, fmt.Errorf("container %s does not run on host %s", container.Name, hostID)
+	}
+	return host, container, nil
+}
+
+// imageRef replaces the tag or digest of an image reference. A digest wins over a tag and
+// an empty tag and digest leave the reference unchanged.
+func imageRef(current, tag, digest string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), ":")
+	digest = strings.TrimPrefix(strings.TrimSpace(digest), "@")
+	if tag == "" && digest == "" {
+		return current
+	}
+
+	repo := current
+	if i := strings.Index(repo, "@"); i >= 0 {
+		repo = repo[:i]
+	}
+	// a colon after the last slash separates the tag; earlier colons belong to a registry port
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	if digest != "" {
+		return repo + "@" + digest
+	}
+	return repo + ":" + tag
+}
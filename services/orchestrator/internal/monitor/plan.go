@@ -0,0 +1,359 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	registry_monitor "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/registry-monitor"
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/config"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	agentserver "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/agent"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Skip reasons reported in an update plan.
+const (
+	SkipOffline       = "host offline"
+	SkipOutsideWindow = "outside update window"
+	SkipCooldown      = "cooldown"
+	SkipPinned        = "pinned"
+	SkipBackoff       = "backing off"
+	SkipQuarantined   = "quarantined"
+	SkipNotifyOnly    = "notify only"
+	SkipApproval      = "approval required"
+)
+
+// Defaults for retrying failed updates.
+const (
+	defaultFailureBackoff  = time.Hour
+	defaultMaxBackoff      = 24 * time.Hour
+	defaultQuarantineAfter = 3
+)
+
+// Scope restricts an update check to one host or one container. The zero value checks the whole fleet.
+type Scope struct {
+	HostID       string // agent ID of the host
+	ContainerUID string
+}
+
+// PlanItem describes what a check decided for one container with an available update.
+type PlanItem struct {
+	Host       db.Host
+	Container  db.Container
+	Policy     Policy
+	Update     *registry_monitor.ImagetoUpdate
+	Dispatched bool
+	SkipReason string // empty when the update is (or would be) dispatched
+}
+
+var (
+	updatesMu       sync.RWMutex
+	window          updateWindow
+	cooldown        time.Duration
+	pinOnRollback   bool
+	failureBackoff  = defaultFailureBackoff
+	maxBackoff      = defaultMaxBackoff
+	quarantineAfter = defaultQuarantineAfter
+)
+
+// SetUpdatePolicy applies the configured update window and cooldown.
+func SetUpdatePolicy(cfg config.Updates) {
+	w, err := parseWindow(cfg.Window)
+	if err != nil {
+		log.Printf("[Plan] Ignoring invalid update window %q: %v", cfg.Window, err)
+	}
+	updatesMu.Lock()
+	defer updatesMu.Unlock()
+	window = w
+	cooldown = cfg.Cooldown
+	pinOnRollback = cfg.PinOnRollback
+	failureBackoff, maxBackoff, quarantineAfter = defaultFailureBackoff, defaultMaxBackoff, defaultQuarantineAfter
+	if cfg.FailureBackoff > 0 {
+		failureBackoff = cfg.FailureBackoff
+	}
+	if cfg.MaxBackoff > 0 {
+		maxBackoff = cfg.MaxBackoff
+	}
+	if cfg.QuarantineAfter > 0 {
+		quarantineAfter = cfg.QuarantineAfter
+	}
+}
+
+// Quarantined reports whether a digest failed often enough to be skipped until cleared.
+func Quarantined(f db.UpdateFailure) bool {
+	updatesMu.RLock()
+	defer updatesMu.RUnlock()
+	return int(f.Failures) >= quarantineAfter
+}
+
+// retryAfter returns when an update to a digest that failed may be attempted again.
+func retryAfter(f db.UpdateFailure) time.Time {
+	updatesMu.RLock()
+	wait, limit := failureBackoff, maxBackoff
+	updatesMu.RUnlock()
+	for i := int32(1); i < f.Failures && wait < limit; i++ {
+		wait *= 2
+	}
+	if wait > limit {
+		wait = limit
+	}
+	return f.LastFailureAt.Time.Add(wait)
+}
+
+// CheckNow runs an update check immediately using the dependencies wired by SetRuntimeDeps.
+// With dryRun set the plan is returned without dispatching or storing anything.
+func CheckNow(ctx context.Context, scope Scope, dryRun bool) ([]PlanItem, error) {
+	cronMu.Lock()
+	grpcClient := cronArgs.registryMonitorClient
+	queries := cronArgs.queries
+	agentServer := cronArgs.agentServer
+	cronMu.Unlock()
+	if grpcClient == nil || queries == nil || agentServer == nil {
+		return nil, errors.New("monitor dependencies not set")
+	}
+	return RunCheck(ctx, grpcClient, queries, agentServer, scope, dryRun, "check-now")
+}
+
+// RunCheck builds an update plan for the scope and, unless dryRun is set, dispatches
+// every update that was not skipped. A dry run writes nothing to the database: it plans with
+// the policies as last reconciled and does not record the updates it finds.
+func RunCheck(ctx context.Context, grpcClient registry_monitor.RegistryMonitorServiceClient, queries *db.Queries, agentServer *agentserver.Server, scope Scope, dryRun bool, trigger string) ([]PlanItem, error) {
+	if !dryRun {
+		reconcilePolicies(ctx)
+	}
+	plan, err := BuildPlan(ctx, grpcClient, queries, agentServer, scope, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		// declared dependencies, possibly across hosts, are dispatched as a DAG first; the
+		// services of a compose project are then updated together, in dependency order
+		dispatchGraph(ctx, queries, agentServer, plan, trigger)
+		dispatchProjects(ctx, queries, agentServer, plan, trigger)
+	}
+	for i := range plan {
+		item := &plan[i]
+		if item.Dispatched {
+			continue
+		}
+		if item.SkipReason != "" {
+			log.Printf("Skipping update of %s on %s: %s", item.Container.Name, item.Host.Hostname, item.SkipReason)
+			continue
+		}
+		if dryRun {
+			log.Printf("Dry run: would update %s on %s to %s", item.Container.Name, item.Host.Hostname, item.Update.NewTag)
+			continue
+		}
+		if err := dispatchUpdate(ctx, queries, agentServer, item.Host, item.Container, item.Update, item.Update.Digest, trigger); err != nil {
+			log.Printf("Dispatch update for container %s failed: %v", item.Container.ContainerUid, err)
+			item.SkipReason = err.Error()
+			continue
+		}
+		item.Dispatched = true
+	}
+	return plan, nil
+}
+
+// BuildPlan checks the scope for available updates and decides which of them may be dispatched
+// now, including the updates held back by their dependencies or compose project. The updates
+// found are recorded unless dryRun is set.
+func BuildPlan(ctx context.Context, grpcClient registry_monitor.RegistryMonitorServiceClient, queries *db.Queries, agentServer *agentserver.Server, scope Scope, dryRun bool) ([]PlanItem, error) {
+	policies := LoadPolicies(ctx, queries)
+	resp, err := CheckForUpdates(ctx, grpcClient, queries, policies, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plan := make([]PlanItem, 0, len(resp.ImagestoUpdate))
+	for _, image := range resp.ImagestoUpdate {
+		// Get the host where this container is running
+		host, err := queries.GetHostbyContainerUID(ctx, image.ContainerUid)
+		if err != nil {
+			log.Printf("Get host for container %s failed: %v", image.ContainerUid, err)
+			continue
+		}
+		// Get container details from DB
+		container, err := queries.GetContainerbyContainerUID(ctx, image.ContainerUid)
+		if err != nil {
+			log.Printf("Get container %s failed: %v", image.ContainerUid, err)
+			continue
+		}
+		policy := policies.For(container)
+		if !dryRun {
+			recordAvailable(ctx, queries, host, container, policy, image)
+		}
+		plan = append(plan, PlanItem{
+			Host:       host,
+			Container:  container,
+			Policy:     policy,
+			Update:     image,
+			SkipReason: skipReason(ctx, queries, agentServer, host, container, policy, image, now),
+		})
+	}
+	// Skips that follow from dependencies and compose projects, so a dry run reports them too
+	graph := planGraph(plan)
+	planProjects(ctx, queries, plan, graph)
+	return plan, nil
+}
+
+// recordAvailable stores an update found for a container, so that it is known even when
+// nothing is dispatched, as for notify-only containers.
+func recordAvailable(ctx context.Context, queries *db.Queries, host db.Host, container db.Container, policy Policy, image *registry_monitor.ImagetoUpdate) {
+	row, err := queries.RecordAvailableUpdate(ctx, db.RecordAvailableUpdateParams{
+		HostID:        host.ID,
+		ContainerName: container.Name,
+		CurrentImage:  container.Image,
+		Image:         image.NewTag,
+		Digest:        image.Digest,
+		Description:   pgtype.Text{String: image.Description, Valid: image.Description != ""},
+	})
+	if err != nil {
+		log.Printf("Record available update of %s failed: %v", container.Name, err)
+		return
+	}
+	if row.DetectedAt.Time.Equal(row.LastCheckedAt.Time) {
+		mode := ""
+		if policy.NotifyOnly {
+			mode = " (notify only)"
+		}
+		log.Printf("Update available for %s on %s: %s%s", container.Name, host.Hostname, image.NewTag, mode)
+	}
+}
+
+// skipReason returns why an update of the container cannot be dispatched at the given time.
+func skipReason(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, host db.Host, container db.Container, policy Policy, image *registry_monitor.ImagetoUpdate, now time.Time) string {
+	if !agentServer.IsConnected(host.AgentID) {
+		return SkipOffline
+	}
+	version, err := queries.GetContainerVersion(ctx, db.GetContainerVersionParams{HostID: host.ID, ContainerName: container.Name})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("Get versions of %s failed: %v", container.Name, err)
+	}
+	if err == nil && version.Pinned {
+		return SkipPinned
+	}
+	if policy.NotifyOnly {
+		return SkipNotifyOnly
+	}
+	if policy.ApprovalRequired {
+		return SkipApproval
+	}
+	if image.Digest != "" {
+		f, err := queries.GetUpdateFailure(ctx, db.GetUpdateFailureParams{HostID: host.ID, ContainerName: container.Name, Digest: image.Digest})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Get update failures of %s failed: %v", container.Name, err)
+		}
+		if err == nil {
+			if Quarantined(f) {
+				return fmt.Sprintf("%s after %d failures", SkipQuarantined, f.Failures)
+			}
+			if until := retryAfter(f); now.Before(until) {
+				return fmt.Sprintf("%s until %s", SkipBackoff, until.Format(time.RFC3339))
+			}
+		}
+	}
+
+	updatesMu.RLock()
+	w, cd := window, cooldown
+	updatesMu.RUnlock()
+	if policy.Schedule != "" {
+		if cw, err := parseWindow(policy.Schedule); err != nil {
+			log.Printf("Ignoring invalid %s %q on %s: %v", LabelSchedule, policy.Schedule, container.Name, err)
+		} else {
+			w = cw
+		}
+	}
+
+	if !w.contains(now) {
+		return fmt.Sprintf("%s (%s)", SkipOutsideWindow, w)
+	}
+	if cd > 0 {
+		last, err := queries.GetLastCompletedUpdate(ctx, db.GetLastCompletedUpdateParams{
+			HostID:        host.ID,
+			ContainerName: pgtype.Text{String: container.Name, Valid: true},
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Get last update of %s failed: %v", container.Name, err)
+		}
+		if err == nil && last.CreatedAt.Valid {
+			if until := last.CreatedAt.Time.Add(cd); now.Before(until) {
+				return fmt.Sprintf("%s until %s", SkipCooldown, until.Format(time.RFC3339))
+			}
+		}
+	}
+	return ""
+}
+
+// updateWindow is a daily local-time window such as "02:00-05:00". It may wrap past midnight.
+// The zero value allows every time of day.
+type updateWindow struct {
+	start, end time.Duration // offsets from midnight
+	set        bool
+}
+
+func parseWindow(s string) (updateWindow, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return updateWindow{}, nil
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return updateWindow{}, fmt.Errorf("expected HH:MM-HH:MM")
+	}
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return updateWindow{}, err
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return updateWindow{}, err
+	}
+	return updateWindow{start: start, end: end, set: true}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	hm := strings.Split(strings.TrimSpace(s), ":")
+	if len(hm) != 2 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	h, err := strconv.Atoi(hm[0])
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("invalid hour in %q", s)
+	}
+	m, err := strconv.Atoi(hm[1])
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid minute in %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+func (w updateWindow) contains(t time.Time) bool {
+	if !w.set || w.start == w.end {
+		return true
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	if w.start < w.end {
+		return offset >= w.start && offset < w.end
+	}
+	// window wraps past midnight, e.g. 22:00-04:00
+	return offset >= w.start || offset < w.end
+}
+
+func (w updateWindow) String() string {
+	if !w.set {
+		return "any time"
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(w.start) + "-" + clock(w.end)
+}
@@ -0,0 +1,235 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/policy"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Container labels that declare an update policy.
+const (
+	LabelEnable     = "lighthouse.enable"      // "true" or "false"
+	LabelPolicy     = "lighthouse.policy"      // patch, minor, major or digest
+	LabelTagPattern = "lighthouse.tag-pattern" // regular expression candidate tags must match
+	LabelSchedule   = "lighthouse.schedule"    // update window such as "02:00-05:00"
+	LabelApproval   = "lighthouse.approval"    // "required" to only update on manual request
+	LabelNotifyOnly = "lighthouse.notify-only" // "true" to check without updating
+	LabelDependsOn  = "lighthouse.depends-on"  // containers to update first: "host/container" or "container", comma separated
+)
+
+// Where the watch state of a container comes from.
+const (
+	SourceDefault = "default"
+	SourceLabel   = "label"
+	SourceFile    = "file"
+	SourceTUI     = "tui"
+)
+
+// Policy is the effective update policy of a container.
+type Policy struct {
+	Watch            bool
+	WatchSource      string
+	Strategy         string // patch, minor, major, digest; empty compares against "latest"
+	TagPattern       string
+	Schedule         string
+	ApprovalRequired bool
+	NotifyOnly       bool
+	Group            string              // rollout group assigned by the policy file
+	DependsOn        []string            // containers that must finish updating first
+	Hooks            []policy.Hook       // commands run by the agent before and after an update
+	Health           *policy.HealthCheck // gates the completion of an update
+	Rules            string              // policy file rules that matched, comma separated
+	Drift            string              // set when a TUI override contradicts the policy file
+}
+
+// PolicyFor combines the labels of a container with the policy file and the overrides set
+// by hand in the TUI, in that order of precedence. file and override may be nil.
+func PolicyFor(c db.Container, file *db.ContainerPolicy, override *db.ContainerOverride) Policy {
+	labels := containerLabels(c)
+	p := Policy{
+		Watch:            c.Watch.Bool || !c.Watch.Valid,
+		WatchSource:      SourceDefault,
+		Strategy:         strings.ToLower(labels[LabelPolicy]),
+		TagPattern:       labels[LabelTagPattern],
+		Schedule:         labels[LabelSchedule],
+		ApprovalRequired: strings.EqualFold(labels[LabelApproval], "required"),
+		NotifyOnly:       labelBool(labels, LabelNotifyOnly),
+		DependsOn:        splitList(labels[LabelDependsOn]),
+		Hooks:            labelHooks(c, labels),
+		Health:           labelHealth(c, labels),
+	}
+	if _, ok := labels[LabelEnable]; ok {
+		p.Watch, p.WatchSource = labelBool(labels, LabelEnable), SourceLabel
+	}
+	if file != nil {
+		if file.Watch.Valid {
+			p.Watch, p.WatchSource = file.Watch.Bool, SourceFile
+		}
+		if file.Strategy.Valid {
+			p.Strategy = file.Strategy.String
+		}
+		if file.TagPattern.Valid {
+			p.TagPattern = file.TagPattern.String
+		}
+		if file.Schedule.Valid {
+			p.Schedule = file.Schedule.String
+		}
+		if file.ApprovalRequired.Valid {
+			p.ApprovalRequired = file.ApprovalRequired.Bool
+		}
+		if file.NotifyOnly.Valid {
+			p.NotifyOnly = file.NotifyOnly.Bool
+		}
+		if file.DependsOn != nil {
+			p.DependsOn = file.DependsOn
+		}
+		if file.Hooks != nil {
+			p.Hooks = fileHooks(c, file.Hooks)
+		}
+		if file.Health != nil {
+			p.Health = fileHealth(c, file.Health)
+		}
+		p.Group, p.Rules = file.RolloutGroup.String, file.Rules
+	}
+	if override != nil && override.Watch.Valid {
+		if file != nil && file.Watch.Valid && file.Watch.Bool != override.Watch.Bool {
+			p.Drift = fmt.Sprintf("watch=%v set in TUI, policy file (%s) says %v", override.Watch.Bool, file.Rules, file.Watch.Bool)
+		}
+		p.Watch, p.WatchSource = override.Watch.Bool, SourceTUI
+	}
+	switch p.Strategy {
+	case "", "patch", "minor", "major", "digest":
+	default:
+		log.Printf("Ignoring unknown update strategy %q on container %s", p.Strategy, c.Name)
+		p.Strategy = ""
+	}
+	return p
+}
+
+// String summarizes the policy for display.
+func (p Policy) String() string {
+	var parts []string
+	if p.Strategy != "" {
+		parts = append(parts, p.Strategy)
+	}
+	if p.Schedule != "" {
+		parts = append(parts, p.Schedule)
+	}
+	if p.ApprovalRequired {
+		parts = append(parts, "approval")
+	}
+	if p.NotifyOnly {
+		parts = append(parts, "notify-only")
+	}
+	if p.Group != "" {
+		parts = append(parts, "group "+p.Group)
+	}
+	if len(p.Hooks) > 0 {
+		parts = append(parts, fmt.Sprintf("%d hook(s)", len(p.Hooks)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Policies holds the policy file rows and TUI overrides of all containers.
+type Policies struct {
+	file      map[policyKey]*db.ContainerPolicy
+	overrides map[policyKey]*db.ContainerOverride
+}
+
+type policyKey struct {
+	hostID pgtype.UUID
+	name   string
+}
+
+// LoadPolicies reads the stored policy file rows and overrides of all containers.
+func LoadPolicies(ctx context.Context, queries *db.Queries) Policies {
+	p := Policies{
+		file:      make(map[policyKey]*db.ContainerPolicy),
+		overrides: make(map[policyKey]*db.ContainerOverride),
+	}
+	rows, err := queries.GetAllContainerPolicies(ctx)
+	if err != nil {
+		log.Printf("Get container policies failed: %v", err)
+	}
+	for i := range rows {
+		p.file[policyKey{rows[i].HostID, rows[i].ContainerName}] = &rows[i]
+	}
+	overrides, err := queries.GetAllContainerOverrides(ctx)
+	if err != nil {
+		log.Printf("Get container overrides failed: %v", err)
+	}
+	for i := range overrides {
+		p.overrides[policyKey{overrides[i].HostID, overrides[i].ContainerName}] = &overrides[i]
+	}
+	return p
+}
+
+// For returns the effective policy of a container.
+func (p Policies) For(c db.Container) Policy {
+	k := policyKey{c.HostID, c.Name}
+	return PolicyFor(c, p.file[k], p.overrides[k])
+}
+
+// PolicyReconciler brings stored policies in line with the policy file.
+type PolicyReconciler interface {
+	Reconcile(ctx context.Context, reason string) error
+}
+
+var (
+	reconcilerMu sync.Mutex
+	reconciler   PolicyReconciler
+)
+
+// SetPolicyReconciler registers the reconciler run before every update check so that
+// containers seen since the last reconcile get their policies too.
+func SetPolicyReconciler(r PolicyReconciler) {
+	reconcilerMu.Lock()
+	defer reconcilerMu.Unlock()
+	reconciler = r
+}
+
+func reconcilePolicies(ctx context.Context) {
+	reconcilerMu.Lock()
+	r := reconciler
+	reconcilerMu.Unlock()
+	if r == nil {
+		return
+	}
+	if err := r.Reconcile(ctx, "update check"); err != nil {
+		log.Printf("Reconcile policies failed: %v", err)
+	}
+}
+
+func containerLabels(c db.Container) map[string]string {
+	labels := map[string]string{}
+	if len(c.Labels) > 0 {
+		if err := json.Unmarshal(c.Labels, &labels); err != nil {
+			log.Printf("Could not unmarshal labels for container %s: %v", c.Name, err)
+		}
+	}
+	return labels
+}
+
+// splitList splits a comma separated label value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func labelBool(labels map[string]string, key string) bool {
+	b, _ := strconv.ParseBool(labels[key])
+	return b
+}
@@ -0,0 +1,309 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	orchestrator "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/host-agents"
+	registry_monitor "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/registry-monitor"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	agentserver "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/grpc/agent"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// outcomeTimeout bounds how long a project or dependent update waits for one update to finish.
+const outcomeTimeout = 15 * time.Minute
+
+// projectUpdate is one service of a compose project to be updated.
+type projectUpdate struct {
+	container db.Container
+	update    *registry_monitor.ImagetoUpdate
+	digest    string
+}
+
+// doneUpdate is a service updated by a project update, kept to roll it back.
+type doneUpdate struct {
+	container db.Container
+	newUID    string
+	previous  string
+	digest    string
+}
+
+// UpdateProject updates every service of a compose project on a host that has an update
+// available, in dependency order. Like UpdateContainer it bypasses the update window,
+// cooldown and pins. It returns the services that will be updated, in order; the update
+// itself runs in the background.
+func UpdateProject(ctx context.Context, hostID, project string) ([]string, error) {
+	cronMu.Lock()
+	grpcClient := cronArgs.registryMonitorClient
+	queries := cronArgs.queries
+	agentServer := cronArgs.agentServer
+	cronMu.Unlock()
+	if grpcClient == nil || queries == nil || agentServer == nil {
+		return nil, errors.New("monitor dependencies not set")
+	}
+
+	plan, err := BuildPlan(ctx, grpcClient, queries, agentServer, Scope{HostID: hostID}, false)
+	if err != nil {
+		return nil, err
+	}
+	var host db.Host
+	var updates []projectUpdate
+	for _, item := range plan {
+		if item.Container.ComposeProject.String != project {
+			continue
+		}
+		if item.SkipReason == SkipOffline {
+			return nil, fmt.Errorf("host %s is offline", item.Host.Hostname)
+		}
+		host = item.Host
+		updates = append(updates, projectUpdate{container: item.Container, update: item.Update, digest: item.Update.Digest})
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("no updates available for project %s", project)
+	}
+
+	ordered, err := orderProject(ctx, queries, host, project, updates)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(ordered))
+	for i, u := range ordered {
+		names[i] = u.container.Name
+	}
+	go runProjectUpdate(context.Background(), queries, agentServer, host, project, ordered, "manual")
+	return names, nil
+}
+
+// projectGroup is the dispatchable plan items of one compose project on one host.
+type projectGroup struct {
+	project string
+	items   []*PlanItem
+}
+
+// projectGroups groups the dispatchable plan items that belong to a compose project, leaving
+// out those taken.
+func projectGroups(plan []PlanItem, taken func(*PlanItem) bool) []projectGroup {
+	type projectKey struct {
+		hostID  pgtype.UUID
+		project string
+	}
+	index := make(map[projectKey]int)
+	var groups []projectGroup
+	for i := range plan {
+		item := &plan[i]
+		project := item.Container.ComposeProject.String
+		if item.SkipReason != "" || project == "" || taken(item) {
+			continue
+		}
+		k := projectKey{item.Host.ID, project}
+		if _, ok := index[k]; !ok {
+			index[k] = len(groups)
+			groups = append(groups, projectGroup{project: project})
+		}
+		groups[index[k]].items = append(groups[index[k]].items, item)
+	}
+	return groups
+}
+
+func (g projectGroup) updates() []projectUpdate {
+	updates := make([]projectUpdate, len(g.items))
+	for i, item := range g.items {
+		updates[i] = projectUpdate{container: item.Container, update: item.Update, digest: item.Update.Digest}
+	}
+	return updates
+}
+
+// planProjects skips the updates of the compose projects that can't be ordered, e.g. for a
+// dependency cycle. Updates of the dependency graph are dispatched on their own and left out.
+func planProjects(ctx context.Context, queries *db.Queries, plan []PlanItem, graph map[*PlanItem]bool) {
+	for _, g := range projectGroups(plan, func(item *PlanItem) bool { return graph[item] }) {
+		host := g.items[0].Host
+		if _, err := orderProject(ctx, queries, host, g.project, g.updates()); err != nil {
+			log.Printf("Skipping update of compose project %s on %s: %v", g.project, host.Hostname, err)
+			for _, item := range g.items {
+				item.SkipReason = err.Error()
+			}
+		}
+	}
+}
+
+// dispatchProjects starts a project update for the dispatchable plan items that belong to a
+// compose project and marks them dispatched. Items outside a project are left untouched.
+func dispatchProjects(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, plan []PlanItem, trigger string) {
+	for _, g := range projectGroups(plan, func(item *PlanItem) bool { return item.Dispatched }) {
+		host := g.items[0].Host
+		ordered, err := orderProject(ctx, queries, host, g.project, g.updates())
+		if err != nil {
+			log.Printf("Skipping update of compose project %s on %s: %v", g.project, host.Hostname, err)
+			for _, item := range g.items {
+				item.SkipReason = err.Error()
+			}
+			continue
+		}
+		for _, item := range g.items {
+			item.Dispatched = true
+		}
+		go runProjectUpdate(context.Background(), queries, agentServer, host, g.project, ordered, trigger)
+	}
+}
+
+// orderProject sorts the updates of a project so every service comes after the services it
+// depends on. Services without an update still count as dependencies but are not updated.
+func orderProject(ctx context.Context, queries *db.Queries, host db.Host, project string, updates []projectUpdate) ([]projectUpdate, error) {
+	members, err := queries.GetProjectContainers(ctx, db.GetProjectContainersParams{
+		HostID:         host.ID,
+		ComposeProject: pgtype.Text{String: project, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get containers of project %s: %w", project, err)
+	}
+	deps := make(map[string][]string, len(members))
+	for _, c := range members {
+		deps[serviceName(c)] = c.DependsOn
+	}
+	order, err := topoSort(deps)
+	if err != nil {
+		return nil, fmt.Errorf("project %s: %w", project, err)
+	}
+	rank := make(map[string]int, len(order))
+	for i, name := range order {
+		rank[name] = i
+	}
+	ordered := append([]projectUpdate(nil), updates...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank[serviceName(ordered[i].container)] < rank[serviceName(ordered[j].container)]
+	})
+	return ordered, nil
+}
+
+// topoSort orders the keys of deps so dependencies come first, breaking ties by name.
+// Dependencies that are not keys of deps are ignored.
+func topoSort(deps map[string][]string) ([]string, error) {
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(deps))
+	var order []string
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// runProjectUpdate updates the services of a project one at a time, waiting for each to
+// complete. When one fails, the services it already updated are rolled back in reverse order.
+// The failed service itself is restored by its agent.
+func runProjectUpdate(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, host db.Host, project string, updates []projectUpdate, trigger string) {
+	log.Printf("Updating compose project %s on %s (%d service(s))", project, host.Hostname, len(updates))
+	var done []doneUpdate
+	for _, u := range updates {
+		status, err := dispatchAndWait(ctx, queries, agentServer, host, u.container, u.update, u.digest, trigger)
+		if err == nil && status.GetStage() != orchestrator.UpdateStatus_COMPLETED {
+			err = fmt.Errorf("%s: %s", status.GetStage(), strings.TrimSpace(status.GetLogs()))
+		}
+		if err != nil {
+			log.Printf("Update of %s in compose project %s failed: %v", u.container.Name, project, err)
+			rollbackProject(ctx, queries, agentServer, host, project, done)
+			return
+		}
+		done = append(done, doneUpdate{
+			container: u.container,
+			newUID:    status.GetNewContainerUid(),
+			previous:  status.GetPreviousImage(),
+			digest:    status.GetPreviousDigest(),
+		})
+	}
+	log.Printf("Compose project %s on %s updated", project, host.Hostname)
+}
+
+// rollbackProject restores the previous images of the services a failed project update changed.
+func rollbackProject(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, host db.Host, project string, done []doneUpdate) {
+	if len(done) == 0 {
+		return
+	}
+	log.Printf("Rolling back compose project %s on %s (%d service(s))", project, host.Hostname, len(done))
+	for i := len(done) - 1; i >= 0; i-- {
+		d := done[i]
+		if d.previous == "" || d.newUID == "" {
+			log.Printf("Cannot roll back %s in project %s: previous version unknown", d.container.Name, project)
+			continue
+		}
+		rollback := &registry_monitor.ImagetoUpdate{
+			ContainerUid: d.newUID,
+			NewTag:       d.previous,
+			Description:  "compose project rollback",
+			Timestamp:    time.Now().Unix(),
+		}
+		status, err := dispatchAndWait(ctx, queries, agentServer, host, d.container, rollback, d.digest, triggerProjectRollback)
+		if err != nil {
+			log.Printf("Rollback of %s in project %s failed: %v", d.container.Name, project, err)
+			continue
+		}
+		if status.GetStage() != orchestrator.UpdateStatus_COMPLETED {
+			log.Printf("Rollback of %s in project %s failed: %s", d.container.Name, project, strings.TrimSpace(status.GetLogs()))
+		}
+	}
+}
+
+// dispatchAndWait dispatches an update and waits for its first terminal status.
+func dispatchAndWait(ctx context.Context, queries *db.Queries, agentServer *agentserver.Server, host db.Host, container db.Container, image *registry_monitor.ImagetoUpdate, digest, trigger string) (*orchestrator.UpdateStatus, error) {
+	commandID := uuid.NewString()
+	outcome, stop := agentServer.WaitOutcome(commandID)
+	defer stop()
+	if err := dispatchCommand(ctx, queries, agentServer, host, container, image, digest, trigger, commandID); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, outcomeTimeout)
+	defer cancel()
+	select {
+	case status := <-outcome:
+		return status, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no outcome for %s: %w", container.Name, ctx.Err())
+	}
+}
+
+// serviceName returns the compose service of a container, falling back to its name.
+func serviceName(c db.Container) string {
+	if c.ComposeService.Valid && c.ComposeService.String != "" {
+		return c.ComposeService.String
+	}
+	return c.Name
+}
@@ -0,0 +1,444 @@
+// Package policy loads declarative update policies from YAML and reconciles them into the database.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/config"
+	db "github.com/MadhavKrishanGoswami/Lighthouse/services/orchestrator/internal/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultPollInterval = 10 * time.Second
+
+// File is the layout of a policy file.
+type File struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Match selects containers. Empty fields match everything; hostname, name and image are globs.
+type Match struct {
+	Hostname string            `yaml:"hostname"`
+	Name     string            `yaml:"name"`
+	Image    string            `yaml:"image"`
+	Labels   map[string]string `yaml:"labels"`
+}
+
+// Rule assigns policy to the containers it matches. Rules apply in file order and later
+// rules override the fields earlier ones set; unset fields are left alone.
+type Rule struct {
+	Name       string  `yaml:"name"`
+	Match      Match   `yaml:"match"`
+	Watch      *bool   `yaml:"watch"`
+	Strategy   *string `yaml:"strategy"` // patch, minor, major or digest
+	TagPattern *string `yaml:"tag_pattern"`
+	Schedule   *string `yaml:"schedule"` // update window such as "02:00-05:00"
+	Approval   *string `yaml:"approval"` // "required" or "auto"
+	NotifyOnly *bool   `yaml:"notify_only"`
+	Group      *string `yaml:"group"` // rollout group
+	// DependsOn lists containers that must finish updating first, as "host/container",
+	// the host given by hostname or agent ID, or "container" for one on the same host.
+	DependsOn []string `yaml:"depends_on"`
+	Hooks     []Hook   `yaml:"hooks"`
+	// Health decides when an updated container is healthy.
+	Health *HealthCheck `yaml:"health"`
+}
+
+// Hook is a step the agent runs around an update of a container.
+type Hook struct {
+	Name      string `yaml:"name" json:"name,omitempty"`
+	Phase     string `yaml:"phase" json:"phase"`                     // "pre" or "post"
+	Type      string `yaml:"type" json:"type,omitempty"`             // "exec" (default), "host" or "http"
+	Command   string `yaml:"command" json:"command,omitempty"`       // exec and host hooks
+	URL       string `yaml:"url" json:"url,omitempty"`               // http hooks
+	Method    string `yaml:"method" json:"method,omitempty"`         // http hooks, defaults to POST
+	Timeout   string `yaml:"timeout" json:"timeout,omitempty"`       // e.g. "5m", defaults to 60s
+	OnFailure string `yaml:"on_failure" json:"on_failure,omitempty"` // "abort" (default), "rollback" or "ignore"
+}
+
+// Validate checks that the hook can be run.
+func (h Hook) Validate() error {
+	if h.Phase != "pre" && h.Phase != "post" {
+		return fmt.Errorf("hook phase must be \"pre\" or \"post\", got %q", h.Phase)
+	}
+	switch h.Type {
+	case "", "exec", "host":
+		if h.Command == "" {
+			return fmt.Errorf("%s hook needs a command", h.Phase)
+		}
+	case "http":
+		if h.URL == "" {
+			return fmt.Errorf("%s hook needs a url", h.Phase)
+		}
+	default:
+		return fmt.Errorf("unknown hook type %q", h.Type)
+	}
+	if h.Timeout != "" {
+		if _, err := time.ParseDuration(h.Timeout); err != nil {
+			return fmt.Errorf("invalid hook timeout %q", h.Timeout)
+		}
+	}
+	switch h.OnFailure {
+	case "", "abort", "rollback", "ignore":
+	default:
+		return fmt.Errorf("hook on_failure must be abort, rollback or ignore, got %q", h.OnFailure)
+	}
+	return nil
+}
+
+// HealthCheck decides when the new container of an update is healthy.
+type HealthCheck struct {
+	Type    string `yaml:"type" json:"type,omitempty"`       // "docker" (default), "http", "tcp", "exec" or "none"
+	Target  string `yaml:"target" json:"target,omitempty"`   // URL, host:port or command; "{ip}" is the container's IP
+	Timeout string `yaml:"timeout" json:"timeout,omitempty"` // time to become healthy, defaults to 60s
+	Grace   string `yaml:"grace" json:"grace,omitempty"`     // time it must then keep running, defaults to 10s
+}
+
+// Validate checks that the health check can be run.
+func (h HealthCheck) Validate() error {
+	switch h.Type {
+	case "", "docker", "none":
+	case "http", "tcp", "exec":
+		if h.Target == "" {
+			return fmt.Errorf("%s health check needs a target", h.Type)
+		}
+	default:
+		return fmt.Errorf("unknown health check type %q", h.Type)
+	}
+	for _, d := range []string{h.Timeout, h.Grace} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("invalid health check duration %q", d)
+		}
+	}
+	return nil
+}
+
+// Load reads a policy file, or every *.yaml and *.yml file of a directory in name order.
+func Load(p string) ([]Rule, error) {
+	files, err := policyFiles(p)
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", f, err)
+		}
+		var pf File
+		if err := yaml.Unmarshal(raw, &pf); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", f, err)
+		}
+		for i, r := range pf.Rules {
+			if r.Name == "" {
+				r.Name = fmt.Sprintf("%s#%d", filepath.Base(f), i+1)
+			}
+			if err := r.validate(); err != nil {
+				return nil, fmt.Errorf("%s: rule %s: %w", f, r.Name, err)
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+func policyFiles(p string) ([]string, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{p}, nil
+	}
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, filepath.Join(p, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (r Rule) validate() error {
+	for _, glob := range []string{r.Match.Hostname, r.Match.Name, r.Match.Image} {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q", glob)
+		}
+	}
+	if r.Strategy != nil {
+		switch *r.Strategy {
+		case "", "patch", "minor", "major", "digest":
+		default:
+			return fmt.Errorf("unknown strategy %q", *r.Strategy)
+		}
+	}
+	if r.Approval != nil && *r.Approval != "required" && *r.Approval != "auto" {
+		return fmt.Errorf("approval must be \"required\" or \"auto\", got %q", *r.Approval)
+	}
+	for _, h := range r.Hooks {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Health != nil {
+		if err := r.Health.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m Match) matches(host db.Host, c db.Container, labels map[string]string) bool {
+	if !globMatch(m.Hostname, host.Hostname) || !globMatch(m.Name, c.Name) || !globMatch(m.Image, c.Image) {
+		return false
+	}
+	for k, v := range m.Labels {
+		if got, ok := labels[k]; !ok || (v != "" && v != got) {
+			return false
+		}
+	}
+	return true
+}
+
+func globMatch(glob, s string) bool {
+	if glob == "" {
+		return true
+	}
+	ok, _ := path.Match(glob, s)
+	return ok
+}
+
+// Evaluate applies the rules to a container. It returns false when no rule matched.
+func Evaluate(rules []Rule, host db.Host, c db.Container) (db.UpsertContainerPolicyParams, bool) {
+	labels := map[string]string{}
+	if len(c.Labels) > 0 {
+		if err := json.Unmarshal(c.Labels, &labels); err != nil {
+			log.Printf("[Policy] Could not unmarshal labels for container %s: %v", c.Name, err)
+		}
+	}
+
+	out := db.UpsertContainerPolicyParams{HostID: host.ID, ContainerName: c.Name}
+	var matched []string
+	for _, r := range rules {
+		if !r.Match.matches(host, c, labels) {
+			continue
+		}
+		matched = append(matched, r.Name)
+		if r.Watch != nil {
+			out.Watch = pgtype.Bool{Bool: *r.Watch, Valid: true}
+		}
+		if r.Strategy != nil {
+			out.Strategy = pgtype.Text{String: *r.Strategy, Valid: true}
+		}
+		if r.TagPattern != nil {
+			out.TagPattern = pgtype.Text{String: *r.TagPattern, Valid: true}
+		}
+		if r.Schedule != nil {
+			out.Schedule = pgtype.Text{String: *r.Schedule, Valid: true}
+		}
+		if r.Approval != nil {
+			out.ApprovalRequired = pgtype.Bool{Bool: *r.Approval == "required", Valid: true}
+		}
+		if r.NotifyOnly != nil {
+			out.NotifyOnly = pgtype.Bool{Bool: *r.NotifyOnly, Valid: true}
+		}
+		if r.Group != nil {
+			out.RolloutGroup = pgtype.Text{String: *r.Group, Valid: true}
+		}
+		if r.DependsOn != nil {
+			out.DependsOn = r.DependsOn
+		}
+		if r.Hooks != nil {
+			if hooks, err := json.Marshal(r.Hooks); err != nil {
+				log.Printf("[Policy] Could not marshal hooks of rule %s: %v", r.Name, err)
+			} else {
+				out.Hooks = hooks
+			}
+		}
+		if r.Health != nil {
+			if health, err := json.Marshal(r.Health); err != nil {
+				log.Printf("[Policy] Could not marshal health check of rule %s: %v", r.Name, err)
+			} else {
+				out.Health = health
+			}
+		}
+	}
+	out.Rules = strings.Join(matched, ",")
+	return out, len(matched) > 0
+}
+
+// Reconciler keeps the container_policies table in line with the policy file.
+type Reconciler struct {
+	cfg config.Policies
+	// conn is the reconciler's own connection, so its transaction is not shared with the
+	// queries of other goroutines.
+	conn *pgx.Conn
+
+	mu          sync.Mutex
+	fingerprint string
+}
+
+// NewReconciler creates a Reconciler on a dedicated connection. It does nothing when no policy
+// path is configured.
+func NewReconciler(cfg config.Policies, conn *pgx.Conn) *Reconciler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	return &Reconciler{cfg: cfg, conn: conn}
+}
+
+// Enabled reports whether a policy path is configured.
+func (r *Reconciler) Enabled() bool {
+	return r != nil && r.cfg.Path != ""
+}
+
+// Reconcile loads the policy file and rewrites the policies of all known containers in one
+// transaction, so checks running meanwhile see either the old or the new policies. Watch
+// states set by hand that contradict the file are reported as drift and, with enforce set,
+// dropped. reason is logged to tell startup, file changes and SIGHUP apart.
+func (r *Reconciler) Reconcile(ctx context.Context, reason string) error {
+	if !r.Enabled() {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules, err := Load(r.cfg.Path)
+	if err != nil {
+		// keep the last good policies rather than wiping them on a broken edit
+		return fmt.Errorf("load policy file: %w", err)
+	}
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // no-op once committed
+	queries := db.New(r.conn).WithTx(tx)
+
+	hosts, err := queries.GetAllHosts(ctx)
+	if err != nil {
+		return fmt.Errorf("get hosts: %w", err)
+	}
+	overrides, err := queries.GetAllContainerOverrides(ctx)
+	if err != nil {
+		return fmt.Errorf("get overrides: %w", err)
+	}
+	type key struct {
+		hostID pgtype.UUID
+		name   string
+	}
+	handSet := make(map[key]db.ContainerOverride, len(overrides))
+	for _, o := range overrides {
+		handSet[key{o.HostID, o.ContainerName}] = o
+	}
+
+	if err := queries.DeleteAllContainerPolicies(ctx); err != nil {
+		return fmt.Errorf("clear policies: %w", err)
+	}
+	applied, drifted := 0, 0
+	for _, h := range hosts {
+		// A failed statement aborts the transaction, so any error ends the reconcile
+		containers, err := queries.GetAllContainersonHost(ctx, h.ID)
+		if err != nil {
+			return fmt.Errorf("get containers of host %s: %w", h.Hostname, err)
+		}
+		for _, c := range containers {
+			params, ok := Evaluate(rules, h, c)
+			if !ok {
+				continue
+			}
+			if err := queries.UpsertContainerPolicy(ctx, params); err != nil {
+				return fmt.Errorf("store policy of %s/%s: %w", h.Hostname, c.Name, err)
+			}
+			applied++
+
+			o, ok := handSet[key{h.ID, c.Name}]
+			if !ok || !o.Watch.Valid || !params.Watch.Valid || o.Watch.Bool == params.Watch.Bool {
+				continue
+			}
+			drifted++
+			log.Printf("[Policy] Drift: %s/%s watch set to %v by hand, policy file (%s) says %v",
+				h.Hostname, c.Name, o.Watch.Bool, params.Rules, params.Watch.Bool)
+			if r.cfg.Enforce {
+				if err := queries.ClearWatchOverride(ctx, db.ClearWatchOverrideParams{HostID: h.ID, ContainerName: c.Name}); err != nil {
+					return fmt.Errorf("drop override of %s/%s: %w", h.Hostname, c.Name, err)
+				}
+			}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit policies: %w", err)
+	}
+	log.Printf("[Policy] Reconciled %d rule(s) onto %d container(s), %d drifted (%s)", len(rules), applied, drifted, reason)
+	return nil
+}
+
+// Run reconciles once and then again whenever the policy file changes, until ctx is done.
+func (r *Reconciler) Run(ctx context.Context) {
+	if !r.Enabled() {
+		return
+	}
+	r.fingerprint, _ = fingerprint(r.cfg.Path)
+	if err := r.Reconcile(ctx, "startup"); err != nil {
+		log.Printf("[Policy] %v", err)
+	}
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fp, err := fingerprint(r.cfg.Path)
+			if err != nil {
+				log.Printf("[Policy] Check policy file failed: %v", err)
+				continue
+			}
+			if fp == r.fingerprint {
+				continue
+			}
+			r.fingerprint = fp
+			if err := r.Reconcile(ctx, "file changed"); err != nil {
+				log.Printf("[Policy] %v", err)
+			}
+		}
+	}
+}
+
+// fingerprint summarizes the names, sizes and modification times of the policy files.
+func fingerprint(p string) (string, error) {
+	files, err := policyFiles(p)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", f, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
@@ -208,6 +208,25 @@ func Monitor(checkforupdates *registry_monitor.CheckUpdatesRequest) (*registry_m
 				return
 			}
 
+			// A tag pattern on its own picks the highest version tag matching it.
+			if img.Policy == "" && img.TagPattern != "" {
+				img.Policy = PolicyMajor
+			}
+			// Containers that declare a policy are checked against it instead of "latest".
+			if img.Policy != "" {
+				update, err := checkPolicy(img, repoName, token)
+				if err != nil {
+					log.Printf("Policy check failed %s: %v", repoName, err)
+					return
+				}
+				if update != nil {
+					mu.Lock()
+					response.ImagestoUpdate = append(response.ImagestoUpdate, update)
+					mu.Unlock()
+				}
+				return
+			}
+
 			// Get the most recent digest for the "latest" tag from the registry.
 			latestDigest, err := getLatestDigest(repoName, token)
 			if err != nil {
@@ -241,6 +260,7 @@ func Monitor(checkforupdates *registry_monitor.CheckUpdatesRequest) (*registry_m
 						truncateDigest(currentDigest),
 						truncateDigest(latestDigest)),
 					Timestamp: time.Now().Unix(),
+					Digest:    latestDigest,
 				}
 
 				// Thread-safe append to response
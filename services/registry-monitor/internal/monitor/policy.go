@@ -0,0 +1,225 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	registry_monitor "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/registry-monitor"
+)
+
+const dockerTagsURL = "https://registry-1.docker.io/v2/%s/tags/list?n=1000"
+
+// Update policies a container can declare.
+const (
+	PolicyPatch  = "patch"
+	PolicyMinor  = "minor"
+	PolicyMajor  = "major"
+	PolicyDigest = "digest"
+)
+
+// tagsResponse is the body of the registry tags/list endpoint.
+type tagsResponse struct {
+	Tags []string `json:"tags"`
+}
+
+// maxTagPages bounds how many pages of tags are fetched for one repository.
+const maxTagPages = 100
+
+// listTags fetches the tags of a Docker Hub repository, following the Link header of each
+// page to the next one.
+func listTags(repository, token string) ([]string, error) {
+	var all []string
+	next := fmt.Sprintf(dockerTagsURL, repository)
+	for page := 0; next != ""; page++ {
+		if page == maxTagPages {
+			log.Printf("Stopped listing tags of %s after %d pages", repository, maxTagPages)
+			break
+		}
+		tags, link, err := listTagsPage(repository, next, token)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, tags...)
+		next = link
+	}
+	return all, nil
+}
+
+// listTagsPage fetches one page of tags and returns the URL of the next page, or "" on the last one.
+func listTagsPage(repository, pageURL, token string) ([]string, string, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create tags request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to execute tags request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("tags request for '%s' failed with status: %s", repository, resp.Status)
+	}
+	var tags tagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, "", fmt.Errorf("failed to decode tags: %w", err)
+	}
+	return tags.Tags, nextPage(req.URL, resp.Header.Get("Link")), nil
+}
+
+// nextPage extracts the rel="next" target of a Link header such as
+// `</v2/library/nginx/tags/list?last=1.25&n=1000>; rel="next"`, resolved against the request URL.
+func nextPage(base *url.URL, link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+		target = strings.Trim(strings.TrimSpace(target), "<>")
+		ref, err := url.Parse(target)
+		if err != nil {
+			return ""
+		}
+		return base.ResolveReference(ref).String()
+	}
+	return ""
+}
+
+// checkPolicy looks for an update allowed by the image's policy. It returns nil when the image is up to date.
+func checkPolicy(img *registry_monitor.ImageInfo, repoName, token string) (*registry_monitor.ImagetoUpdate, error) {
+	if img.Policy == PolicyDigest {
+		if img.TagPattern != "" {
+			log.Printf("Ignoring tag pattern %q of %s: the digest policy keeps the tag", img.TagPattern, repoName)
+		}
+		return checkDigest(img, repoName, token)
+	}
+
+	current, ok := parseVersion(img.Tag)
+	if !ok {
+		return nil, fmt.Errorf("tag %q is not a version, cannot apply %s policy", img.Tag, img.Policy)
+	}
+	var pattern *regexp.Regexp
+	if img.TagPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(img.TagPattern); err != nil {
+			return nil, fmt.Errorf("invalid tag pattern %q: %w", img.TagPattern, err)
+		}
+	}
+	tags, err := listTags(repoName, token)
+	if err != nil {
+		return nil, err
+	}
+
+	bestTag, best := "", current
+	for _, tag := range tags {
+		if pattern != nil && !pattern.MatchString(tag) {
+			continue
+		}
+		v, ok := parseVersion(tag)
+		if !ok || !v.newerThan(best) || !current.allows(v, img.Policy) {
+			continue
+		}
+		bestTag, best = tag, v
+	}
+	if bestTag == "" {
+		log.Printf("No %s update for %s:%s", img.Policy, repoName, img.Tag)
+		return nil, nil
+	}
+
+	digest, err := getCurrentDigest(repoName, bestTag, token)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Update found %s %s -> %s (%s)", repoName, img.Tag, bestTag, img.Policy)
+	return &registry_monitor.ImagetoUpdate{
+		ContainerUid: img.ContainerUid,
+		NewTag:       fmt.Sprintf("%s:%s", img.Repository, bestTag),
+		Description:  fmt.Sprintf("%s update available for %s: %s -> %s", img.Policy, img.Repository, img.Tag, bestTag),
+		Timestamp:    time.Now().Unix(),
+		Digest:       digest,
+	}, nil
+}
+
+// checkDigest reports an update when the image's tag now points at a different digest than the running one.
+func checkDigest(img *registry_monitor.ImageInfo, repoName, token string) (*registry_monitor.ImagetoUpdate, error) {
+	if img.CurrentDigest == "" {
+		return nil, fmt.Errorf("running digest of %s:%s unknown, cannot apply digest policy", repoName, img.Tag)
+	}
+	remote, err := getCurrentDigest(repoName, img.Tag, token)
+	if err != nil {
+		return nil, err
+	}
+	if remote == img.CurrentDigest {
+		log.Printf("No update needed %s:%s digest=%s", repoName, img.Tag, truncateDigest(remote))
+		return nil, nil
+	}
+	log.Printf("Update found %s:%s current=%s latest=%s", repoName, img.Tag, truncateDigest(img.CurrentDigest), truncateDigest(remote))
+	return &registry_monitor.ImagetoUpdate{
+		ContainerUid: img.ContainerUid,
+		NewTag:       fmt.Sprintf("%s:%s", img.Repository, img.Tag),
+		Description: fmt.Sprintf("New digest for %s:%s. Current: %s, New: %s",
+			img.Repository, img.Tag, truncateDigest(img.CurrentDigest), truncateDigest(remote)),
+		Timestamp: time.Now().Unix(),
+		Digest:    remote,
+	}, nil
+}
+
+// version is a release tag such as "1.25", "v2.3.1" or "1.25.3-alpine".
+type version struct {
+	parts  [3]int
+	suffix string // e.g. "-alpine"; candidates must keep the suffix of the running tag
+}
+
+func parseVersion(tag string) (version, bool) {
+	var v version
+	s := strings.TrimPrefix(tag, "v")
+	if i := strings.Index(s, "-"); i >= 0 {
+		s, v.suffix = s[:i], s[i:]
+	}
+	fields := strings.Split(s, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return version{}, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.parts[i] = n
+	}
+	return v, true
+}
+
+func (v version) newerThan(o version) bool {
+	for i := range v.parts {
+		if v.parts[i] != o.parts[i] {
+			return v.parts[i] > o.parts[i]
+		}
+	}
+	return false
+}
+
+// allows reports whether moving from v to candidate is permitted by the policy.
+func (v version) allows(candidate version, policy string) bool {
+	if candidate.suffix != v.suffix {
+		return false
+	}
+	switch policy {
+	case PolicyPatch:
+		return candidate.parts[0] == v.parts[0] && candidate.parts[1] == v.parts[1]
+	case PolicyMinor:
+		return candidate.parts[0] == v.parts[0]
+	case PolicyMajor:
+		return true
+	}
+	return false
+}
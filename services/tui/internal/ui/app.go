@@ -29,8 +29,8 @@ type App struct {
 	cancel        context.CancelFunc
 	dataMu        sync.RWMutex
 	hostsData     []Host
-	containersMap map[string][]Container // mac -> containers
-	nameToMAC     map[string]string      // hostname -> mac
+	containersMap map[string][]Container // host ID -> containers
+	outdatedOnly  bool                   // containers panel lists outdated containers of all hosts
 }
 
 // NewApp creates and initializes the TUI application and its layout.
@@ -40,7 +40,6 @@ func NewApp(client tui.TUIServiceClient, conn *grpc.ClientConn) *App {
 		client:        client,
 		conn:          conn,
 		containersMap: make(map[string][]Container),
-		nameToMAC:     make(map[string]string),
 	}
 
 	// Apply global theme before building components
@@ -66,19 +65,21 @@ func NewApp(client tui.TUIServiceClient, conn *grpc.ClientConn) *App {
 	app.credits = NewCreditWidget("MadhavKrishanGoswami", "Goswamimadhav24")
 
 	// Host selection -> update containers from realtime map
-	app.hosts.SetHostSelectedFunc(func(hostName string) {
-		app.dataMu.RLock()
-		mac := app.nameToMAC[hostName]
-		containers := app.containersMap[mac]
-		app.dataMu.RUnlock()
-		app.containers.Update(containers)
+	app.hosts.SetHostSelectedFunc(func(hostID string) {
+		app.outdatedOnly = false
+		app.refreshContainers()
+	})
+
+	// Host check -> run CheckNow scoped to the selected host
+	app.hosts.SetCheckNowFunc(func(host Host, dryRun bool) {
+		app.OnCheckNow(host.ID, "", host.Name, dryRun)
 	})
 
 	// Setup layout
 	app.setupLayout()
 
 	// Initial placeholder state
-	app.hosts.Update([]Host{{Name: "(connecting...)", IP: "-", MACAddress: "-", LastHeartbeat: time.Now()}})
+	app.hosts.Update([]Host{{Name: "(connecting...)", IP: "-", MACAddress: "-", LastHeartbeat: time.Now(), Online: true}})
 	app.servicesStatus.Update(Service{})
 	app.SetFocus(app.hosts)
 
@@ -87,6 +88,10 @@ func NewApp(client tui.TUIServiceClient, conn *grpc.ClientConn) *App {
 
 	// Global key handler
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// let text fields (e.g. the update prompt) receive digits
+		if _, ok := app.GetFocus().(*tview.InputField); ok {
+			return event
+		}
 		switch event.Rune() {
 		case '1':
 			app.SetFocus(app.hosts)
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tui "github.com/MadhavKrishanGoswami/Lighthouse/services/common/genproto/tui"
+)
+
+// OnCheckNow asks the orchestrator to check for updates immediately. Leave hostID and
+// containerUID empty to check the whole fleet. With dryRun set only the plan is shown.
+func (a *App) OnCheckNow(hostID, containerUID, label string, dryRun bool) {
+	go func() {
+		if a.client == nil {
+			return
+		}
+		verb := "Checking"
+		if dryRun {
+			verb = "Planning"
+		}
+		a.logs.AddLog(fmt.Sprintf("[yellow]%s updates for %s...", verb, label))
+		resp, err := a.client.CheckNow(context.Background(), &tui.CheckNowRequest{
+			HostId:       hostID,
+			ContainerUid: containerUID,
+			DryRun:       dryRun,
+		})
+		if err != nil {
+			a.logs.AddLog("[red]CheckNow failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		if len(resp.Plan) == 0 {
+			a.logs.AddLog("[green]No updates available for " + label)
+			return
+		}
+		for _, p := range resp.Plan {
+			a.logs.AddLog(formatPlannedUpdate(p, dryRun))
+		}
+	}()
+}
+
+// formatPlannedUpdate renders a single plan entry as a colored log line.
+func formatPlannedUpdate(p *tui.PlannedUpdate, dryRun bool) string {
+	target := fmt.Sprintf("%s/%s %s -> %s", p.Hostname, p.ContainerName, p.CurrentImage, p.NewImage)
+	switch {
+	case p.SkipReason != "":
+		return fmt.Sprintf("[orange]skip %s (%s)", target, p.SkipReason)
+	case p.Dispatched:
+		return "[green]update sent " + target
+	case dryRun:
+		return "[white]would update " + target
+	default:
+		return "[white]update " + target
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -11,11 +12,52 @@ import (
 )
 
 type Container struct {
+	UID        string
+	HostID     string
+	HostName   string
 	Name       string
 	Image      string
 	Status     string
+	Health     string // Docker health: starting, healthy or unhealthy; empty without a HEALTHCHECK
 	IsWatching bool
 	IsUpdating bool
+	Denial     string    // reason the latest admission review denied an update, if any
+	Stage      string    // latest update stage reported by the agent, e.g. PULLING or COMPLETED
+	StageLogs  string    // log message of the latest stage
+	StageAt    time.Time // when the latest stage was reported
+	// PreviousImage is the image a rollback would restore, empty when unknown.
+	PreviousImage string
+	Pinned        bool // automatic updates are suspended
+	// Quarantined is the image that is skipped after repeated failed updates, if any.
+	Quarantined string
+	Failures    int32
+	Policy      string // update policy from labels and the policy file, e.g. "minor, 02:00-05:00"
+	WatchSource string // "default", "label", "file" or "tui"
+	Drift       string // set when the watch state set in the TUI contradicts the policy file
+	// Available is the newest image a check found, applied or not, and AvailableAt when it was first found.
+	Available   string
+	AvailableAt time.Time
+	Project     string // Docker Compose project, if any
+}
+
+// Update stages are shown in the update column while in progress and for stageDisplayTTL once finished.
+const (
+	stageDisplayTTL = time.Hour
+	stageStaleAfter = 30 * time.Minute // in-progress stages older than this are assumed lost
+)
+
+// stageInProgress reports whether the stage belongs to an update that has not finished yet.
+func stageInProgress(stage string) bool {
+	switch stage {
+	case "QUEUED", "PULLING", "PREFLIGHT", "HOOK", "STARTING", "RUNNING", "HEALTH_CHECK":
+		return true
+	}
+	return false
+}
+
+// updatingFromStage reports whether a container is being updated according to its latest stage.
+func updatingFromStage(stage string, at time.Time) bool {
+	return stageInProgress(stage) && time.Since(at) < stageStaleAfter
 }
 
 type ContainersPanel struct {
@@ -45,7 +87,7 @@ func NewContainersPanel(app *App) *ContainersPanel {
 	// Handle selection font-color only
 	cp.SetSelectionChangedFunc(func(row, column int) {
 		for r := 1; r <= len(cp.containers); r++ {
-			for c := 0; c < 4; c++ {
+			for c := 0; c < 7; c++ {
 				cell := cp.GetCell(r, c)
 				if r == row {
 					cell.SetTextColor(tcell.ColorLightBlue)
@@ -65,7 +107,7 @@ func (cp *ContainersPanel) Update(containers []Container) {
 	cp.Clear()
 	cp.SetFixed(1, 0)
 
-	headers := []string{"Name", "Image", "Status", "Watching"}
+	headers := []string{"Name", "Image", "Status", "Watching", "Policy", "Available", "Update"}
 	for i, h := range headers {
 		cp.SetCell(0, i, tview.NewTableCell(h).
 			SetTextColor(Theme.TitleColor).
@@ -83,7 +125,14 @@ func (cp *ContainersPanel) drawRow(row int, c Container) {
 	if c.IsUpdating {
 		textColor = Theme.AccentWarningColor
 	}
-	cp.SetCell(row, 0, tview.NewTableCell(c.Name).SetTextColor(textColor).SetExpansion(1))
+	name := c.Name
+	if cp.app != nil && cp.app.outdatedOnly {
+		name = c.HostName + "/" + c.Name
+	}
+	if c.Project != "" {
+		name += " [" + c.Project + "]"
+	}
+	cp.SetCell(row, 0, tview.NewTableCell(name).SetTextColor(textColor).SetExpansion(1))
 	imageName := c.Image
 	if len(imageName) > 30 {
 		imageName = imageName[:27] + "..."
@@ -93,17 +142,94 @@ func (cp *ContainersPanel) drawRow(row int, c Container) {
 	if strings.Contains(strings.ToLower(c.Status), "running") {
 		statusColor = Theme.AccentGoodColor
 	}
-	if c.IsUpdating {
+	if c.Health == "unhealthy" || c.IsUpdating {
 		statusColor = Theme.AccentWarningColor
 	}
-	cp.SetCell(row, 2, tview.NewTableCell(c.Status).SetTextColor(statusColor).SetAlign(tview.AlignCenter).SetExpansion(1))
+	status := c.Status
+	if c.Health != "" {
+		status += " (" + c.Health + ")"
+	}
+	cp.SetCell(row, 2, tview.NewTableCell(status).SetTextColor(statusColor).SetAlign(tview.AlignCenter).SetExpansion(1))
 	watchText := "No"
 	watchColor := textColor
 	if c.IsWatching {
 		watchText = "Yes"
 		watchColor = Theme.AccentWarningColor
 	}
+	if c.WatchSource != "" && c.WatchSource != "default" {
+		watchText += " (" + c.WatchSource + ")"
+	}
+	if c.Drift != "" {
+		watchText += " drift"
+		watchColor = Theme.AccentErrorColor
+	}
 	cp.SetCell(row, 3, tview.NewTableCell(watchText).SetTextColor(watchColor).SetAlign(tview.AlignCenter))
+	policyText := c.Policy
+	if policyText == "" {
+		policyText = "-"
+	}
+	cp.SetCell(row, 4, tview.NewTableCell(truncate(policyText, 24)).SetTextColor(Theme.SecondaryTextColor).SetAlign(tview.AlignCenter))
+	availableText, availableColor := availableCell(c)
+	cp.SetCell(row, 5, tview.NewTableCell(availableText).SetTextColor(availableColor).SetAlign(tview.AlignCenter))
+	updateText, updateColor := updateCell(c)
+	cp.SetCell(row, 6, tview.NewTableCell(updateText).SetTextColor(updateColor).SetAlign(tview.AlignCenter).SetExpansion(1))
+}
+
+// availableCell returns the text and color for the available update column, e.g. "1.25.3 (2h ago)".
+func availableCell(c Container) (string, tcell.Color) {
+	if c.Available == "" {
+		return "-", Theme.SecondaryTextColor
+	}
+	tag := c.Available
+	if i := strings.LastIndex(tag, ":"); i > strings.LastIndex(tag, "/") {
+		tag = tag[i+1:]
+	}
+	return truncate(tag, 16) + " (" + formatHeartbeat(c.AvailableAt) + ")", Theme.AccentWarningColor
+}
+
+// updateCell returns the text and color for the update column of a container row.
+func updateCell(c Container) (string, tcell.Color) {
+	if c.IsUpdating {
+		return stageLabel(c.Stage) + "...", Theme.AccentWarningColor
+	}
+	if c.Denial != "" {
+		return truncate("Denied: "+c.Denial, 30), Theme.AccentErrorColor
+	}
+	if c.Quarantined != "" {
+		return fmt.Sprintf("Quarantined (%d failures)", c.Failures), Theme.AccentErrorColor
+	}
+	if c.Pinned {
+		return "Pinned", Theme.AccentWarningColor
+	}
+	if c.Stage != "" && !stageInProgress(c.Stage) && time.Since(c.StageAt) < stageDisplayTTL {
+		switch c.Stage {
+		case "COMPLETED":
+			return "Updated", Theme.AccentGoodColor
+		case "ROLLBACK":
+			return "Rolled back", Theme.AccentErrorColor
+		case "CANCELLED":
+			return "Cancelled", Theme.AccentWarningColor
+		case "FAILED":
+			return truncate("Failed: "+c.StageLogs, 30), Theme.AccentErrorColor
+		}
+	}
+	return "-", Theme.SecondaryTextColor
+}
+
+// stageLabel turns an update stage such as HEALTH_CHECK into "Health check".
+func stageLabel(stage string) string {
+	if stage == "" {
+		return ""
+	}
+	s := strings.ToLower(strings.ReplaceAll(stage, "_", " "))
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n-3] + "..."
+	}
+	return s
 }
 
 func (cp *ContainersPanel) restoreCellColor(row, col int) {
@@ -123,7 +249,7 @@ func (cp *ContainersPanel) restoreCellColor(row, col int) {
 		if strings.Contains(strings.ToLower(c.Status), "running") {
 			color = Theme.AccentGoodColor
 		}
-		if c.IsUpdating {
+		if c.Health == "unhealthy" || c.IsUpdating {
 			color = Theme.AccentWarningColor
 		}
 		cp.GetCell(row, col).SetTextColor(color)
@@ -132,11 +258,26 @@ func (cp *ContainersPanel) restoreCellColor(row, col int) {
 		if c.IsWatching || c.IsUpdating {
 			color = Theme.AccentWarningColor
 		}
+		if c.Drift != "" {
+			color = Theme.AccentErrorColor
+		}
+		cp.GetCell(row, col).SetTextColor(color)
+	case 4:
+		cp.GetCell(row, col).SetTextColor(Theme.SecondaryTextColor)
+	case 5:
+		_, color := availableCell(c)
+		cp.GetCell(row, col).SetTextColor(color)
+	case 6:
+		_, color := updateCell(c)
 		cp.GetCell(row, col).SetTextColor(color)
 	}
 }
 
 func (cp *ContainersPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if (event.Rune() == 'o' || event.Rune() == 'O') && cp.app != nil {
+		cp.app.ToggleOutdated()
+		return nil
+	}
 	row, _ := cp.GetSelection()
 	if row <= 0 || row-1 >= len(cp.containers) {
 		return event
@@ -146,35 +287,270 @@ func (cp *ContainersPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	switch event.Rune() {
 	case 'w', 'W':
 		c.IsWatching = !c.IsWatching
+		c.WatchSource = "tui"
 		cp.drawRow(row, *c)
 		if cp.app != nil {
 			cp.app.OnWatchToggle(*c)
 		}
 		return nil
+	case 'c', 'C':
+		if cp.app != nil {
+			cp.app.OnCheckNow("", c.UID, c.Name, false)
+		}
+		return nil
+	case 'p', 'P':
+		if cp.app != nil {
+			cp.app.OnCheckNow("", c.UID, c.Name, true)
+		}
+		return nil
 	case 'u', 'U':
-		if c.IsUpdating {
+		if c.IsUpdating || cp.app == nil {
 			return nil
 		}
-		c.IsUpdating = true
-		cp.drawRow(row, *c)
-		go func(container *Container, r int) {
-			if cp.app != nil {
-				cp.app.OnUpdateContainer(*container)
-			}
-			time.Sleep(2 * time.Second)
-			cp.app.QueueUpdateDraw(func() {
-				container.IsUpdating = false
-				cp.drawRow(r, *container)
-			})
-		}(c, row)
+		cp.app.promptUpdateTarget(*c)
+		return nil
+	case 'r', 'R':
+		if c.IsUpdating || cp.app == nil {
+			return nil
+		}
+		cp.app.promptRollback(*c)
+		return nil
+	case 'l', 'L':
+		if cp.app != nil {
+			cp.app.OnPinToggle(*c)
+		}
+		return nil
+	case 'x', 'X':
+		if cp.app != nil && c.Quarantined != "" {
+			cp.app.OnClearQuarantine(*c)
+		}
+		return nil
+	case 'k', 'K':
+		if cp.app != nil && c.IsUpdating {
+			cp.app.OnCancelUpdate(*c)
+		}
+		return nil
+	case 's', 'S':
+		if cp.app != nil && c.Project != "" {
+			cp.app.promptProjectUpdate(*c)
+		}
 		return nil
 	}
 	return event
 }
 
-func (a *App) OnUpdateContainer(c Container) {
-	// Placeholder for future RPC
-	c.IsUpdating = false
+// promptRollback asks for confirmation before rolling the container back to its previous image.
+func (a *App) promptRollback(c Container) {
+	if c.PreviousImage == "" {
+		a.logs.AddLog("[red]No previous version recorded for " + c.Name)
+		return
+	}
+	modal := tview.NewModal().
+		SetText("Roll back " + c.Name + " to " + c.PreviousImage + "?").
+		AddButtons([]string{"Rollback", "Rollback & pin", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			a.SetRoot(a.root, true)
+			a.SetFocus(a.containers)
+			switch label {
+			case "Rollback":
+				a.OnRollbackContainer(c, false)
+			case "Rollback & pin":
+				a.OnRollbackContainer(c, true)
+			}
+		})
+	a.SetRoot(modal, true)
+}
+
+// promptProjectUpdate asks for confirmation before updating the compose project of the container.
+func (a *App) promptProjectUpdate(c Container) {
+	modal := tview.NewModal().
+		SetText("Update all services of compose project " + c.Project + " on " + c.HostName + "?\nA failed service rolls the whole project back.").
+		AddButtons([]string{"Update project", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			a.SetRoot(a.root, true)
+			a.SetFocus(a.containers)
+			if label == "Update project" {
+				a.OnUpdateProject(c.HostID, c.Project)
+			}
+		})
+	a.SetRoot(modal, true)
+}
+
+// OnUpdateProject asks the orchestrator to update a compose project in dependency order.
+func (a *App) OnUpdateProject(hostID, project string) {
+	go func() {
+		if a.client == nil {
+			return
+		}
+		resp, err := a.client.UpdateProject(context.Background(), &tui.UpdateProjectRequest{HostId: hostID, Project: project})
+		if err != nil {
+			a.logs.AddLog("[red]UpdateProject failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		a.logs.AddLog("[green]" + resp.Message)
+	}()
+}
+
+// OnRollbackContainer asks the orchestrator to restore the container's previous image.
+func (a *App) OnRollbackContainer(c Container, pin bool) {
+	a.markUpdating(c.UID)
+	go func() {
+		if a.client == nil {
+			return
+		}
+		hostID := c.HostID
+		resp, err := a.client.RollbackContainer(context.Background(), &tui.RollbackContainerRequest{HostId: hostID, ContainerUid: c.UID, Pin: pin})
+		if err != nil {
+			a.logs.AddLog("[red]RollbackContainer failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		a.logs.AddLog("[green]Rolling back " + c.Name + " to " + resp.Image)
+	}()
+}
+
+// OnClearQuarantine lifts the quarantine so the failed image is tried again on the next check.
+func (a *App) OnClearQuarantine(c Container) {
+	go func(cont Container) {
+		if a.client == nil {
+			return
+		}
+		hostID := cont.HostID
+		resp, err := a.client.ClearQuarantine(context.Background(), &tui.ClearQuarantineRequest{HostId: hostID, ContainerUid: cont.UID})
+		if err != nil {
+			a.logs.AddLog("[red]ClearQuarantine failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		a.logs.AddLog("[green]Quarantine cleared for " + cont.Name)
+	}(c)
+}
+
+// OnCancelUpdate aborts the queued or running update of the container.
+func (a *App) OnCancelUpdate(c Container) {
+	go func(cont Container) {
+		if a.client == nil {
+			return
+		}
+		hostID := cont.HostID
+		resp, err := a.client.CancelUpdate(context.Background(), &tui.CancelUpdateRequest{HostId: hostID, ContainerUid: cont.UID})
+		if err != nil {
+			a.logs.AddLog("[red]CancelUpdate failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		a.logs.AddLog("[yellow]Cancelling the update of " + cont.Name)
+	}(c)
+}
+
+// OnPinToggle pins or unpins the container; pinned containers are skipped by automatic updates.
+func (a *App) OnPinToggle(c Container) {
+	go func(cont Container) {
+		if a.client == nil {
+			return
+		}
+		hostID := cont.HostID
+		resp, err := a.client.SetPin(context.Background(), &tui.SetPinRequest{HostId: hostID, ContainerUid: cont.UID, Pinned: !cont.Pinned})
+		if err != nil {
+			a.logs.AddLog("[red]SetPin failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		if cont.Pinned {
+			a.logs.AddLog("[green]Unpinned " + cont.Name)
+		} else {
+			a.logs.AddLog("[green]Pinned " + cont.Name)
+		}
+	}(c)
+}
+
+// promptUpdateTarget asks for an optional tag or digest and then forces an update of the container.
+func (a *App) promptUpdateTarget(c Container) {
+	form := tview.NewForm()
+	form.AddInputField("Tag or digest", "", 40, nil, nil)
+	closeForm := func() {
+		a.SetRoot(a.root, true)
+		a.SetFocus(a.containers)
+	}
+	form.AddButton("Update", func() {
+		target := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		closeForm()
+		a.OnUpdateContainer(c, target)
+	})
+	form.AddButton("Cancel", closeForm)
+	form.SetCancelFunc(closeForm)
+	form.SetBorder(true).
+		SetTitle(" Update " + c.Name + " (empty = re-pull current image) ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(Theme.BorderColor).
+		SetTitleColor(Theme.TitleColor)
+
+	// center the form on top of the dashboard
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 7, 0, true).
+			AddItem(nil, 0, 1, false), 70, 0, true).
+		AddItem(nil, 0, 1, false)
+	a.SetRoot(modal, true)
+	a.SetFocus(form)
+}
+
+// OnUpdateContainer asks the orchestrator to update the container. target is an optional
+// tag or "sha256:" digest; progress is shown in the update column as the agent reports it.
+func (a *App) OnUpdateContainer(c Container, target string) {
+	a.markUpdating(c.UID)
+	go func() {
+		if a.client == nil {
+			return
+		}
+		hostID := c.HostID
+		req := &tui.UpdateContainerRequest{HostId: hostID, ContainerUid: c.UID}
+		if strings.HasPrefix(target, "sha256:") {
+			req.Digest = target
+		} else {
+			req.Tag = target
+		}
+		resp, err := a.client.UpdateContainer(context.Background(), req)
+		if err != nil {
+			a.logs.AddLog("[red]UpdateContainer failed: " + err.Error())
+			return
+		}
+		if !resp.Success {
+			a.logs.AddLog("[red]" + resp.Message)
+			return
+		}
+		a.logs.AddLog("[green]Updating " + c.Name + " to " + resp.Image)
+	}()
+}
+
+// markUpdating shows the container as queued until the next snapshot reports the real stage.
+func (a *App) markUpdating(uid string) {
+	for i := range a.containers.containers {
+		c := &a.containers.containers[i]
+		if c.UID != uid {
+			continue
+		}
+		c.Stage, c.StageAt, c.IsUpdating = "QUEUED", time.Now(), true
+		a.containers.drawRow(i+1, *c)
+	}
 }
 
 func (a *App) OnWatchToggle(c Container) {
@@ -182,10 +558,8 @@ func (a *App) OnWatchToggle(c Container) {
 		if a.client == nil {
 			return
 		}
-		a.dataMu.RLock()
-		mac := a.nameToMAC[a.hosts.selectedHostName]
-		a.dataMu.RUnlock()
-		_, err := a.client.SetWatch(context.Background(), &tui.SetWatchlistRequest{ContainerName: cont.Name, HostMac: mac, Watch: cont.IsWatching})
+		hostID := cont.HostID
+		_, err := a.client.SetWatch(context.Background(), &tui.SetWatchlistRequest{ContainerName: cont.Name, HostId: hostID, Watch: cont.IsWatching})
 		if err != nil {
 			a.logs.AddLog("[red]SetWatch failed: " + err.Error())
 		} else {
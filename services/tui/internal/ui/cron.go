@@ -36,6 +36,10 @@ func NewCronWidget(app *App) *CronWidget {
 			w.adjustInterval(1 * time.Hour)
 		case '-':
 			w.adjustInterval(-1 * time.Hour)
+		case 'c', 'C':
+			app.OnCheckNow("", "", "all hosts", false)
+		case 'p', 'P':
+			app.OnCheckNow("", "", "all hosts", true)
 		}
 		return event
 	})
@@ -63,7 +67,7 @@ func (cw *CronWidget) adjustInterval(delta time.Duration) {
 
 func (cw *CronWidget) updateIntervalText() {
 	hours := int(cw.duration.Hours())
-	text := fmt.Sprintf("\n[green]%2d Hour(s)\n\n [yellow](Use + / - to adjust)\n", hours)
+	text := fmt.Sprintf("\n[green]%2d Hour(s)\n\n [yellow](Use + / - to adjust)\n [yellow](c: check now, p: plan)\n", hours)
 	cw.intervalView.SetText(text)
 }
 
@@ -10,19 +10,22 @@ import (
 
 // Host represents the data for a single host machine.
 type Host struct {
+	ID            string // agent ID, identifies the host in requests
 	Name          string
 	IP            string
 	MACAddress    string
 	LastHeartbeat time.Time
+	Online        bool // the agent is connected
 }
 
 // HostsPanel represents the TUI component that displays a list of hosts.
 type HostsPanel struct {
 	*tview.Table
-	hosts            []Host
-	onHostSelected   func(hostName string)
-	selectedHostName string
-	showMAC          bool // toggle for MAC visibility
+	hosts          []Host
+	onHostSelected func(hostID string)
+	onCheckNow     func(host Host, dryRun bool)
+	selectedHostID string
+	showMAC        bool // toggle for MAC visibility
 }
 
 // NewHostsPanel creates a new panel for displaying hosts.
@@ -52,7 +55,7 @@ func NewHostsPanel() *HostsPanel {
 				if r == row {
 					cell.SetTextColor(tcell.ColorLightBlue) // selected font
 				} else {
-					cell.SetTextColor(hp.getDefaultColor(c))
+					cell.SetTextColor(hp.getDefaultColor(r, c))
 				}
 			}
 		}
@@ -66,6 +69,13 @@ func NewHostsPanel() *HostsPanel {
 			hp.showMAC = !hp.showMAC
 			hp.Update(hp.hosts)
 			return nil
+		case 'c', 'C', 'p', 'P':
+			row, _ := hp.GetSelection()
+			if row > 0 && row-1 < len(hp.hosts) && hp.onCheckNow != nil {
+				dryRun := event.Rune() == 'p' || event.Rune() == 'P'
+				hp.onCheckNow(hp.hosts[row-1], dryRun)
+			}
+			return nil
 		}
 		return event
 	})
@@ -84,8 +94,12 @@ func (hp *HostsPanel) Update(hosts []Host) {
 	for i, host := range hosts {
 		row := i + 1
 
-		hp.SetCell(row, 0, tview.NewTableCell(host.Name).
-			SetTextColor(tcell.ColorWhite).
+		name := host.Name
+		if !host.Online {
+			name += " (offline)"
+		}
+		hp.SetCell(row, 0, tview.NewTableCell(name).
+			SetTextColor(hp.getDefaultColor(row, 0)).
 			SetAlign(tview.AlignLeft).
 			SetExpansion(expansions[0]))
 
@@ -119,25 +133,33 @@ func (hp *HostsPanel) Update(hosts []Host) {
 }
 
 // SetHostSelectedFunc sets the callback function when a host is selected.
-func (hp *HostsPanel) SetHostSelectedFunc(handler func(hostName string)) {
+func (hp *HostsPanel) SetHostSelectedFunc(handler func(hostID string)) {
 	hp.onHostSelected = handler
 }
 
+// SetCheckNowFunc sets the callback used to check a host for updates ('c') or preview the plan ('p').
+func (hp *HostsPanel) SetCheckNowFunc(handler func(host Host, dryRun bool)) {
+	hp.onCheckNow = handler
+}
+
 // handleSelectionChange triggers when the user navigates the table.
 func (hp *HostsPanel) handleSelectionChange(row, column int) {
 	if row > 0 && row-1 < len(hp.hosts) && hp.onHostSelected != nil {
 		selectedHost := hp.hosts[row-1]
-		if selectedHost.Name != hp.selectedHostName {
-			hp.selectedHostName = selectedHost.Name
-			hp.onHostSelected(selectedHost.Name)
+		if selectedHost.ID != hp.selectedHostID {
+			hp.selectedHostID = selectedHost.ID
+			hp.onHostSelected(selectedHost.ID)
 		}
 	}
 }
 
 // Helper: returns default color for a column
-func (hp *HostsPanel) getDefaultColor(column int) tcell.Color {
+func (hp *HostsPanel) getDefaultColor(row, column int) tcell.Color {
 	switch column {
 	case 0:
+		if row > 0 && row-1 < len(hp.hosts) && !hp.hosts[row-1].Online {
+			return tcell.ColorGray
+		}
 		return tcell.ColorWhite
 	case 1:
 		return tcell.ColorGreen
@@ -8,6 +8,7 @@ func fetchMockHosts() []Host {
 			Name:          "Madhav-MacBookPro",
 			IP:            "192.168.1.10",
 			LastHeartbeat: time.Now(),
+			Online:        true,
 			MACAddress:    "00:1A:2B:3C:4D:5E",
 		},
 		{
@@ -0,0 +1,37 @@
+package ui
+
+import "sort"
+
+// ToggleOutdated switches the containers panel between the selected host and the
+// outdated containers of the whole fleet.
+func (a *App) ToggleOutdated() {
+	a.outdatedOnly = !a.outdatedOnly
+	a.refreshContainers()
+}
+
+// refreshContainers redraws the containers panel for the current view. It must run on the UI goroutine.
+func (a *App) refreshContainers() {
+	a.dataMu.RLock()
+	defer a.dataMu.RUnlock()
+	if !a.outdatedOnly {
+		a.containers.SetTitle("[2] Containers ")
+		if selected := a.hosts.selectedHostID; selected != "" {
+			a.containers.Update(a.containersMap[selected])
+		}
+		return
+	}
+	var outdated []Container
+	for _, containers := range a.containersMap {
+		for _, c := range containers {
+			if c.Available != "" {
+				outdated = append(outdated, c)
+			}
+		}
+	}
+	// oldest detections first, they have been waiting longest
+	sort.Slice(outdated, func(i, j int) bool {
+		return outdated[i].AvailableAt.Before(outdated[j].AvailableAt)
+	})
+	a.containers.SetTitle("[2] Outdated containers, all hosts (o: back) ")
+	a.containers.Update(outdated)
+}
@@ -78,26 +78,56 @@ func (a *App) handleSnapshot(msg *tui.DataStreamSend) {
 	}
 	var hosts []Host
 	containersMap := make(map[string][]Container)
-	nameToMAC := make(map[string]string)
 	if msg.HostList != nil {
 		for _, h := range msg.HostList.Hosts {
 			if h == nil {
 				continue
 			}
 			tm, _ := time.Parse(time.RFC3339, h.LastHeartbeat)
-			hosts = append(hosts, Host{Name: h.Hostname, IP: h.IpAddress, MACAddress: h.MacAddress, LastHeartbeat: tm})
-			nameToMAC[h.Hostname] = h.MacAddress
+			hosts = append(hosts, Host{ID: h.AgentId, Name: h.Hostname, IP: h.IpAddress, MACAddress: h.MacAddress, LastHeartbeat: tm, Online: h.Online})
 			for _, c := range h.Containers {
 				if c == nil {
 					continue
 				}
-				containersMap[h.MacAddress] = append(containersMap[h.MacAddress], Container{
+				container := Container{
+					UID:        c.ContainerUid,
+					HostID:     h.AgentId,
+					HostName:   h.Hostname,
 					Name:       c.Name,
 					Image:      c.Image,
 					Status:     protoStatusToString(c.Status),
+					Health:     c.Health,
 					IsWatching: c.Watch,
 					IsUpdating: false,
-				})
+
+					PreviousImage: c.PreviousImage,
+					Pinned:        c.Pinned,
+					Policy:        c.Policy,
+					WatchSource:   c.WatchSource,
+					Drift:         c.Drift,
+					Project:       c.ComposeProject,
+				}
+				if u := c.Update; u != nil {
+					container.Stage = u.Stage
+					container.StageLogs = u.Logs
+					container.StageAt, _ = time.Parse(time.RFC3339, u.UpdatedAt)
+					container.IsUpdating = updatingFromStage(container.Stage, container.StageAt)
+				}
+				if u := c.Available; u != nil {
+					container.Available = u.Image
+					container.AvailableAt, _ = time.Parse(time.RFC3339, u.DetectedAt)
+				}
+				if q := c.Quarantine; q != nil {
+					container.Quarantined = q.Image
+					container.Failures = q.Failures
+				}
+				if d := c.LastDenial; d != nil {
+					container.Denial = d.Reason
+					if container.Denial == "" {
+						container.Denial = "by " + d.Webhook
+					}
+				}
+				containersMap[h.AgentId] = append(containersMap[h.AgentId], container)
 			}
 		}
 	}
@@ -118,7 +148,6 @@ func (a *App) handleSnapshot(msg *tui.DataStreamSend) {
 	a.dataMu.Lock()
 	a.hostsData = hosts
 	a.containersMap = containersMap
-	a.nameToMAC = nameToMAC
 	a.dataMu.Unlock()
 
 	a.QueueUpdateDraw(func() {
@@ -127,11 +156,7 @@ func (a *App) handleSnapshot(msg *tui.DataStreamSend) {
 		}
 		a.servicesStatus.Update(svc)
 		a.cron.UpdateTime(cron)
-		selected := a.hosts.selectedHostName
-		if selected != "" {
-			mac := nameToMAC[selected]
-			a.containers.Update(containersMap[mac])
-		}
+		a.refreshContainers()
 	})
 
 	if msg.Logs != "" {